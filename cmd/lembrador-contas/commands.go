@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/oauth_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/password_setup_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/refresh_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/secret"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/user"
+	"github.com/regismartiny/lembrador-contas-go/internal/secret_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/token_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/auth_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/secret_usecase"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ISSUE_PASSWORD_SETUP_TOKEN_COMMAND = "issue-password-setup-token"
+	ROTATE_SECRETS_COMMAND             = "rotate-secrets"
+)
+
+func runCommand(ctx context.Context, database *mongo.Database, args []string) error {
+	switch args[0] {
+	case ISSUE_PASSWORD_SETUP_TOKEN_COMMAND:
+		if len(args) != 2 {
+			return fmt.Errorf("usage: %s <email>", ISSUE_PASSWORD_SETUP_TOKEN_COMMAND)
+		}
+		return issuePasswordSetupToken(ctx, database, args[1])
+	case ROTATE_SECRETS_COMMAND:
+		return rotateSecrets(ctx, database)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// issuePasswordSetupToken prints the password setup token of the user to the operator, who hands it
+// to the user. The token is never written to the log.
+func issuePasswordSetupToken(ctx context.Context, database *mongo.Database, email string) error {
+	tokenService, err := token_service.NewTokenService()
+	if err != nil {
+		return err
+	}
+
+	authUseCase := auth_usecase.NewAuthUseCase(user.NewUserRepository(ctx, database),
+		refresh_token.NewRefreshTokenRepository(ctx, database),
+		password_setup_token.NewPasswordSetupTokenRepository(ctx, database), tokenService)
+
+	setupToken, issueErr := authUseCase.IssuePasswordSetupToken(ctx, email)
+	if issueErr != nil {
+		return issueErr
+	}
+
+	fmt.Printf("Password setup token of %s, valid until %s:\n%s\n",
+		email, setupToken.ExpiresAt.Format("2006-01-02 15:04:05"), setupToken.SetupToken)
+
+	return nil
+}
+
+// rotateSecrets encrypts again, under the current master key, the values of every household still
+// encrypted with a previous key.
+func rotateSecrets(ctx context.Context, database *mongo.Database) error {
+	secretService, err := secret_service.NewSecretService()
+	if err != nil {
+		return err
+	}
+
+	secretRepository := secret.NewSecretRepository(ctx, database, secretService)
+	secretUseCase := secret_usecase.NewSecretUseCase(secretRepository, secretRepository,
+		oauth_token.NewOAuthTokenRepository(ctx, database, secretService))
+
+	output, rotateErr := secretUseCase.RotateSecrets(household_entity.WithoutScope(ctx))
+	if rotateErr != nil {
+		return rotateErr
+	}
+
+	fmt.Printf("Secrets reencrypted: %d\n", output.Reencrypted)
+
+	return nil
+}
@@ -3,32 +3,70 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/regismartiny/lembrador-contas-go/configuration/database/mongodb"
+	"github.com/regismartiny/lembrador-contas-go/internal/blob_storage"
+	"github.com/regismartiny/lembrador-contas-go/internal/calendar"
 	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/attachment_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/auth_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/bill_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/bill_processing_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/category_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/email_value_source_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/gmail_auth_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/holiday_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/household_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/invoice_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/mail_account_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/reconciliation_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/secret_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/settlement_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/table_value_source_controller"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/controller/user_controller"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/middleware"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/attachment"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/bill"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/bill_processing"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/category"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/email_value_source"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/holiday"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/invoice"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/mail_account"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/oauth_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/password_setup_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/refresh_token"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/secret"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/table_value_source"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/user"
 	"github.com/regismartiny/lembrador-contas-go/internal/infra/gmail_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/scheduler"
+	"github.com/regismartiny/lembrador-contas-go/internal/secret_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/token_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/transaction_manager"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/attachment_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/auth_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/bill_processing_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/bill_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/category_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/email_value_source_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/gmail_auth_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/holiday_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/household_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/invoice_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/mail_account_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/reconciliation_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/secret_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/settlement_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/table_value_source_usecase"
 	"github.com/regismartiny/lembrador-contas-go/internal/usecase/user_usecase"
 	"go.mongodb.org/mongo-driver/mongo"
-	"google.golang.org/api/gmail/v1"
 )
 
 func main() {
@@ -47,72 +85,209 @@ func main() {
 		return
 	}
 
-	log.Println("Creating Gmail service...")
-	gmailService, err := gmail_service.NewGmailService()
-	if err != nil {
-		log.Fatal(err.Error())
+	// operator commands run instead of the server
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, databaseConnection, os.Args[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
 		return
 	}
 
 	router := gin.Default()
 
-	deps := initDependencies(ctx, databaseConnection, gmailService)
+	deps, err := initDependencies(ctx, databaseConnection)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
 
-	router.GET("/user", deps.userController.FindUsers)
-	router.GET("/user/:id", deps.userController.FindUserById)
+	router.POST("/auth/login", deps.authController.Login)
+	router.POST("/auth/refresh", deps.authController.Refresh)
+	router.POST("/auth/logout", deps.authController.Logout)
+	router.POST("/auth/password-setup", deps.authController.SetupPassword)
 	router.POST("/user", deps.userController.CreateUser)
-	router.GET("/bill", deps.billController.FindBills)
-	router.GET("/bill/:id", deps.billController.FindBillById)
-	router.POST("/bill", deps.billController.CreateBill)
-	router.GET("/invoice", deps.invoiceControler.FindInvoices)
-	router.GET("/invoice/:id", deps.invoiceControler.FindInvoiceById)
-	router.POST("/invoice", deps.invoiceControler.CreateInvoice)
-	router.GET("/table-value-source", deps.tableValueSourceController.FindTableValueSources)
-	router.GET("/table-value-source/:id", deps.tableValueSourceController.FindTableValueSourceById)
-	router.POST("/table-value-source", deps.tableValueSourceController.CreateTableValueSource)
-	router.PUT("/table-value-source/:id", deps.tableValueSourceController.UpdateTableValueSource)
-	router.GET("/email-value-source", deps.emailValueSourceController.FindEmailValueSources)
-	router.GET("/email-value-source/:id", deps.emailValueSourceController.FindEmailValueSourceById)
-	router.POST("/email-value-source", deps.emailValueSourceController.CreateEmailValueSource)
-	router.PUT("/email-value-source/:id", deps.emailValueSourceController.UpdateEmailValueSource)
-	router.POST("/bill-processing/start", deps.billProcessingController.StartBillProcessing)
-	router.GET("/bill-processing/status/:id", deps.billProcessingController.GetBillProcessingStatus)
-	router.GET("/bill-processing", deps.billProcessingController.FindBillProcessings)
+	// google redirects the browser here, without the access token
+	router.GET("/auth/gmail/callback", deps.gmailAuthController.GmailAuthCallback)
+
+	// every other route needs the access token of the caller
+	authenticated := router.Group("/", deps.authenticated)
+	authenticated.PUT("/user/:id/password", deps.userController.UpdateUserPassword)
+	authenticated.POST("/household", deps.householdController.CreateHousehold)
+	authenticated.GET("/household", deps.householdController.FindHouseholds)
+	authenticated.GET("/household/:id", deps.householdController.FindHouseholdById)
+	authenticated.POST("/household/:id/members", deps.householdController.InviteMember)
+	authenticated.PATCH("/household/:id/members/:memberId", deps.householdController.ChangeMemberRole)
+	authenticated.DELETE("/household/:id/members/:memberId", deps.householdController.RemoveMember)
+	authenticated.POST("/household/:id/accept", deps.householdController.AcceptInvitation)
+
+	// the records below belong to the household of the caller, and only its members are listed
+	scoped := authenticated.Group("/", deps.householdScope)
+	scoped.GET("/user", deps.userController.FindUsers)
+	scoped.GET("/user/:id", deps.userController.FindUserById)
+	scoped.GET("/bill", deps.billController.FindBills)
+	scoped.GET("/bill/:id", deps.billController.FindBillById)
+	scoped.POST("/bill", deps.billController.CreateBill)
+	scoped.PUT("/bill/:id", deps.billController.UpdateBill)
+	scoped.PATCH("/bill/:id/status", deps.billController.UpdateBillStatus)
+	scoped.DELETE("/bill/:id", deps.billController.DeleteBill)
+	scoped.POST("/bill/:id/suspensions", deps.billController.AddBillSuspension)
+	scoped.DELETE("/bill/:id/suspensions/:suspensionId", deps.billController.DeleteBillSuspension)
+	scoped.GET("/invoice", deps.invoiceControler.FindInvoices)
+	scoped.GET("/invoice/:id", deps.invoiceControler.FindInvoiceById)
+	scoped.POST("/invoice", deps.invoiceControler.CreateInvoice)
+	scoped.POST("/invoice/pay", deps.invoiceControler.PayInvoices)
+	scoped.GET("/invoice/payment-discrepancies", deps.invoiceControler.FindPaymentDiscrepancies)
+	scoped.GET("/invoice/unconfirmed-payments", deps.invoiceControler.FindUnconfirmedPayments)
+	scoped.POST("/invoice/:id/pay", deps.invoiceControler.PayInvoice)
+	scoped.DELETE("/invoice/:id/payments/:paymentId", deps.invoiceControler.DeleteInvoicePayment)
+	scoped.GET("/invoice/:id/attachments", deps.attachmentController.FindAttachments)
+	scoped.POST("/invoice/:id/attachments", deps.attachmentController.CreateAttachment)
+	scoped.GET("/invoice/:id/attachments/:attachmentId", deps.attachmentController.DownloadAttachment)
+	scoped.DELETE("/invoice/:id/attachments/:attachmentId", deps.attachmentController.DeleteAttachment)
+	scoped.POST("/reconciliation/ofx", deps.reconciliationController.ReconcileOfxStatement)
+	scoped.POST("/reconciliation/csv", deps.reconciliationController.ReconcileCsvStatement)
+	scoped.POST("/reconciliation/email", deps.reconciliationController.ReconcilePaymentEmails)
+	scoped.GET("/table-value-source", deps.tableValueSourceController.FindTableValueSources)
+	scoped.GET("/table-value-source/:id", deps.tableValueSourceController.FindTableValueSourceById)
+	scoped.POST("/table-value-source", deps.tableValueSourceController.CreateTableValueSource)
+	scoped.PUT("/table-value-source/:id", deps.tableValueSourceController.UpdateTableValueSource)
+	scoped.GET("/email-value-source", deps.emailValueSourceController.FindEmailValueSources)
+	scoped.GET("/email-value-source/:id", deps.emailValueSourceController.FindEmailValueSourceById)
+	scoped.POST("/email-value-source", deps.emailValueSourceController.CreateEmailValueSource)
+	scoped.PUT("/email-value-source/:id", deps.emailValueSourceController.UpdateEmailValueSource)
+	scoped.POST("/bill-processing/start", deps.billProcessingController.StartBillProcessing)
+	scoped.GET("/bill-processing/status/:id", deps.billProcessingController.GetBillProcessingStatus)
+	scoped.GET("/bill-processing", deps.billProcessingController.FindBillProcessings)
+	scoped.GET("/mail-account", deps.mailAccountController.FindMailAccounts)
+	scoped.GET("/mail-account/:id", deps.mailAccountController.FindMailAccountById)
+	scoped.POST("/mail-account", deps.mailAccountController.CreateMailAccount)
+	scoped.GET("/secret", deps.secretController.FindSecrets)
+	scoped.GET("/secret/:id", deps.secretController.FindSecretById)
+	scoped.POST("/secret", deps.secretController.CreateSecret)
+	scoped.PUT("/secret/:id", deps.secretController.UpdateSecret)
+	scoped.DELETE("/secret/:id", deps.secretController.DeleteSecret)
+	scoped.POST("/secret/rotate", deps.secretController.RotateSecrets)
+	scoped.GET("/auth/gmail/start", deps.gmailAuthController.StartGmailAuth)
+	scoped.GET("/auth/gmail/status", deps.gmailAuthController.GetGmailAuthStatus)
+	scoped.GET("/holiday", deps.holidayController.FindHolidays)
+	scoped.POST("/holiday", deps.holidayController.CreateHoliday)
+	scoped.DELETE("/holiday/:id", deps.holidayController.DeleteHoliday)
+	scoped.GET("/category", deps.categoryController.FindCategories)
+	scoped.GET("/category/budget", deps.categoryController.FindCategoryBudgets)
+	scoped.GET("/category/:id", deps.categoryController.FindCategoryById)
+	scoped.POST("/category", deps.categoryController.CreateCategory)
+	scoped.PUT("/category/:id", deps.categoryController.UpdateCategory)
+	scoped.DELETE("/category/:id", deps.categoryController.DeleteCategory)
+	scoped.GET("/settlements", deps.settlementController.FindSettlement)
 
 	router.Run(":8080")
 }
 
-func initDependencies(ctx context.Context, database *mongo.Database, gmailService *gmail.Service) *Dependencies {
+func initDependencies(ctx context.Context, database *mongo.Database) (*Dependencies, error) {
 
 	userRepository := user.NewUserRepository(ctx, database)
 	userUseCase := user_usecase.NewUserUseCase(userRepository)
 	userController := user_controller.NewUserController(userUseCase)
 
+	tokenService, err := token_service.NewTokenService()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenRepository := refresh_token.NewRefreshTokenRepository(ctx, database)
+	passwordSetupTokenRepository := password_setup_token.NewPasswordSetupTokenRepository(ctx, database)
+	authUseCase := auth_usecase.NewAuthUseCase(userRepository, refreshTokenRepository, passwordSetupTokenRepository, tokenService)
+	authController := auth_controller.NewAuthController(authUseCase)
+
+	householdRepository := household.NewHouseholdRepository(ctx, database)
+	householdUseCase := household_usecase.NewHouseholdUseCase(householdRepository, userRepository)
+	householdController := household_controller.NewHouseholdController(householdUseCase)
+
+	secretService, err := secret_service.NewSecretService()
+	if err != nil {
+		return nil, err
+	}
+
+	oauthTokenRepository := oauth_token.NewOAuthTokenRepository(ctx, database, secretService)
+	secretRepository := secret.NewSecretRepository(ctx, database, secretService)
+	secretUseCase := secret_usecase.NewSecretUseCase(secretRepository, secretRepository, oauthTokenRepository)
+	secretController := secret_controller.NewSecretController(secretUseCase)
+
+	log.Println("Creating Gmail service...")
+	gmailService, err := gmail_service.NewGmailService(ctx, oauthTokenRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	mailAccountRepository := mail_account.NewMailAccountRepository(ctx, database)
+	mailAccountUseCase := mail_account_usecase.NewMailAccountUseCase(mailAccountRepository, userRepository)
+	mailAccountController := mail_account_controller.NewMailAccountController(mailAccountUseCase)
+	emailServiceResolver := email_service.NewEmailServiceResolver(mailAccountRepository, gmailService)
+	gmailAuthUseCase := gmail_auth_usecase.NewGmailAuthUseCase(gmailService, mailAccountRepository)
+	gmailAuthController := gmail_auth_controller.NewGmailAuthController(gmailAuthUseCase)
+
+	holidayRepository := holiday.NewHolidayRepository(ctx, database)
+	businessCalendar := calendar.NewCalendar(holidayRepository)
+	holidayUseCase := holiday_usecase.NewHolidayUseCase(holidayRepository, businessCalendar)
+	holidayController := holiday_controller.NewHolidayController(holidayUseCase)
+
+	categoryRepository := category.NewCategoryRepository(ctx, database)
+
 	billRepository := bill.NewBillRepository(ctx, database)
-	billUseCase := bill_usecase.NewBillUseCase(billRepository)
-	billController := bill_controller.NewBillController(billUseCase)
 
-	invoiceRepository := invoice.NewInvoiceRepository(ctx, database)
-	invoiceUseCase := invoice_usecase.NewInvoiceUseCase(invoiceRepository)
+	invoiceRepository, err := invoice.NewInvoiceRepository(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	invoiceUseCase := invoice_usecase.NewInvoiceUseCase(invoiceRepository, billRepository, categoryRepository)
 	invoiceController := invoice_controller.NewInvoiceController(invoiceUseCase)
 
+	settlementUseCase := settlement_usecase.NewSettlementUseCase(billRepository, invoiceRepository, userRepository)
+	settlementController := settlement_controller.NewSettlementController(settlementUseCase)
+
+	categoryUseCase := category_usecase.NewCategoryUseCase(categoryRepository, billRepository, invoiceRepository)
+	categoryController := category_controller.NewCategoryController(categoryUseCase)
+
+	blobStorage, err := blob_storage.NewBlobStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentRepository := attachment.NewAttachmentRepository(ctx, database)
+	attachmentUseCase := attachment_usecase.NewAttachmentUseCase(attachmentRepository, invoiceRepository, blobStorage)
+	attachmentController := attachment_controller.NewAttachmentController(attachmentUseCase)
+
+	reconciliationUseCase := reconciliation_usecase.NewReconciliationUseCase(invoiceRepository, billRepository, emailServiceResolver)
+	reconciliationController := reconciliation_controller.NewReconciliationController(reconciliationUseCase)
+
 	tableValueSourceRepository := table_value_source.NewTableValueSourceRepository(ctx, database)
 	tableValueSourceUseCase := table_value_source_usecase.NewTableValueSourceUseCase(tableValueSourceRepository)
 	tableValueSourceController := table_value_source_controller.NewTableValueSourceController(tableValueSourceUseCase)
 
 	emailValueSourceRepository := email_value_source.NewEmailValueSourceRepository(ctx, database)
-	emailValueSourceUseCase := email_value_source_usecase.NewEmailValueSourceUseCase(emailValueSourceRepository)
+	emailValueSourceUseCase := email_value_source_usecase.NewEmailValueSourceUseCase(emailValueSourceRepository, mailAccountRepository, emailServiceResolver)
 	emailValueSourceController := email_value_source_controller.NewEmailValueSourceController(emailValueSourceUseCase)
 
 	billProcessingRepository := bill_processing.NewBillProcessingRepository(ctx, database)
-	emailService := email_service.NewGmailEmailService(gmailService)
-	billProcessingUseCase := bill_processing_usecase.NewBillProcessingUseCase(billProcessingRepository, billRepository, tableValueSourceRepository,
-		emailValueSourceRepository, invoiceRepository, emailService)
+	transactionManager := transaction_manager.NewTransactionManager(ctx, database)
+	billUseCase := bill_usecase.NewBillUseCase(billRepository, userRepository, categoryRepository, invoiceRepository, attachmentRepository, billProcessingRepository,
+		tableValueSourceRepository, emailValueSourceRepository, blobStorage, transactionManager, businessCalendar)
+	billController := bill_controller.NewBillController(billUseCase)
+	billProcessingUseCase := bill_processing_usecase.NewBillProcessingUseCase(billProcessingRepository, billRepository, categoryRepository, tableValueSourceRepository,
+		emailValueSourceRepository, invoiceRepository, emailServiceResolver, transactionManager, businessCalendar)
 	billProcessingController := bill_processing_controller.NewBillProcessingController(billProcessingUseCase)
 
+	dailyJobs := scheduler.NewScheduler(householdUseCase)
+	dailyJobs.Daily(ctx, "automatic payments", func(ctx context.Context) *internal_error.InternalError {
+		return billProcessingUseCase.SettleAutomaticPayments(ctx, time.Now())
+	})
+
 	return &Dependencies{
 		userController, billController, invoiceController, tableValueSourceController, emailValueSourceController, billProcessingController,
-	}
+		gmailAuthController, mailAccountController, secretController, attachmentController,
+		reconciliationController, holidayController, categoryController, settlementController,
+		householdController, authController, middleware.Authenticated(authUseCase), middleware.HouseholdScope(householdUseCase),
+	}, nil
 }
 
 type Dependencies struct {
@@ -122,4 +297,16 @@ type Dependencies struct {
 	tableValueSourceController *table_value_source_controller.TableValueSourceController
 	emailValueSourceController *email_value_source_controller.EmailValueSourceController
 	billProcessingController   *bill_processing_controller.BillProcessingController
+	gmailAuthController        *gmail_auth_controller.GmailAuthController
+	mailAccountController      *mail_account_controller.MailAccountController
+	secretController           *secret_controller.SecretController
+	attachmentController       *attachment_controller.AttachmentController
+	reconciliationController   *reconciliation_controller.ReconciliationController
+	holidayController          *holiday_controller.HolidayController
+	categoryController         *category_controller.CategoryController
+	settlementController       *settlement_controller.SettlementController
+	householdController        *household_controller.HouseholdController
+	authController             *auth_controller.AuthController
+	authenticated              gin.HandlerFunc
+	householdScope             gin.HandlerFunc
 }
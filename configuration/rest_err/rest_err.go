@@ -25,9 +25,17 @@ func (r *RestErr) Error() string {
 func ConvertError(internalError *internal_error.InternalError) *RestErr {
 	switch internalError.Err {
 	case "bad_request":
-		return NewBadRequestError(internalError.Error())
+		causes := make([]Causes, len(internalError.Causes))
+		for i, cause := range internalError.Causes {
+			causes[i] = Causes{Field: cause.Field, Message: cause.Message}
+		}
+		return NewBadRequestError(internalError.Error(), causes...)
 	case "not_found":
 		return NewNotFoundError(internalError.Error())
+	case "forbidden":
+		return NewForbiddenError(internalError.Error())
+	case "unauthorized":
+		return NewUnauthorizedError(internalError.Error())
 	default:
 		return NewInternalServerError(internalError.Error())
 	}
@@ -59,3 +67,21 @@ func NewNotFoundError(message string) *RestErr {
 		Causes:  nil,
 	}
 }
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "forbidden",
+		Code:    http.StatusForbidden,
+		Causes:  nil,
+	}
+}
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+		Causes:  nil,
+	}
+}
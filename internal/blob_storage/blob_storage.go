@@ -0,0 +1,46 @@
+package blob_storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	BLOB_STORAGE_TYPE      = "BLOB_STORAGE_TYPE"
+	BLOB_STORAGE_LOCAL_DIR = "BLOB_STORAGE_LOCAL_DIR"
+
+	LOCAL_STORAGE_TYPE = "local"
+
+	DEFAULT_LOCAL_DIR = "data/blobs"
+)
+
+// BlobStorageInterface stores binary content (attachments, receipts...) under a key.
+// Metadata such as content type and checksum is kept by the caller.
+type BlobStorageInterface interface {
+	Put(ctx context.Context, key string, content io.Reader) *internal_error.InternalError
+	Get(ctx context.Context, key string) (io.ReadCloser, *internal_error.InternalError)
+	Delete(ctx context.Context, key string) *internal_error.InternalError
+}
+
+// NewBlobStorage creates the storage selected by BLOB_STORAGE_TYPE (local filesystem by default).
+func NewBlobStorage() (BlobStorageInterface, error) {
+	storageType := getEnv(BLOB_STORAGE_TYPE, LOCAL_STORAGE_TYPE)
+
+	switch storageType {
+	case LOCAL_STORAGE_TYPE:
+		return NewLocalBlobStorage(getEnv(BLOB_STORAGE_LOCAL_DIR, DEFAULT_LOCAL_DIR))
+	default:
+		return nil, fmt.Errorf("unsupported blob storage type: %s", storageType)
+	}
+}
+
+func getEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
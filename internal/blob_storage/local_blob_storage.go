@@ -0,0 +1,112 @@
+package blob_storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// LocalBlobStorage keeps each blob as a file below baseDir, using the key as relative path.
+type LocalBlobStorage struct {
+	baseDir string
+}
+
+func NewLocalBlobStorage(baseDir string) (*LocalBlobStorage, error) {
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		log.Printf("Unable to create blob storage directory: %v", err)
+		return nil, err
+	}
+
+	return &LocalBlobStorage{
+		baseDir: baseDir,
+	}, nil
+}
+
+func (s *LocalBlobStorage) Put(ctx context.Context, key string, content io.Reader) *internal_error.InternalError {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Printf("Unable to create blob directory: %v", err)
+		return internal_error.NewInternalServerError("Error trying to store blob")
+	}
+
+	// written to a temporary file first, so a failed upload never leaves a partial blob
+	tmp, e := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if e != nil {
+		log.Printf("Unable to create blob file: %v", e)
+		return internal_error.NewInternalServerError("Error trying to store blob")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, e := io.Copy(tmp, content); e != nil {
+		tmp.Close()
+		log.Printf("Unable to write blob file: %v", e)
+		return internal_error.NewInternalServerError("Error trying to store blob")
+	}
+
+	if e := tmp.Close(); e != nil {
+		log.Printf("Unable to write blob file: %v", e)
+		return internal_error.NewInternalServerError("Error trying to store blob")
+	}
+
+	if e := os.Rename(tmp.Name(), path); e != nil {
+		log.Printf("Unable to move blob file: %v", e)
+		return internal_error.NewInternalServerError("Error trying to store blob")
+	}
+
+	return nil
+}
+
+func (s *LocalBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, *internal_error.InternalError) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		if errors.Is(e, fs.ErrNotExist) {
+			return nil, internal_error.NewNotFoundError("Blob not found")
+		}
+		log.Printf("Unable to open blob file: %v", e)
+		return nil, internal_error.NewInternalServerError("Error trying to read blob")
+	}
+
+	return f, nil
+}
+
+func (s *LocalBlobStorage) Delete(ctx context.Context, key string) *internal_error.InternalError {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if e := os.Remove(path); e != nil && !errors.Is(e, fs.ErrNotExist) {
+		log.Printf("Unable to delete blob file: %v", e)
+		return internal_error.NewInternalServerError("Error trying to delete blob")
+	}
+
+	return nil
+}
+
+// path maps the key to a file below baseDir, rejecting keys that would escape it.
+func (s *LocalBlobStorage) path(key string) (string, *internal_error.InternalError) {
+	cleanKey := filepath.Clean(filepath.FromSlash(key))
+
+	if key == "" || filepath.IsAbs(cleanKey) || cleanKey == ".." ||
+		strings.HasPrefix(cleanKey, ".."+string(filepath.Separator)) {
+		return "", internal_error.NewBadRequestError("invalid blob key")
+	}
+
+	return filepath.Join(s.baseDir, cleanKey), nil
+}
@@ -0,0 +1,268 @@
+package calendar
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/holiday_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// CalendarInterface tells the business days of a city: the days that are not weekends,
+// national holidays or the custom holidays registered for the city and its state.
+type CalendarInterface interface {
+	Holidays(
+		ctx context.Context,
+		year int,
+		state string,
+		city string) ([]*Holiday, *internal_error.InternalError)
+	IsBusinessDay(
+		ctx context.Context,
+		date time.Time,
+		state string,
+		city string) (bool, *internal_error.InternalError)
+	// AdjustDueDate moves a due date that is not a business day as the rule of the bill says.
+	AdjustDueDate(
+		ctx context.Context,
+		dueDate time.Time,
+		rule bill_entity.DueDateRule) (time.Time, *internal_error.InternalError)
+	// BusinessDays loads the custom holidays of every state and city once, to adjust the due
+	// dates of many bills.
+	BusinessDays(ctx context.Context) (*BusinessDays, *internal_error.InternalError)
+}
+
+type Holiday struct {
+	Id    string // custom holidays only
+	Date  time.Time
+	Name  string
+	State string
+	City  string
+}
+
+// BusinessDays adjusts due dates with the custom holidays loaded by Calendar.BusinessDays.
+type BusinessDays struct {
+	customHolidays []*holiday_entity.Holiday
+}
+
+type Calendar struct {
+	holidayRepository holiday_entity.HolidayRepositoryInterface
+}
+
+func NewCalendar(holidayRepository holiday_entity.HolidayRepositoryInterface) *Calendar {
+	return &Calendar{
+		holidayRepository: holidayRepository,
+	}
+}
+
+// NationalHolidays returns the national holidays of the year, including the ones that move with
+// Easter. Carnaval is not a holiday by law, but banks are closed, so boletos move as well.
+func NationalHolidays(year int) []*Holiday {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+	easter := Easter(year)
+
+	holidays := []*Holiday{
+		{Date: date(time.January, 1), Name: "Confraternização Universal"},
+		{Date: easter.AddDate(0, 0, -48), Name: "Carnaval"},
+		{Date: easter.AddDate(0, 0, -47), Name: "Carnaval"},
+		{Date: easter.AddDate(0, 0, -2), Name: "Sexta-feira Santa"},
+		{Date: date(time.April, 21), Name: "Tiradentes"},
+		{Date: date(time.May, 1), Name: "Dia do Trabalho"},
+		{Date: easter.AddDate(0, 0, 60), Name: "Corpus Christi"},
+		{Date: date(time.September, 7), Name: "Independência do Brasil"},
+		{Date: date(time.October, 12), Name: "Nossa Senhora Aparecida"},
+		{Date: date(time.November, 2), Name: "Finados"},
+		{Date: date(time.November, 15), Name: "Proclamação da República"},
+		{Date: date(time.December, 25), Name: "Natal"},
+	}
+
+	// national holiday since 2024 (Lei 14.759/2023)
+	if year >= 2024 {
+		holidays = append(holidays, &Holiday{Date: date(time.November, 20), Name: "Dia Nacional de Zumbi e da Consciência Negra"})
+	}
+
+	sortHolidays(holidays)
+
+	return holidays
+}
+
+// Easter returns the Easter Sunday of the year (Gregorian calendar, anonymous algorithm).
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+func (c *Calendar) Holidays(
+	ctx context.Context,
+	year int,
+	state string,
+	city string) ([]*Holiday, *internal_error.InternalError) {
+
+	customHolidays, err := c.findCustomHolidays(ctx, state, city)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := NationalHolidays(year)
+
+	for _, customHoliday := range customHolidays {
+		date, _ := time.ParseInLocation("2006-01-02", customHoliday.Date, time.Local)
+		if customHoliday.Yearly {
+			date = time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+			// a yearly holiday on February 29 is not observed in the other years
+			if !customHoliday.OccursOn(date) {
+				continue
+			}
+		}
+		if date.Year() != year {
+			continue
+		}
+
+		holidays = append(holidays, &Holiday{
+			Id:    customHoliday.Id,
+			Date:  date,
+			Name:  customHoliday.Name,
+			State: customHoliday.State,
+			City:  customHoliday.City,
+		})
+	}
+
+	sortHolidays(holidays)
+
+	return holidays, nil
+}
+
+func (c *Calendar) IsBusinessDay(
+	ctx context.Context,
+	date time.Time,
+	state string,
+	city string) (bool, *internal_error.InternalError) {
+
+	customHolidays, err := c.findCustomHolidays(ctx, state, city)
+	if err != nil {
+		return false, err
+	}
+
+	return isBusinessDay(date, customHolidays), nil
+}
+
+func (c *Calendar) AdjustDueDate(
+	ctx context.Context,
+	dueDate time.Time,
+	rule bill_entity.DueDateRule) (time.Time, *internal_error.InternalError) {
+
+	if rule.Adjustment != bill_entity.NextBusinessDay && rule.Adjustment != bill_entity.PreviousBusinessDay {
+		return dueDate, nil
+	}
+
+	customHolidays, err := c.findCustomHolidays(ctx, rule.State, rule.City)
+	if err != nil {
+		return dueDate, err
+	}
+
+	return adjustDueDate(dueDate, rule, customHolidays), nil
+}
+
+func (c *Calendar) BusinessDays(ctx context.Context) (*BusinessDays, *internal_error.InternalError) {
+	customHolidays, err := c.holidayRepository.FindHolidays(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &BusinessDays{customHolidays: customHolidays}, nil
+}
+
+// AdjustDueDate moves a due date that is not a business day as the rule of the bill says,
+// considering the custom holidays of the state and city of the rule.
+func (b *BusinessDays) AdjustDueDate(dueDate time.Time, rule bill_entity.DueDateRule) time.Time {
+	customHolidays := slices.DeleteFunc(slices.Clone(b.customHolidays), func(holiday *holiday_entity.Holiday) bool {
+		return !holiday.AppliesTo(rule.State, rule.City)
+	})
+
+	return adjustDueDate(dueDate, rule, customHolidays)
+}
+
+func adjustDueDate(
+	dueDate time.Time,
+	rule bill_entity.DueDateRule,
+	customHolidays []*holiday_entity.Holiday) time.Time {
+
+	step := 0
+	switch rule.Adjustment {
+	case bill_entity.NextBusinessDay:
+		step = 1
+	case bill_entity.PreviousBusinessDay:
+		step = -1
+	default:
+		return dueDate
+	}
+
+	for !isBusinessDay(dueDate, customHolidays) {
+		dueDate = dueDate.AddDate(0, 0, step)
+	}
+
+	return dueDate
+}
+
+// findCustomHolidays returns the custom holidays observed in the city of the state.
+func (c *Calendar) findCustomHolidays(
+	ctx context.Context,
+	state string,
+	city string) ([]*holiday_entity.Holiday, *internal_error.InternalError) {
+
+	holidays, err := c.holidayRepository.FindHolidays(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(holidays, func(holiday *holiday_entity.Holiday) bool {
+		return !holiday.AppliesTo(state, city)
+	}), nil
+}
+
+func isBusinessDay(date time.Time, customHolidays []*holiday_entity.Holiday) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+
+	for _, holiday := range NationalHolidays(date.Year()) {
+		if sameDay(holiday.Date, date) {
+			return false
+		}
+	}
+
+	for _, holiday := range customHolidays {
+		if holiday.OccursOn(date) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func sortHolidays(holidays []*Holiday) {
+	slices.SortStableFunc(holidays, func(a, b *Holiday) int {
+		return a.Date.Compare(b.Date)
+	})
+}
@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNationalHolidaysMovable(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		year          int
+		easter        time.Time
+		carnaval      []time.Time
+		goodFriday    time.Time
+		corpusChristi time.Time
+	}{
+		{
+			year:          2019,
+			easter:        date(2019, time.April, 21),
+			carnaval:      []time.Time{date(2019, time.March, 4), date(2019, time.March, 5)},
+			goodFriday:    date(2019, time.April, 19),
+			corpusChristi: date(2019, time.June, 20),
+		},
+		{
+			// leap year, Carnaval before February 29
+			year:          2024,
+			easter:        date(2024, time.March, 31),
+			carnaval:      []time.Time{date(2024, time.February, 12), date(2024, time.February, 13)},
+			goodFriday:    date(2024, time.March, 29),
+			corpusChristi: date(2024, time.May, 30),
+		},
+		{
+			year:          2025,
+			easter:        date(2025, time.April, 20),
+			carnaval:      []time.Time{date(2025, time.March, 3), date(2025, time.March, 4)},
+			goodFriday:    date(2025, time.April, 18),
+			corpusChristi: date(2025, time.June, 19),
+		},
+		{
+			// leap year, Carnaval after February 29
+			year:          2000,
+			easter:        date(2000, time.April, 23),
+			carnaval:      []time.Time{date(2000, time.March, 6), date(2000, time.March, 7)},
+			goodFriday:    date(2000, time.April, 21),
+			corpusChristi: date(2000, time.June, 22),
+		},
+		{
+			// leap year with an early Easter
+			year:          2008,
+			easter:        date(2008, time.March, 23),
+			carnaval:      []time.Time{date(2008, time.February, 4), date(2008, time.February, 5)},
+			goodFriday:    date(2008, time.March, 21),
+			corpusChristi: date(2008, time.May, 22),
+		},
+		{
+			// latest Easter of the century
+			year:          2038,
+			easter:        date(2038, time.April, 25),
+			carnaval:      []time.Time{date(2038, time.March, 8), date(2038, time.March, 9)},
+			goodFriday:    date(2038, time.April, 23),
+			corpusChristi: date(2038, time.June, 24),
+		},
+		{
+			// not a leap year, although divisible by 4
+			year:          2100,
+			easter:        date(2100, time.March, 28),
+			carnaval:      []time.Time{date(2100, time.February, 8), date(2100, time.February, 9)},
+			goodFriday:    date(2100, time.March, 26),
+			corpusChristi: date(2100, time.May, 27),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.easter.Format("2006"), func(t *testing.T) {
+			if got := Easter(tt.year); !got.Equal(tt.easter) {
+				t.Errorf("Easter(%d) = %s, want %s", tt.year, got.Format(time.DateOnly), tt.easter.Format(time.DateOnly))
+			}
+
+			want := map[string][]time.Time{
+				"Carnaval":          tt.carnaval,
+				"Sexta-feira Santa": {tt.goodFriday},
+				"Corpus Christi":    {tt.corpusChristi},
+			}
+			got := make(map[string][]time.Time)
+			for _, holiday := range NationalHolidays(tt.year) {
+				if _, movable := want[holiday.Name]; movable {
+					got[holiday.Name] = append(got[holiday.Name], holiday.Date)
+				}
+			}
+
+			for name, dates := range want {
+				if len(got[name]) != len(dates) {
+					t.Errorf("%s %d = %v, want %v", name, tt.year, got[name], dates)
+					continue
+				}
+				for i := range dates {
+					if !got[name][i].Equal(dates[i]) {
+						t.Errorf("%s %d = %s, want %s", name, tt.year,
+							got[name][i].Format(time.DateOnly), dates[i].Format(time.DateOnly))
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,14 @@
 package email_data_extractor
 
 import (
-	"cmp"
 	"fmt"
 	"log"
-	"math"
-	"slices"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type CorsanEmailDataExtractor struct {
@@ -22,7 +19,7 @@ type CorsanEmailData struct {
 	CodigoImovel  string
 	Vencimento    time.Time
 	MesReferencia string
-	Valor         float64
+	Valor         money.Money
 }
 
 type MonthOfTheYear uint8
@@ -82,32 +79,12 @@ func (x *CorsanEmailDataExtractor) Extract(request EmailDataExtractorRequest) (*
 
 	log.Println("Extracting email data. Request:", request)
 
-	startDate := request.StartDate.Format("2006/01/02")
-	endDate := request.EndDate.Format("2006/01/02")
-
-	messages, err := x.emailService.FindMessages(request.Subject, request.Address, startDate, endDate)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(messages) == 0 {
-		return nil, internal_error.NewNotFoundError("No messages found")
-	}
-
-	slices.SortStableFunc(messages, func(a, b *email_service.EmailServiceMessage) int {
-		return cmp.Compare(a.Id, b.Id)
-	})
-
-	log.Println("Ordered Messages", messages)
-
-	lastMessage := messages[0]
-
-	lastMessage, err = x.emailService.GetMessage(lastMessage.Id)
+	message, err := findMessage(x.emailService, request)
 	if err != nil {
 		return nil, err
 	}
 
-	parsedData, err := x.parse(lastMessage)
+	parsedData, err := x.parse(message)
 
 	log.Println("Parsed data", parsedData)
 
@@ -116,7 +93,11 @@ func (x *CorsanEmailDataExtractor) Extract(request EmailDataExtractorRequest) (*
 	}
 
 	return &EmailDataExtractorResponse{
-		Amount: parsedData.Valor,
+		Amount:  parsedData.Valor,
+		Barcode: findBarcode(message.Text()),
+		Message: newEmailDataExtractorMessage(message,
+			"corsan", parsedData.CodigoImovel, parsedData.Vencimento.Format("2006-01-02"),
+			parsedData.MesReferencia, fmt.Sprintf("%.2f", parsedData.Valor.Float())),
 	}, nil
 }
 
@@ -155,12 +136,10 @@ func (x *CorsanEmailDataExtractor) parse(msg *email_service.EmailServiceMessage)
 	indexAgradecemos := strings.Index(snippet, STR_AGRADECEMOS)
 	valorValorAPagarStr := snippet[indexValorAPAgar+len(STR_VALOR)+1 : indexAgradecemos-1]
 	valorValorAPagarStrNumber := valorValorAPagarStr[0 : len(valorValorAPagarStr)-1]
-	valorValorAPagarStrNumber = strings.ReplaceAll(valorValorAPagarStrNumber, ",", ".")
-	valorValorAPagar, err := strconv.ParseFloat(valorValorAPagarStrNumber, 64)
+	valorValorAPagar, err := money.Parse(valorValorAPagarStrNumber)
 	if err != nil {
 		return nil, internal_error.NewInternalServerError(fmt.Sprintf("Error parsing value %s", valorValorAPagarStrNumber))
 	}
-	valorValorAPagar = math.Ceil(valorValorAPagar*100) / 100
 
 	return &CorsanEmailData{
 		CodigoImovel:  valorCodigoImovel,
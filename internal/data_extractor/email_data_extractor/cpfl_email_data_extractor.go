@@ -1,17 +1,14 @@
 package email_data_extractor
 
 import (
-	"cmp"
 	"fmt"
 	"log"
-	"math"
-	"slices"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type CpflEmailDataExtractor struct {
@@ -22,7 +19,7 @@ type CpflEmailData struct {
 	Instalacao    string
 	Vencimento    time.Time
 	MesReferencia string
-	Valor         float64
+	Valor         money.Money
 }
 
 func NewCpflEmailDataExtractor(emailService email_service.EmailServiceInterface) *CpflEmailDataExtractor {
@@ -35,32 +32,12 @@ func (x *CpflEmailDataExtractor) Extract(request EmailDataExtractorRequest) (*Em
 
 	log.Println("Extracting email data. Request:", request)
 
-	startDate := request.StartDate.Format("2006/01/02")
-	endDate := request.EndDate.Format("2006/01/02")
-
-	messages, err := x.emailService.FindMessages(request.Subject, request.Address, startDate, endDate)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(messages) == 0 {
-		return nil, internal_error.NewNotFoundError("No messages found")
-	}
-
-	slices.SortStableFunc(messages, func(a, b *email_service.EmailServiceMessage) int {
-		return cmp.Compare(a.Id, b.Id)
-	})
-
-	log.Println("Ordered Messages", messages)
-
-	lastMessage := messages[0]
-
-	lastMessage, err = x.emailService.GetMessage(lastMessage.Id)
+	message, err := findMessage(x.emailService, request)
 	if err != nil {
 		return nil, err
 	}
 
-	parsedData, err := x.parse(lastMessage)
+	parsedData, err := x.parse(message)
 
 	log.Println("Parsed data", parsedData)
 
@@ -69,7 +46,11 @@ func (x *CpflEmailDataExtractor) Extract(request EmailDataExtractorRequest) (*Em
 	}
 
 	return &EmailDataExtractorResponse{
-		Amount: parsedData.Valor,
+		Amount:  parsedData.Valor,
+		Barcode: findBarcode(message.Text()),
+		Message: newEmailDataExtractorMessage(message,
+			"cpfl", parsedData.Instalacao, parsedData.Vencimento.Format("2006-01-02"),
+			parsedData.MesReferencia, fmt.Sprintf("%.2f", parsedData.Valor.Float())),
 	}, nil
 }
 
@@ -105,12 +86,10 @@ func (x *CpflEmailDataExtractor) parse(msg *email_service.EmailServiceMessage) (
 	indexParaAbrir := strings.Index(snippet, STR_PARA_ABRIR)
 	valorValorAPagarStr := snippet[indexValorAPAgar+len(STR_VALOR_A_PAGAR)+1 : indexParaAbrir-1]
 	valorValorAPagarStrNumber := valorValorAPagarStr[3 : len(valorValorAPagarStr)-1]
-	valorValorAPagarStrNumber = strings.ReplaceAll(valorValorAPagarStrNumber, ",", ".")
-	valorValorAPagar, err := strconv.ParseFloat(valorValorAPagarStrNumber, 64)
+	valorValorAPagar, err := money.Parse(valorValorAPagarStrNumber)
 	if err != nil {
 		return nil, internal_error.NewInternalServerError(fmt.Sprintf("Error parsing value %s", valorValorAPagarStrNumber))
 	}
-	valorValorAPagar = math.Ceil(valorValorAPagar*100) / 100
 
 	return &CpflEmailData{
 		Instalacao:    valorInstalacao,
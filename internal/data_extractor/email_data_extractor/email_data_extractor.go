@@ -1,11 +1,19 @@
 package email_data_extractor
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type EmailDataExtractorInterface interface {
@@ -17,10 +25,52 @@ type EmailDataExtractorRequest struct {
 	Address   string
 	StartDate time.Time
 	EndDate   time.Time
+	Labels    []string
+	Exclude   []string
+	Selection MessageSelection
+	// SkipMessage reports whether a message must not be used, e.g. because it was
+	// already consumed by another invoice. Skipped messages are passed over in favour
+	// of the next candidate.
+	SkipMessage func(messageId string) bool
 }
 
 type EmailDataExtractorResponse struct {
-	Amount float64
+	Amount  money.Money
+	Barcode string // linha digitável found in the bill, digits only
+	Message EmailDataExtractorMessage
+}
+
+// EmailDataExtractorMessage identifies the message the data was extracted from.
+type EmailDataExtractorMessage struct {
+	Id         string
+	ThreadId   string
+	Sender     string
+	ReceivedAt time.Time
+	// ContentHash is computed from the extracted data instead of the raw message, so a
+	// forwarded copy of the same bill hashes to the same value.
+	ContentHash string
+}
+
+type MessageSelection uint8
+
+const (
+	NewestMessage MessageSelection = iota + 1
+	OldestMessage
+)
+
+func (s MessageSelection) Name() string {
+	return messageSelectionNames[s]
+}
+
+var messageSelectionNames = []string{
+	"",
+	"newest",
+	"oldest",
+}
+
+var defaultExclusions = []string{
+	"in:trash",
+	"in:spam",
 }
 
 func NewEmailDataExtractor(emailService email_service.EmailServiceInterface, dataExtractor email_value_source_entity.EmailValueSourceDataExtractor) EmailDataExtractorInterface {
@@ -33,3 +83,78 @@ func NewEmailDataExtractor(emailService email_service.EmailServiceInterface, dat
 		return nil
 	}
 }
+
+// findMessage searches the messages matching the request and returns the full content
+// of the one chosen by request.Selection (the newest one when not set).
+func findMessage(emailService email_service.EmailServiceInterface, request EmailDataExtractorRequest) (*email_service.EmailServiceMessage, *internal_error.InternalError) {
+
+	exclude := request.Exclude
+	if len(exclude) == 0 {
+		exclude = defaultExclusions
+	}
+
+	messages, err := emailService.FindMessages(email_service.FindMessagesRequest{
+		Subject:   request.Subject,
+		Address:   request.Address,
+		StartDate: request.StartDate.Format("2006/01/02"),
+		EndDate:   request.EndDate.Format("2006/01/02"),
+		Labels:    request.Labels,
+		Exclude:   exclude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, internal_error.NewNotFoundError("No messages found")
+	}
+
+	selection := request.Selection
+	if selection == 0 {
+		selection = NewestMessage
+	}
+
+	if selection == OldestMessage {
+		slices.Reverse(messages)
+	}
+
+	for _, candidate := range messages {
+		if request.SkipMessage != nil && request.SkipMessage(candidate.Id) {
+			log.Printf("Skipping message %s: already consumed", candidate.Id)
+			continue
+		}
+
+		log.Printf("Selected %s message %s (received at %v)", selection.Name(), candidate.Id,
+			time.UnixMilli(candidate.InternalDate))
+
+		return emailService.GetMessage(candidate.Id)
+	}
+
+	return nil, internal_error.NewNotFoundError("No unconsumed messages found")
+}
+
+// newEmailDataExtractorMessage describes msg, hashing the given extracted fields.
+func newEmailDataExtractorMessage(msg *email_service.EmailServiceMessage, fields ...string) EmailDataExtractorMessage {
+	hash := sha256.Sum256([]byte(strings.Join(fields, "|")))
+
+	return EmailDataExtractorMessage{
+		Id:          msg.Id,
+		ThreadId:    msg.ThreadId,
+		Sender:      msg.Header("From"),
+		ReceivedAt:  time.UnixMilli(msg.InternalDate),
+		ContentHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+var barcodeRegex = regexp.MustCompile(`\d[\d .\-]{42,64}\d`)
+
+// findBarcode returns the digits of the first linha digitável or código de barras in the text.
+func findBarcode(text string) string {
+	for _, match := range barcodeRegex.FindAllString(text, -1) {
+		if barcode := invoice_entity.NormalizeBarcode(match); barcode != "" {
+			return barcode
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,208 @@
+package email_data_extractor
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// PaymentConfirmationExtractorInterface reads the confirmations the banks send after a
+// payment ("Pix enviado", "Pagamento de boleto realizado").
+type PaymentConfirmationExtractorInterface interface {
+	ExtractPayments(request EmailDataExtractorRequest) ([]*PaymentConfirmation, *internal_error.InternalError)
+}
+
+type PaymentConfirmation struct {
+	Method      invoice_entity.PaymentMethod
+	Amount      money.Money
+	Date        time.Time
+	Beneficiary string
+	Barcode     string // linha digitável of the paid boleto, digits only
+	Message     EmailDataExtractorMessage
+}
+
+// NewPaymentConfirmationExtractors returns one extractor for each kind of confirmation.
+func NewPaymentConfirmationExtractors(emailService email_service.EmailServiceInterface) []PaymentConfirmationExtractorInterface {
+	return []PaymentConfirmationExtractorInterface{
+		NewPixPaymentConfirmationExtractor(emailService),
+		NewBoletoPaymentConfirmationExtractor(emailService),
+	}
+}
+
+type PixPaymentConfirmationExtractor struct {
+	emailService email_service.EmailServiceInterface
+}
+
+func NewPixPaymentConfirmationExtractor(emailService email_service.EmailServiceInterface) *PixPaymentConfirmationExtractor {
+	return &PixPaymentConfirmationExtractor{
+		emailService: emailService,
+	}
+}
+
+func (x *PixPaymentConfirmationExtractor) ExtractPayments(request EmailDataExtractorRequest) ([]*PaymentConfirmation, *internal_error.InternalError) {
+	return extractPaymentConfirmations(x.emailService, request, invoice_entity.Pix,
+		[]string{"Pix enviado", "Pix realizado", "Comprovante de Pix"})
+}
+
+type BoletoPaymentConfirmationExtractor struct {
+	emailService email_service.EmailServiceInterface
+}
+
+func NewBoletoPaymentConfirmationExtractor(emailService email_service.EmailServiceInterface) *BoletoPaymentConfirmationExtractor {
+	return &BoletoPaymentConfirmationExtractor{
+		emailService: emailService,
+	}
+}
+
+func (x *BoletoPaymentConfirmationExtractor) ExtractPayments(request EmailDataExtractorRequest) ([]*PaymentConfirmation, *internal_error.InternalError) {
+	return extractPaymentConfirmations(x.emailService, request, invoice_entity.Boleto,
+		[]string{"Pagamento de boleto realizado", "Comprovante de pagamento de boleto"})
+}
+
+var (
+	labeledAmountRegex = regexp.MustCompile(
+		`(?i)valor(?: pago| do pagamento| da transa[cç][aã]o| do pix| total)?\s*:?\s*R\$\s*([\d.]+,\d{2})`)
+	amountRegex      = regexp.MustCompile(`R\$\s*([\d.]+,\d{2})`)
+	labeledDateRegex = regexp.MustCompile(
+		`(?i)(?:data(?: do pagamento| de pagamento| da transa[cç][aã]o| e hora)?|realizad[oa] em|pago em)\s*:?\s*(\d{2}/\d{2}/\d{4})`)
+	beneficiaryRegex = regexp.MustCompile(
+		`(?i)^(?:para|destinat[aá]rio|benefici[aá]rio|favorecido|recebedor|nome do recebedor|nome do favorecido)\s*:?\s*(.*)$`)
+)
+
+// extractPaymentConfirmations searches the messages with each subject (or request.Subject, when
+// informed) and parses every one not skipped by the request. Messages that cannot be parsed are
+// logged and ignored, since the subjects also match unrelated emails.
+func extractPaymentConfirmations(
+	emailService email_service.EmailServiceInterface,
+	request EmailDataExtractorRequest,
+	method invoice_entity.PaymentMethod,
+	subjects []string) ([]*PaymentConfirmation, *internal_error.InternalError) {
+
+	if request.Subject != "" {
+		subjects = []string{request.Subject}
+	}
+
+	exclude := request.Exclude
+	if len(exclude) == 0 {
+		exclude = defaultExclusions
+	}
+
+	confirmations := make([]*PaymentConfirmation, 0)
+	seen := make(map[string]bool)
+
+	for _, subject := range subjects {
+		messages, err := emailService.FindMessages(email_service.FindMessagesRequest{
+			Subject:   subject,
+			Address:   request.Address,
+			StartDate: request.StartDate.Format("2006/01/02"),
+			EndDate:   request.EndDate.Format("2006/01/02"),
+			Labels:    request.Labels,
+			Exclude:   exclude,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range messages {
+			if seen[candidate.Id] {
+				continue
+			}
+			seen[candidate.Id] = true
+
+			if request.SkipMessage != nil && request.SkipMessage(candidate.Id) {
+				continue
+			}
+
+			message, err := emailService.GetMessage(candidate.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			confirmation, err := parsePaymentConfirmation(message, method)
+			if err != nil {
+				log.Printf("Ignoring message %s: %s", candidate.Id, err.Message)
+				continue
+			}
+
+			confirmations = append(confirmations, confirmation)
+		}
+	}
+
+	return confirmations, nil
+}
+
+func parsePaymentConfirmation(msg *email_service.EmailServiceMessage, method invoice_entity.PaymentMethod) (*PaymentConfirmation, *internal_error.InternalError) {
+	text := msg.Text()
+
+	match := labeledAmountRegex.FindStringSubmatch(text)
+	if match == nil {
+		match = amountRegex.FindStringSubmatch(text)
+	}
+	if match == nil {
+		return nil, internal_error.NewNotFoundError("No amount found in message")
+	}
+
+	amount, e := money.Parse(match[1])
+	if e != nil || amount <= 0 {
+		return nil, internal_error.NewInternalServerError(fmt.Sprintf("Error parsing value %s", match[1]))
+	}
+
+	date := time.UnixMilli(msg.InternalDate)
+	if match := labeledDateRegex.FindStringSubmatch(text); match != nil {
+		if parsed, e := time.ParseInLocation("02/01/2006", match[1], time.Local); e == nil {
+			date = parsed
+		}
+	}
+
+	confirmation := &PaymentConfirmation{
+		Method:      method,
+		Amount:      amount,
+		Date:        date,
+		Beneficiary: findBeneficiary(text),
+	}
+
+	if method == invoice_entity.Boleto {
+		confirmation.Barcode = findBarcode(text)
+	}
+
+	confirmation.Message = newEmailDataExtractorMessage(msg,
+		method.Name(), fmt.Sprintf("%.2f", confirmation.Amount.Float()), confirmation.Date.Format("2006-01-02"),
+		confirmation.Beneficiary, confirmation.Barcode)
+
+	return confirmation, nil
+}
+
+// findBeneficiary returns the value of the first beneficiary label, which the banks put either on
+// the same line ("Para: CORSAN") or on the next one.
+func findBeneficiary(text string) string {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		match := beneficiaryRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		value := strings.TrimSpace(match[1])
+		for j := i + 1; value == "" && j < len(lines); j++ {
+			value = strings.TrimSpace(lines[j])
+		}
+
+		for _, suffix := range []string{"CPF", "CNPJ", "Chave", "Instituição"} {
+			if index := strings.Index(value, suffix); index > 0 {
+				value = value[:index]
+			}
+		}
+
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	return ""
+}
@@ -1,17 +1,76 @@
 package email_service
 
 import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 	"google.golang.org/api/gmail/v1"
 )
 
+const (
+	FIND_MESSAGES_PAGE_SIZE = 100
+	// a search stops after this many messages, the newest ones, as each one costs a metadata request
+	FIND_MESSAGES_MAX_MESSAGES = 500
+	// metadata requests running at the same time when searching messages
+	FETCH_MESSAGES_CONCURRENCY = 10
+)
+
 type EmailServiceInterface interface {
-	FindMessages(subject string, address string, startDate string, endDate string) ([]*EmailServiceMessage, *internal_error.InternalError)
+	IsConnected() bool
+	// FindMessages returns the messages matching the request, newest first, with the From and
+	// Subject headers but without the body
+	FindMessages(request FindMessagesRequest) ([]*EmailServiceMessage, *internal_error.InternalError)
 	GetMessage(messageId string) (*EmailServiceMessage, *internal_error.InternalError)
 }
 
+type GmailServiceProviderInterface interface {
+	IsConnected(ctx context.Context, accountId string) bool
+	GetGmailService(ctx context.Context, accountId string) (*gmail.Service, *internal_error.InternalError)
+}
+
+type FindMessagesRequest struct {
+	Subject   string
+	Address   string
+	StartDate string
+	EndDate   string
+	Labels    []string // restricts the search to these labels/folders (e.g. "inbox", "contas")
+	Exclude   []string // search terms to negate (e.g. "in:trash", "label:processado")
+}
+
+func (r FindMessagesRequest) Query() string {
+	terms := make([]string, 0)
+
+	if r.Address != "" {
+		terms = append(terms, "from:"+r.Address)
+	}
+	if r.Subject != "" {
+		terms = append(terms, fmt.Sprintf("subject:\"%s\"", r.Subject))
+	}
+	if r.StartDate != "" {
+		terms = append(terms, "after:"+r.StartDate)
+	}
+	if r.EndDate != "" {
+		terms = append(terms, "before:"+r.EndDate)
+	}
+	for _, label := range r.Labels {
+		terms = append(terms, fmt.Sprintf("label:\"%s\"", label))
+	}
+	for _, exclude := range r.Exclude {
+		terms = append(terms, "-"+strings.TrimPrefix(exclude, "-"))
+	}
+
+	return strings.Join(terms, " ")
+}
+
 type EmailServiceMessage struct {
 	HistoryId       uint64                   `json:"historyId,omitempty,string"`
 	Id              string                   `json:"id,omitempty"`
@@ -26,6 +85,66 @@ type EmailServiceMessage struct {
 	NullFields      []string                 `json:"-"`
 }
 
+// Header returns the value of the named top level header (e.g. "From"), or "" when absent.
+func (m *EmailServiceMessage) Header(name string) string {
+	if m.Payload == nil {
+		return ""
+	}
+	for _, h := range m.Payload.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// Text returns the message body as plain text: the text/plain parts when present, otherwise
+// the text/html parts without tags, otherwise the snippet.
+func (m *EmailServiceMessage) Text() string {
+	if m.Payload != nil {
+		if text := m.Payload.text("text/plain"); text != "" {
+			return text
+		}
+		if html := m.Payload.text("text/html"); html != "" {
+			return htmlToText(html)
+		}
+	}
+	return m.Snippet
+}
+
+func (p *EmailServiceMessagePart) text(mimeType string) string {
+	if strings.HasPrefix(p.MimeType, mimeType) && p.Body != nil && p.Body.Data != "" {
+		data, err := base64.URLEncoding.DecodeString(p.Body.Data)
+		if err != nil {
+			data, err = base64.RawURLEncoding.DecodeString(p.Body.Data)
+		}
+		if err == nil {
+			return string(data)
+		}
+	}
+
+	texts := make([]string, 0)
+	for _, child := range p.Parts {
+		if text := child.text(mimeType); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+var (
+	htmlBreakRegex = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/li|/h[1-6])[^>]*>`)
+	htmlTagRegex   = regexp.MustCompile(`(?s)<style.*?</style>|<script.*?</script>|<[^>]+>`)
+)
+
+func htmlToText(html string) string {
+	text := htmlBreakRegex.ReplaceAllString(html, "\n")
+	text = htmlTagRegex.ReplaceAllString(text, " ")
+	return htmlEntities.Replace(text)
+}
+
+var htmlEntities = strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", "\"", "&#39;", "'")
+
 type EmailServiceMessagePart struct {
 	Body            *MessagePartBody           `json:"body,omitempty"`
 	Filename        string                     `json:"filename,omitempty"`
@@ -52,76 +171,138 @@ type MessagePartHeader struct {
 	NullFields      []string `json:"-"`
 }
 
-func NewGmailEmailService(gmailService *gmail.Service) EmailServiceInterface {
+func NewGmailEmailService(gmailServiceProvider GmailServiceProviderInterface, accountId string) EmailServiceInterface {
 	return &GmailEmailService{
-		gmailService: gmailService,
+		gmailServiceProvider: gmailServiceProvider,
+		accountId:            accountId,
 	}
 }
 
 type GmailEmailService struct {
-	gmailService *gmail.Service
+	gmailServiceProvider GmailServiceProviderInterface
+	accountId            string
 }
 
-func (g *GmailEmailService) FindMessages(subject string, address string, startDate string, endDate string) ([]*EmailServiceMessage, *internal_error.InternalError) {
-
-	messagesFound := make([]*EmailServiceMessage, 0)
+func (g *GmailEmailService) IsConnected() bool {
+	return g.gmailServiceProvider.IsConnected(context.Background(), g.accountId)
+}
 
-	query := "from:" + address + " subject:\"" + subject + "\" after:" + startDate + " before:" + endDate
+// FindMessages returns every message matching the request, across all result pages,
+// ordered from newest to oldest by InternalDate.
+func (g *GmailEmailService) FindMessages(request FindMessagesRequest) ([]*EmailServiceMessage, *internal_error.InternalError) {
 
-	mes, err := g.gmailService.Users.Messages.List("me").Q(query).Do()
+	gmailService, err := g.gmailServiceProvider.GetGmailService(context.Background(), g.accountId)
 	if err != nil {
-		log.Printf("Error Listing emails: %v", err)
-		return nil, internal_error.NewInternalServerError("Error listing emails")
+		return nil, err
 	}
 
-	log.Printf("Found %d messages", len(mes.Messages))
+	messageIds := make([]string, 0)
 
-	for _, message := range mes.Messages {
-		messagesFound = append(messagesFound, &EmailServiceMessage{
-			Id: message.Id,
-		})
+	query := request.Query()
+
+	log.Printf("Searching messages. Query: %s", query)
+
+	pageToken := ""
+
+	for {
+		call := gmailService.Users.Messages.List("me").Q(query).MaxResults(FIND_MESSAGES_PAGE_SIZE)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		mes, e := call.Do()
+		if e != nil {
+			log.Printf("Error Listing emails: %v", e)
+			return nil, internal_error.NewInternalServerError("Error listing emails")
+		}
+
+		for _, message := range mes.Messages {
+			messageIds = append(messageIds, message.Id)
+		}
+
+		if mes.NextPageToken == "" {
+			break
+		}
+
+		if len(messageIds) >= FIND_MESSAGES_MAX_MESSAGES {
+			log.Printf("Search limited to the newest %d messages. Query: %s", FIND_MESSAGES_MAX_MESSAGES, query)
+			messageIds = messageIds[:FIND_MESSAGES_MAX_MESSAGES]
+			break
+		}
+		pageToken = mes.NextPageToken
+	}
+
+	messagesFound, err := getMessagesMetadata(gmailService, messageIds)
+	if err != nil {
+		return nil, err
 	}
 
+	log.Printf("Found %d messages", len(messagesFound))
+
+	slices.SortStableFunc(messagesFound, func(a, b *EmailServiceMessage) int {
+		return cmp.Compare(b.InternalDate, a.InternalDate)
+	})
+
 	return messagesFound, nil
 }
 
-func (g *GmailEmailService) GetMessage(messageId string) (*EmailServiceMessage, *internal_error.InternalError) {
-	msg, err := g.gmailService.Users.Messages.Get("me", messageId).Do()
-	if err != nil {
-		log.Printf("Error getting message: %v", err)
-		return &EmailServiceMessage{}, internal_error.NewInternalServerError("Error getting message")
+// getMessagesMetadata gets the date and the From and Subject headers of the messages, which the
+// list does not return, a few messages at a time.
+func getMessagesMetadata(gmailService *gmail.Service, messageIds []string) ([]*EmailServiceMessage, *internal_error.InternalError) {
+	messages := make([]*EmailServiceMessage, len(messageIds))
+	errs := make([]error, len(messageIds))
+
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, FETCH_MESSAGES_CONCURRENCY)
+
+	for i, messageId := range messageIds {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			msg, e := gmailService.Users.Messages.Get("me", messageId).
+				Format("metadata").MetadataHeaders("From", "Subject").Do()
+			if e != nil {
+				errs[i] = e
+				return
+			}
+
+			messages[i] = &EmailServiceMessage{
+				Id:           msg.Id,
+				ThreadId:     msg.ThreadId,
+				HistoryId:    msg.HistoryId,
+				InternalDate: msg.InternalDate,
+				LabelIds:     msg.LabelIds,
+				Payload:      toEmailServiceMessagePart(msg.Payload),
+				Snippet:      msg.Snippet,
+				SizeEstimate: msg.SizeEstimate,
+			}
+		}()
 	}
 
-	headers := make([]*MessagePartHeader, 0)
+	wg.Wait()
 
-	for _, h := range msg.Payload.Headers {
-		headers = append(headers, &MessagePartHeader{
-			Name:            h.Name,
-			Value:           h.Value,
-			ForceSendFields: h.ForceSendFields,
-			NullFields:      h.NullFields,
-		})
+	if e := errors.Join(errs...); e != nil {
+		log.Printf("Error getting message metadata: %v", e)
+		return nil, internal_error.NewInternalServerError("Error getting message metadata")
 	}
 
-	parts := make([]*EmailServiceMessagePart, 0)
+	return messages, nil
+}
 
-	for _, p := range msg.Payload.Parts {
-		parts = append(parts, &EmailServiceMessagePart{
-			Body: &MessagePartBody{
-				AttachmentId:    p.Body.AttachmentId,
-				Data:            p.Body.Data,
-				Size:            p.Body.Size,
-				ForceSendFields: p.Body.ForceSendFields,
-				NullFields:      p.Body.NullFields,
-			},
-			Filename:        p.Filename,
-			Headers:         nil,
-			MimeType:        p.MimeType,
-			PartId:          p.PartId,
-			Parts:           nil,
-			ForceSendFields: p.ForceSendFields,
-			NullFields:      p.NullFields,
-		})
+func (g *GmailEmailService) GetMessage(messageId string) (*EmailServiceMessage, *internal_error.InternalError) {
+	gmailService, err := g.gmailServiceProvider.GetGmailService(context.Background(), g.accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, e := gmailService.Users.Messages.Get("me", messageId).Do()
+	if e != nil {
+		log.Printf("Error getting message: %v", e)
+		return &EmailServiceMessage{}, internal_error.NewInternalServerError("Error getting message")
 	}
 
 	return &EmailServiceMessage{
@@ -129,25 +310,53 @@ func (g *GmailEmailService) GetMessage(messageId string) (*EmailServiceMessage,
 		HistoryId:    msg.HistoryId,
 		InternalDate: msg.InternalDate,
 		LabelIds:     msg.LabelIds,
-		Payload: &EmailServiceMessagePart{
-			Body: &MessagePartBody{
-				AttachmentId:    msg.Payload.Body.AttachmentId,
-				Data:            msg.Payload.Body.Data,
-				Size:            msg.Payload.Body.Size,
-				ForceSendFields: msg.Payload.Body.ForceSendFields,
-				NullFields:      msg.Payload.Body.NullFields,
-			},
-			Filename:        msg.Payload.Filename,
-			Headers:         headers,
-			MimeType:        msg.Payload.MimeType,
-			PartId:          msg.Payload.PartId,
-			Parts:           parts,
-			ForceSendFields: msg.Payload.ForceSendFields,
-			NullFields:      msg.Payload.NullFields,
-		},
+		Payload:      toEmailServiceMessagePart(msg.Payload),
 		Raw:          msg.Raw,
 		SizeEstimate: msg.SizeEstimate,
 		Snippet:      msg.Snippet,
 		ThreadId:     msg.ThreadId,
 	}, nil
 }
+
+func toEmailServiceMessagePart(p *gmail.MessagePart) *EmailServiceMessagePart {
+	if p == nil {
+		return nil
+	}
+
+	headers := make([]*MessagePartHeader, 0)
+	for _, h := range p.Headers {
+		headers = append(headers, &MessagePartHeader{
+			Name:            h.Name,
+			Value:           h.Value,
+			ForceSendFields: h.ForceSendFields,
+			NullFields:      h.NullFields,
+		})
+	}
+
+	parts := make([]*EmailServiceMessagePart, 0)
+	for _, child := range p.Parts {
+		parts = append(parts, toEmailServiceMessagePart(child))
+	}
+
+	part := &EmailServiceMessagePart{
+		Filename:        p.Filename,
+		Headers:         headers,
+		MimeType:        p.MimeType,
+		PartId:          p.PartId,
+		Parts:           parts,
+		ForceSendFields: p.ForceSendFields,
+		NullFields:      p.NullFields,
+	}
+
+	if p.Body != nil {
+		part.Body = &MessagePartBody{
+			AttachmentId:    p.Body.AttachmentId,
+			Data:            p.Body.Data,
+			Size:            p.Body.Size,
+			ForceSendFields: p.Body.ForceSendFields,
+			NullFields:      p.Body.NullFields,
+		}
+	}
+
+	return part
+}
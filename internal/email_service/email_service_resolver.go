@@ -0,0 +1,54 @@
+package email_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type EmailServiceResolverInterface interface {
+	ResolveEmailService(
+		ctx context.Context,
+		mailAccountId string) (EmailServiceInterface, *internal_error.InternalError)
+}
+
+// EmailServiceResolver returns the email service that reads the mailbox of a mail account.
+// An empty mail account id resolves to the legacy Gmail mailbox.
+type EmailServiceResolver struct {
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface
+	gmailServiceProvider  GmailServiceProviderInterface
+}
+
+func NewEmailServiceResolver(
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface,
+	gmailServiceProvider GmailServiceProviderInterface) EmailServiceResolverInterface {
+
+	return &EmailServiceResolver{
+		mailAccountRepository: mailAccountRepository,
+		gmailServiceProvider:  gmailServiceProvider,
+	}
+}
+
+func (r *EmailServiceResolver) ResolveEmailService(
+	ctx context.Context,
+	mailAccountId string) (EmailServiceInterface, *internal_error.InternalError) {
+
+	if mailAccountId == "" {
+		return NewGmailEmailService(r.gmailServiceProvider, ""), nil
+	}
+
+	mailAccount, err := r.mailAccountRepository.FindMailAccountById(ctx, mailAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mailAccount.Provider {
+	case mail_account_entity.Gmail:
+		return NewGmailEmailService(r.gmailServiceProvider, mailAccount.Id), nil
+	default:
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("mail account provider %s not implemented yet", mailAccount.Provider.Name()))
+	}
+}
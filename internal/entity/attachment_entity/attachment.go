@@ -0,0 +1,125 @@
+package attachment_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Attachment describes a document (boleto, payment receipt...) kept in the blob storage
+// under StorageKey.
+type Attachment struct {
+	Id          string
+	InvoiceId   string
+	Kind        AttachmentKind
+	FileName    string
+	ContentType string
+	Size        int64
+	Checksum    string // sha256 of the content, hex encoded
+	StorageKey  string
+	CreatedAt   time.Time
+}
+
+type AttachmentKind uint8
+
+const (
+	Boleto AttachmentKind = iota + 1
+	Receipt
+	Other
+)
+
+func (k AttachmentKind) Name() string {
+	return attachmentKindNames[k]
+}
+
+var attachmentKindNames = []string{
+	"",
+	"boleto",
+	"receipt",
+	"other",
+}
+
+func GetAttachmentKindByName(name string) (AttachmentKind, *internal_error.InternalError) {
+	for k, v := range attachmentKindNames {
+		if v == name {
+			return AttachmentKind(k), nil
+		}
+	}
+
+	return AttachmentKind(0), internal_error.NewBadRequestError("invalid attachment kind name")
+}
+
+// NewStorageKey returns a new attachment id and the blob storage key of its content.
+func NewStorageKey(invoiceId string) (string, string) {
+	id := uuid.New().String()
+	return id, storageKey(invoiceId, id)
+}
+
+func storageKey(invoiceId string, id string) string {
+	return "invoices/" + invoiceId + "/" + id
+}
+
+func CreateAttachment(
+	id string,
+	invoiceId string,
+	kind string,
+	fileName string,
+	contentType string,
+	size int64,
+	checksum string) (*Attachment, *internal_error.InternalError) {
+
+	var attachmentKind AttachmentKind
+
+	if kind == "" {
+		attachmentKind = Other
+	} else {
+		kind, err := GetAttachmentKindByName(kind)
+		if err != nil {
+			return nil, err
+		}
+		attachmentKind = kind
+	}
+
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	attachment :=
+		&Attachment{
+			Id:          id,
+			InvoiceId:   invoiceId,
+			Kind:        attachmentKind,
+			FileName:    fileName,
+			ContentType: contentType,
+			Size:        size,
+			Checksum:    checksum,
+			StorageKey:  storageKey(invoiceId, id),
+			CreatedAt:   time.Now(),
+		}
+
+	if err := attachment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (attachment *Attachment) Validate() *internal_error.InternalError {
+	if attachment.InvoiceId == "" || attachment.Kind == 0 {
+		return internal_error.NewBadRequestError("invalid attachment object")
+	}
+	if attachment.Size <= 0 || len(attachment.Checksum) != 64 {
+		return internal_error.NewBadRequestError("invalid attachment object. empty content")
+	}
+
+	return nil
+}
+
+type AttachmentRepositoryInterface interface {
+	CreateAttachment(ctx context.Context, attachmentEntity *Attachment) *internal_error.InternalError
+	FindAttachmentById(ctx context.Context, attachmentId string) (*Attachment, *internal_error.InternalError)
+	FindAttachments(ctx context.Context, invoiceId string) ([]*Attachment, *internal_error.InternalError)
+	DeleteAttachment(ctx context.Context, attachmentId string) *internal_error.InternalError
+}
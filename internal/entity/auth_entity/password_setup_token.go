@@ -0,0 +1,52 @@
+package auth_entity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// PasswordSetupToken allows a user created before authentication existed to define the first
+// password. It can be used only once and, like the refresh tokens, only its hash is stored.
+type PasswordSetupToken struct {
+	Id        string
+	UserId    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
+
+// CreatePasswordSetupToken returns the password setup token of the user and its value, which is
+// handed to the user.
+func CreatePasswordSetupToken(userId string, duration time.Duration) (*PasswordSetupToken, string, *internal_error.InternalError) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return nil, "", internal_error.NewInternalServerError("Error trying to create password setup token")
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(value)
+
+	setupToken := &PasswordSetupToken{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		TokenHash: HashRefreshToken(token),
+		ExpiresAt: time.Now().Add(duration),
+		CreatedAt: time.Now(),
+	}
+
+	return setupToken, token, nil
+}
+
+type PasswordSetupTokenRepositoryInterface interface {
+	CreatePasswordSetupToken(ctx context.Context, setupToken *PasswordSetupToken) *internal_error.InternalError
+	// HasActivePasswordSetupToken reports whether the user has a token that was not used and has not expired
+	HasActivePasswordSetupToken(ctx context.Context, userId string) (bool, *internal_error.InternalError)
+	// UsePasswordSetupToken marks the unused and not expired token as used, returning it. Concurrent
+	// calls with the same token get it only once.
+	UsePasswordSetupToken(ctx context.Context, tokenHash string) (*PasswordSetupToken, *internal_error.InternalError)
+}
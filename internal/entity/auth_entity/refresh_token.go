@@ -0,0 +1,67 @@
+package auth_entity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// RefreshToken allows a user to get new access tokens without informing the password again. Only
+// the hash of the token is stored, and every use replaces it by a new one.
+type RefreshToken struct {
+	Id        string
+	UserId    string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+	CreatedAt time.Time
+}
+
+// CreateRefreshToken returns the refresh token of the user and its value, which is only known by
+// the client.
+func CreateRefreshToken(userId string, duration time.Duration) (*RefreshToken, string, *internal_error.InternalError) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return nil, "", internal_error.NewInternalServerError("Error trying to create refresh token")
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(value)
+
+	refreshToken := &RefreshToken{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		TokenHash: HashRefreshToken(token),
+		ExpiresAt: time.Now().Add(duration),
+		CreatedAt: time.Now(),
+	}
+
+	return refreshToken, token, nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return !t.IsRevoked() && now.Before(t.ExpiresAt)
+}
+
+type RefreshTokenRepositoryInterface interface {
+	CreateRefreshToken(ctx context.Context, refreshToken *RefreshToken) *internal_error.InternalError
+	// RevokeRefreshToken revokes the token when it is not revoked yet, returning it as it was before
+	RevokeRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, *internal_error.InternalError)
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, *internal_error.InternalError)
+	// RevokeUserRefreshTokens ends every session of the user
+	RevokeUserRefreshTokens(ctx context.Context, userId string) *internal_error.InternalError
+}
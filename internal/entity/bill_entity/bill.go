@@ -2,6 +2,8 @@ package bill_entity
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,12 +17,122 @@ type Bill struct {
 	Company         string
 	ValueSourceType ValueSourceType
 	ValueSourceId   string
+	CategoryId      string
+	Tags            []string
 	DueDay          uint8
+	Recurrence      Recurrence
+	DueDateRule     DueDateRule
+	LatePaymentRule LatePaymentRule
+	PaymentMode     PaymentMode
+	Split           SplitRule
+	Suspensions     []Suspension
 	Status          BillStatus
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
+// LatePaymentRule holds the charges applied to invoices paid after the due date:
+// a one-off fine (multa) and a monthly interest (juros de mora) charged pro rata die.
+type LatePaymentRule struct {
+	FinePercentage            float64
+	MonthlyInterestPercentage float64
+}
+
+// DueDateRule moves the due dates that are not business days in the city of the bill, as the
+// banks do with boletos. Bills saved before the rule existed have no adjustment.
+type DueDateRule struct {
+	Adjustment DueDateAdjustment
+	State      string // UF of the custom holidays observed, e.g. RS
+	City       string
+}
+
+type DueDateAdjustment uint8
+
+const (
+	NoAdjustment DueDateAdjustment = iota + 1
+	NextBusinessDay
+	PreviousBusinessDay
+)
+
+func (a DueDateAdjustment) Name() string {
+	return dueDateAdjustmentNames[a]
+}
+
+var dueDateAdjustmentNames = []string{
+	"",
+	"none",
+	"next_business_day",
+	"previous_business_day",
+}
+
+func GetDueDateAdjustmentByName(name string) (DueDateAdjustment, *internal_error.InternalError) {
+	for k, v := range dueDateAdjustmentNames {
+		if v == name {
+			return DueDateAdjustment(k), nil
+		}
+	}
+
+	return DueDateAdjustment(0), internal_error.NewBadRequestError("invalid bill dueDateRule adjustment name",
+		internal_error.Cause{Field: "dueDateRule.adjustment", Message: "adjustment must be one of none, next_business_day or previous_business_day"})
+}
+
+// CreateDueDateRule returns the rule with the adjustment, none when it is empty.
+func CreateDueDateRule(adjustment string, state string, city string) (DueDateRule, *internal_error.InternalError) {
+	rule := DueDateRule{
+		Adjustment: NoAdjustment,
+		State:      strings.ToUpper(strings.TrimSpace(state)),
+		City:       strings.TrimSpace(city),
+	}
+
+	if adjustment != "" {
+		adjustment, err := GetDueDateAdjustmentByName(adjustment)
+		if err != nil {
+			return rule, err
+		}
+		rule.Adjustment = adjustment
+	}
+
+	return rule, nil
+}
+
+// PaymentMode tells how the invoices of the bill are paid. Invoices of bills charged
+// automatically (débito automático or credit card) are paid on the due date with no action, so
+// instead of a reminder to pay them there is a notice to check that the charge happened.
+type PaymentMode uint8
+
+const (
+	Manual PaymentMode = iota + 1
+	AutomaticDebit
+	CreditCard
+)
+
+func (m PaymentMode) Name() string {
+	return paymentModeNames[m]
+}
+
+var paymentModeNames = []string{
+	"",
+	"manual",
+	"automatic_debit",
+	"credit_card",
+}
+
+func GetPaymentModeByName(name string) (PaymentMode, *internal_error.InternalError) {
+	for k, v := range paymentModeNames {
+		if v == name {
+			return PaymentMode(k), nil
+		}
+	}
+
+	return PaymentMode(0), internal_error.NewBadRequestError("invalid bill paymentMode name",
+		internal_error.Cause{Field: "paymentMode", Message: "paymentMode must be one of manual, automatic_debit or credit_card"})
+}
+
+// IsAutomatic reports whether the invoices are charged automatically on the due date.
+func (m PaymentMode) IsAutomatic() bool {
+	return m == AutomaticDebit || m == CreditCard
+}
+
 type BillStatus uint8
 
 const (
@@ -53,7 +165,8 @@ func GetBillStatusByName(name string) (BillStatus, *internal_error.InternalError
 		}
 	}
 
-	return BillStatus(0), internal_error.NewBadRequestError("invalid bill status name")
+	return BillStatus(0), internal_error.NewBadRequestError("invalid bill status name",
+		internal_error.Cause{Field: "status", Message: "status must be one of active or inactive"})
 }
 
 func (s ValueSourceType) Name() string {
@@ -73,7 +186,8 @@ func GetValueSourceTypeByName(name string) (ValueSourceType, *internal_error.Int
 		}
 	}
 
-	return ValueSourceType(0), internal_error.NewBadRequestError("invalid bill valueSourceType name")
+	return ValueSourceType(0), internal_error.NewBadRequestError("invalid bill valueSourceType name",
+		internal_error.Cause{Field: "valueSourceType", Message: "valueSourceType must be one of table, email or api"})
 }
 
 func CreateBill(
@@ -82,7 +196,14 @@ func CreateBill(
 	company string,
 	valueSourceType string,
 	valueSourceId string,
+	categoryId string,
+	tags []string,
 	dueDay uint8,
+	recurrence Recurrence,
+	dueDateRule DueDateRule,
+	latePaymentRule LatePaymentRule,
+	paymentMode string,
+	split SplitRule,
 	status string) (*Bill, *internal_error.InternalError) {
 
 	var billStatus BillStatus
@@ -97,10 +218,21 @@ func CreateBill(
 		billStatus = status
 	}
 
+	billPaymentMode := Manual
+
+	if paymentMode != "" {
+		paymentMode, err := GetPaymentModeByName(paymentMode)
+		if err != nil {
+			return nil, err
+		}
+		billPaymentMode = paymentMode
+	}
+
 	var billValueSourceType ValueSourceType
 
 	if valueSourceType == "" {
-		return nil, internal_error.NewBadRequestError("invalid bill value source type")
+		return nil, internal_error.NewBadRequestError("invalid bill value source type",
+			internal_error.Cause{Field: "valueSourceType", Message: "valueSourceType is required"})
 	} else {
 		valueSourceType, err := GetValueSourceTypeByName(valueSourceType)
 		if err != nil {
@@ -117,7 +249,14 @@ func CreateBill(
 			Company:         company,
 			ValueSourceType: billValueSourceType,
 			ValueSourceId:   valueSourceId,
+			CategoryId:      categoryId,
+			Tags:            NormalizeTags(tags),
 			DueDay:          dueDay,
+			Recurrence:      recurrence,
+			DueDateRule:     dueDateRule,
+			LatePaymentRule: latePaymentRule,
+			PaymentMode:     billPaymentMode,
+			Split:           split,
 			Status:          billStatus,
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
@@ -130,24 +269,204 @@ func CreateBill(
 	return bill, nil
 }
 
+// Update changes the informed fields, keeping the current value of the empty ones.
+func (bill *Bill) Update(
+	name string,
+	company string,
+	valueSourceType string,
+	valueSourceId string,
+	categoryId *string,
+	tags []string,
+	dueDay uint8,
+	recurrence *Recurrence,
+	dueDateRule *DueDateRule,
+	latePaymentRule *LatePaymentRule,
+	paymentMode string,
+	split *SplitRule) *internal_error.InternalError {
+
+	if name != "" {
+		bill.Name = name
+	}
+
+	if company != "" {
+		bill.Company = company
+	}
+
+	if valueSourceType != "" {
+		valueSourceType, err := GetValueSourceTypeByName(valueSourceType)
+		if err != nil {
+			return err
+		}
+		bill.ValueSourceType = valueSourceType
+	}
+
+	if valueSourceId != "" {
+		bill.ValueSourceId = valueSourceId
+	}
+
+	if categoryId != nil {
+		bill.CategoryId = *categoryId
+	}
+
+	if tags != nil {
+		bill.Tags = NormalizeTags(tags)
+	}
+
+	if dueDay != 0 {
+		bill.DueDay = dueDay
+	}
+
+	if recurrence != nil {
+		bill.Recurrence = *recurrence
+	}
+
+	if dueDateRule != nil {
+		bill.DueDateRule = *dueDateRule
+	}
+
+	if latePaymentRule != nil {
+		bill.LatePaymentRule = *latePaymentRule
+	}
+
+	if paymentMode != "" {
+		paymentMode, err := GetPaymentModeByName(paymentMode)
+		if err != nil {
+			return err
+		}
+		bill.PaymentMode = paymentMode
+	}
+
+	if split != nil {
+		bill.Split = *split
+	}
+
+	bill.UpdatedAt = time.Now()
+
+	if err := bill.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NormalizeTags returns the tags in lower case, without blanks or repetitions.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
+
+// UpdateStatus activates or deactivates the bill. Inactive bills are not processed.
+func (bill *Bill) UpdateStatus(status string) *internal_error.InternalError {
+	billStatus, err := GetBillStatusByName(status)
+	if err != nil || billStatus == 0 {
+		return internal_error.NewBadRequestError("invalid bill status name")
+	}
+
+	bill.Status = billStatus
+	bill.UpdatedAt = time.Now()
+
+	return nil
+}
+
 func (bill *Bill) Validate() *internal_error.InternalError {
-	if len(bill.Name) < 3 ||
-		len(bill.Company) < 3 {
-		return internal_error.NewBadRequestError("invalid bill object: invalid name or company")
+	causes := make([]internal_error.Cause, 0)
+
+	if bill.UserId == "" {
+		causes = append(causes, internal_error.Cause{Field: "userId", Message: "userId is required"})
+	}
+	if len(bill.Name) < 3 {
+		causes = append(causes, internal_error.Cause{Field: "name", Message: "name must be at least 3 characters in length"})
+	}
+	if len(bill.Company) < 3 {
+		causes = append(causes, internal_error.Cause{Field: "company", Message: "company must be at least 3 characters in length"})
+	}
+	if bill.ValueSourceId == "" {
+		causes = append(causes, internal_error.Cause{Field: "valueSourceId", Message: "valueSourceId is required"})
+	}
+	if bill.DueDay < 1 || bill.DueDay > 31 {
+		causes = append(causes, internal_error.Cause{Field: "dueDay", Message: "dueDay must be between 1 and 31"})
+	}
+	if bill.DueDateRule.State != "" && len(bill.DueDateRule.State) != 2 {
+		causes = append(causes, internal_error.Cause{Field: "dueDateRule.state", Message: "state must be an UF, e.g. RS"})
+	}
+	if bill.LatePaymentRule.FinePercentage < 0 || bill.LatePaymentRule.FinePercentage > 100 {
+		causes = append(causes, internal_error.Cause{Field: "latePaymentRule.finePercentage", Message: "finePercentage must be between 0 and 100"})
 	}
-	if bill.DueDay > 28 {
-		return internal_error.NewBadRequestError("invalid bill object: invalid due day. must be between 1 and 28")
+	if bill.LatePaymentRule.MonthlyInterestPercentage < 0 || bill.LatePaymentRule.MonthlyInterestPercentage > 100 {
+		causes = append(causes, internal_error.Cause{Field: "latePaymentRule.monthlyInterestPercentage", Message: "monthlyInterestPercentage must be between 0 and 100"})
+	}
+	if err := bill.Recurrence.Validate(); err != nil {
+		causes = append(causes, err.Causes...)
+	}
+	if err := bill.Split.Validate(); err != nil {
+		causes = append(causes, err.Causes...)
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid bill object", causes...)
 	}
 
 	return nil
 }
 
+// DueDate returns the due date of the invoice of the period (in the following month) and
+// whether the recurrence of the bill has a due date in that month. Due days after the end of the
+// month fall on its last day (31 is February 28th or 29th). The date is not adjusted to business
+// days, which depends on the calendar of the bill's city.
+func (bill *Bill) DueDate(period time.Time) (time.Time, bool) {
+	dueMonth := time.Date(period.Year(), period.Month()+1, 1, 0, 0, 0, 0, time.Local)
+	lastDay := dueMonth.AddDate(0, 1, -1).Day()
+	dueDate := time.Date(dueMonth.Year(), dueMonth.Month(), min(int(bill.DueDay), lastDay), 0, 0, 0, 0, time.Local)
+
+	return dueDate, bill.Recurrence.IsDueIn(dueMonth)
+}
+
+// NextDueDate returns the first due date of the bill on or after the day of from, skipping the
+// suspended periods, or false when the bill is not due anymore (e.g. a one-off bill already due).
+func (bill *Bill) NextDueDate(from time.Time) (time.Time, bool) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	period := time.Date(from.Year(), from.Month()-1, 1, 0, 0, 0, 0, time.Local)
+
+	for i := 0; i < maxMonthsToNextDueDate; i++ {
+		dueDate, due := bill.DueDate(period.AddDate(0, i, 0))
+		if due && !dueDate.Before(day) && bill.SuspensionIn(period.AddDate(0, i, 0).Format("2006-01")) == nil {
+			return dueDate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// BillFilter selects the bills matching all the informed fields. A nil CategoryIds matches any
+// category, and an empty one matches no bill.
+type BillFilter struct {
+	Status      BillStatus
+	UserId      string
+	Name        string
+	Company     string
+	CategoryIds []string
+	Tag         string
+}
+
 type BillRepositoryInterface interface {
 	CreateBill(ctx context.Context, billEntity *Bill) *internal_error.InternalError
 	FindBillById(ctx context.Context, billId string) (*Bill, *internal_error.InternalError)
 	FindBills(
 		ctx context.Context,
-		status BillStatus,
-		userId string,
-		name, company string) ([]*Bill, *internal_error.InternalError)
+		filter BillFilter) ([]*Bill, *internal_error.InternalError)
+	// UpdateBill updates the bill, keeping the suspensions.
+	UpdateBill(ctx context.Context, billEntity *Bill) *internal_error.InternalError
+	// AddBillSuspension adds the suspension when no other one of the bill overlaps it.
+	AddBillSuspension(ctx context.Context, billId string, suspension Suspension) *internal_error.InternalError
+	RemoveBillSuspension(ctx context.Context, billId string, suspensionId string) *internal_error.InternalError
+	DeleteBill(ctx context.Context, billId string) *internal_error.InternalError
 }
@@ -0,0 +1,161 @@
+package bill_entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Recurrence tells in which months the bill is due. The months are the ones of the due dates,
+// not of the competence periods: the invoice of a period is due in the following month.
+type Recurrence struct {
+	Type       RecurrenceType
+	Interval   uint8        // every_n_months: months between two due dates
+	Month      time.Month   // yearly: month of the due date
+	Months     []time.Month // specific_months: months of the due dates
+	StartMonth string       // every_n_months: first due month; one_off: the only due month (YYYY-MM)
+}
+
+type RecurrenceType uint8
+
+const (
+	Monthly RecurrenceType = iota + 1
+	EveryNMonths
+	Yearly
+	SpecificMonths
+	OneOff
+)
+
+// due dates searched by NextDueDate, enough for any yearly bill
+const maxMonthsToNextDueDate = 12 * 10
+
+func (t RecurrenceType) Name() string {
+	return recurrenceTypeNames[t]
+}
+
+var recurrenceTypeNames = []string{
+	"",
+	"monthly",
+	"every_n_months",
+	"yearly",
+	"specific_months",
+	"one_off",
+}
+
+func GetRecurrenceTypeByName(name string) (RecurrenceType, *internal_error.InternalError) {
+	for k, v := range recurrenceTypeNames {
+		if v == name {
+			return RecurrenceType(k), nil
+		}
+	}
+
+	return RecurrenceType(0), internal_error.NewBadRequestError("invalid bill recurrence type name",
+		internal_error.Cause{Field: "recurrence.type", Message: "type must be one of monthly, every_n_months, yearly, specific_months or one_off"})
+}
+
+// CreateRecurrence returns the recurrence of the type, monthly when it is empty.
+func CreateRecurrence(
+	recurrenceType string,
+	interval uint8,
+	month time.Month,
+	months []time.Month,
+	startMonth string) (Recurrence, *internal_error.InternalError) {
+
+	recurrence := Recurrence{Type: Monthly}
+
+	if recurrenceType != "" {
+		recurrenceType, err := GetRecurrenceTypeByName(recurrenceType)
+		if err != nil {
+			return recurrence, err
+		}
+		recurrence.Type = recurrenceType
+	}
+
+	switch recurrence.Type {
+	case EveryNMonths:
+		recurrence.Interval = interval
+		recurrence.StartMonth = startMonth
+	case Yearly:
+		recurrence.Month = month
+	case SpecificMonths:
+		recurrence.Months = slices.Clone(months)
+		slices.Sort(recurrence.Months)
+		recurrence.Months = slices.Compact(recurrence.Months)
+	case OneOff:
+		recurrence.StartMonth = startMonth
+	}
+
+	if err := recurrence.Validate(); err != nil {
+		return recurrence, err
+	}
+
+	return recurrence, nil
+}
+
+// IsDueIn tells whether the bill has a due date in the month of dueMonth. Bills saved before
+// recurrences existed have no type and are monthly.
+func (r Recurrence) IsDueIn(dueMonth time.Time) bool {
+	switch r.Type {
+	case EveryNMonths:
+		start, err := time.ParseInLocation("2006-01", r.StartMonth, time.Local)
+		if err != nil {
+			return false
+		}
+		months := monthsBetween(start, dueMonth)
+		return months >= 0 && months%int(r.Interval) == 0
+	case Yearly:
+		return dueMonth.Month() == r.Month
+	case SpecificMonths:
+		return slices.Contains(r.Months, dueMonth.Month())
+	case OneOff:
+		return dueMonth.Format("2006-01") == r.StartMonth
+	}
+
+	return true
+}
+
+func (r Recurrence) Validate() *internal_error.InternalError {
+	causes := make([]internal_error.Cause, 0)
+
+	switch r.Type {
+	case Monthly:
+	case EveryNMonths:
+		if r.Interval < 2 || r.Interval > 60 {
+			causes = append(causes, internal_error.Cause{Field: "recurrence.interval", Message: "interval must be between 2 and 60 months"})
+		}
+		if _, err := time.Parse("2006-01", r.StartMonth); err != nil {
+			causes = append(causes, internal_error.Cause{Field: "recurrence.startMonth", Message: "startMonth must be in the format YYYY-MM"})
+		}
+	case Yearly:
+		if r.Month < time.January || r.Month > time.December {
+			causes = append(causes, internal_error.Cause{Field: "recurrence.month", Message: "month must be between 1 and 12"})
+		}
+	case SpecificMonths:
+		if len(r.Months) == 0 {
+			causes = append(causes, internal_error.Cause{Field: "recurrence.months", Message: "months must not be empty"})
+		}
+		for _, month := range r.Months {
+			if month < time.January || month > time.December {
+				causes = append(causes, internal_error.Cause{Field: "recurrence.months", Message: "months must be between 1 and 12"})
+				break
+			}
+		}
+	case OneOff:
+		if _, err := time.Parse("2006-01", r.StartMonth); err != nil {
+			causes = append(causes, internal_error.Cause{Field: "recurrence.startMonth", Message: "startMonth must be in the format YYYY-MM"})
+		}
+	default:
+		causes = append(causes, internal_error.Cause{Field: "recurrence.type", Message: "type must be one of monthly, every_n_months, yearly, specific_months or one_off"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid bill recurrence", causes...)
+	}
+
+	return nil
+}
+
+func monthsBetween(start time.Time, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+}
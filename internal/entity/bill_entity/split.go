@@ -0,0 +1,205 @@
+package bill_entity
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// SplitRule divides the invoices of a bill shared by several users, e.g. roommates. A bill
+// without a split rule is owed entirely by its owner (UserId).
+type SplitRule struct {
+	Type   SplitType
+	Shares []Share
+}
+
+// Share is the part of a user in a split rule: a percentage of the invoice in percentage rules
+// and an amount in fixed rules. Equal rules only list the users.
+type Share struct {
+	UserId     string
+	Percentage float64
+	Amount     money.Money
+}
+
+type SplitType uint8
+
+const (
+	EqualSplit SplitType = iota + 1
+	PercentageSplit
+	FixedSplit
+)
+
+func (t SplitType) Name() string {
+	return splitTypeNames[t]
+}
+
+var splitTypeNames = []string{
+	"",
+	"equal",
+	"percentage",
+	"fixed",
+}
+
+func GetSplitTypeByName(name string) (SplitType, *internal_error.InternalError) {
+	for k, v := range splitTypeNames {
+		if v == name {
+			return SplitType(k), nil
+		}
+	}
+
+	return SplitType(0), internal_error.NewBadRequestError("invalid bill split type name",
+		internal_error.Cause{Field: "split.type", Message: "type must be one of equal, percentage or fixed"})
+}
+
+// CreateSplitRule returns the rule of the type. An empty type with no shares means the bill is
+// not shared.
+func CreateSplitRule(splitType string, shares []Share) (SplitRule, *internal_error.InternalError) {
+	if splitType == "" && len(shares) == 0 {
+		return SplitRule{}, nil
+	}
+
+	rule := SplitRule{Shares: shares}
+
+	if splitType == "" {
+		return rule, internal_error.NewBadRequestError("invalid bill split",
+			internal_error.Cause{Field: "split.type", Message: "type is required"})
+	}
+
+	ruleType, err := GetSplitTypeByName(splitType)
+	if err != nil {
+		return rule, err
+	}
+	rule.Type = ruleType
+
+	if err := rule.Validate(); err != nil {
+		return rule, err
+	}
+
+	return rule, nil
+}
+
+func (rule SplitRule) IsShared() bool {
+	return rule.Type != 0
+}
+
+func (rule SplitRule) Validate() *internal_error.InternalError {
+	if !rule.IsShared() {
+		return nil
+	}
+
+	causes := make([]internal_error.Cause, 0)
+
+	minShares := 2
+	if rule.Type == FixedSplit {
+		minShares = 1 // the owner pays what is left
+	}
+	if len(rule.Shares) < minShares {
+		causes = append(causes, internal_error.Cause{Field: "split.shares",
+			Message: fmt.Sprintf("a %s split needs at least %d users", rule.Type.Name(), minShares)})
+	}
+
+	userIds := make([]string, 0, len(rule.Shares))
+	var totalPercentage float64
+
+	for i, share := range rule.Shares {
+		field := fmt.Sprintf("split.shares[%d]", i)
+
+		if share.UserId == "" {
+			causes = append(causes, internal_error.Cause{Field: field + ".userId", Message: "userId is required"})
+		} else if slices.Contains(userIds, share.UserId) {
+			causes = append(causes, internal_error.Cause{Field: field + ".userId", Message: "user already has a share"})
+		}
+		userIds = append(userIds, share.UserId)
+
+		switch rule.Type {
+		case PercentageSplit:
+			if share.Percentage <= 0 || share.Percentage > 100 {
+				causes = append(causes, internal_error.Cause{Field: field + ".percentage", Message: "percentage must be greater than 0 and at most 100"})
+			}
+			totalPercentage += share.Percentage
+		case FixedSplit:
+			if share.Amount <= 0 {
+				causes = append(causes, internal_error.Cause{Field: field + ".amount", Message: "amount must be greater than 0"})
+			}
+		}
+	}
+
+	if rule.Type == PercentageSplit && math.Abs(totalPercentage-100) > 0.001 {
+		causes = append(causes, internal_error.Cause{Field: "split.shares", Message: "the percentages must add up to 100"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid bill split", causes...)
+	}
+
+	return nil
+}
+
+// Users returns the owner of the bill and the users it is shared with.
+func (bill *Bill) Users() []string {
+	users := []string{bill.UserId}
+	for _, share := range bill.Split.Shares {
+		if !slices.Contains(users, share.UserId) {
+			users = append(users, share.UserId)
+		}
+	}
+	return users
+}
+
+// InvoiceShares divides an invoice amount among the users of the bill. In fixed splits the
+// owner owes what is left after the fixed amounts; when the invoice is smaller than them, it is
+// divided in proportion to the fixed amounts.
+func (bill *Bill) InvoiceShares(amount money.Money) []invoice_entity.Share {
+	rule := bill.Split
+	if !rule.IsShared() || len(rule.Shares) == 0 {
+		return []invoice_entity.Share{{UserId: bill.UserId, Amount: amount}}
+	}
+
+	weights := make([]float64, len(rule.Shares))
+	var fixedTotal money.Money
+	for i, share := range rule.Shares {
+		switch rule.Type {
+		case EqualSplit:
+			weights[i] = 1
+		case PercentageSplit:
+			weights[i] = share.Percentage
+		case FixedSplit:
+			weights[i] = float64(share.Amount)
+			fixedTotal += share.Amount
+		}
+	}
+
+	shares := make([]invoice_entity.Share, len(rule.Shares))
+
+	if rule.Type == FixedSplit && amount >= fixedTotal {
+		for i, share := range rule.Shares {
+			shares[i] = invoice_entity.Share{UserId: share.UserId, Amount: share.Amount}
+		}
+		return addShare(shares, bill.UserId, amount-fixedTotal)
+	}
+
+	for i, part := range amount.Allocate(weights) {
+		shares[i] = invoice_entity.Share{UserId: rule.Shares[i].UserId, Amount: part}
+	}
+
+	return shares
+}
+
+func addShare(shares []invoice_entity.Share, userId string, amount money.Money) []invoice_entity.Share {
+	if amount == 0 {
+		return shares
+	}
+
+	for i := range shares {
+		if shares[i].UserId == userId {
+			shares[i].Amount += amount
+			return shares
+		}
+	}
+
+	return append(shares, invoice_entity.Share{UserId: userId, Amount: amount})
+}
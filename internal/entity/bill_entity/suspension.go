@@ -0,0 +1,84 @@
+package bill_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Suspension is a window of competence periods (YYYY-MM, inclusive) in which the bill is not
+// charged, e.g. a paused gym contract. The bill stays active: it is skipped by the processing of
+// the periods in the window and is charged again, with no action, from the following period.
+type Suspension struct {
+	Id         string
+	FromPeriod string
+	ToPeriod   string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+func (s Suspension) Contains(period string) bool {
+	return s.FromPeriod <= period && period <= s.ToPeriod
+}
+
+func (s Suspension) Validate() *internal_error.InternalError {
+	if _, err := time.Parse("2006-01", s.FromPeriod); err != nil {
+		return internal_error.NewBadRequestError("invalid bill suspension",
+			internal_error.Cause{Field: "from", Message: "from must be in the format YYYY-MM"})
+	}
+	if _, err := time.Parse("2006-01", s.ToPeriod); err != nil {
+		return internal_error.NewBadRequestError("invalid bill suspension",
+			internal_error.Cause{Field: "to", Message: "to must be in the format YYYY-MM"})
+	}
+	if s.ToPeriod < s.FromPeriod {
+		return internal_error.NewBadRequestError("invalid bill suspension",
+			internal_error.Cause{Field: "to", Message: "to must not be before from"})
+	}
+
+	return nil
+}
+
+// AddSuspension suspends the bill from the period fromPeriod to toPeriod. Windows of the same
+// bill cannot overlap.
+func (bill *Bill) AddSuspension(
+	fromPeriod string,
+	toPeriod string,
+	reason string) (*Suspension, *internal_error.InternalError) {
+
+	suspension := Suspension{
+		Id:         uuid.New().String(),
+		FromPeriod: fromPeriod,
+		ToPeriod:   toPeriod,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := suspension.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, other := range bill.Suspensions {
+		if other.FromPeriod <= suspension.ToPeriod && suspension.FromPeriod <= other.ToPeriod {
+			return nil, internal_error.NewBadRequestError("invalid bill suspension",
+				internal_error.Cause{Field: "from", Message: "the bill is already suspended from " + other.FromPeriod + " to " + other.ToPeriod})
+		}
+	}
+
+	bill.Suspensions = append(bill.Suspensions, suspension)
+	bill.UpdatedAt = time.Now()
+
+	return &suspension, nil
+}
+
+// SuspensionIn returns the suspension window that contains the period (YYYY-MM), or nil when
+// the bill is not suspended in it.
+func (bill *Bill) SuspensionIn(period string) *Suspension {
+	for i := range bill.Suspensions {
+		if bill.Suspensions[i].Contains(period) {
+			return &bill.Suspensions[i]
+		}
+	}
+
+	return nil
+}
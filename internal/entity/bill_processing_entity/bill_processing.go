@@ -6,14 +6,83 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type BillProcessing struct {
-	Id        string
-	Status    BillProcessingStatus
-	Period    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id          string
+	Status      BillProcessingStatus
+	Period      string
+	BillResults []*BillResult
+	// BudgetAlerts are the categories whose budget was exceeded in the period when the processing
+	// finished
+	BudgetAlerts []*BudgetAlert
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// BillResult is the outcome of processing one bill. It is saved in the same transaction as the
+// invoice of the bill, so a bill with a processed result had all its changes applied.
+type BillResult struct {
+	BillId      string
+	Status      BillResultStatus
+	Error       string
+	Reason      string // why a skipped bill was not processed
+	ProcessedAt time.Time
+}
+
+// BudgetAlert reports a category whose invoices of the period exceed its monthly budget.
+type BudgetAlert struct {
+	CategoryId    string
+	Spent         money.Money
+	MonthlyBudget money.Money
+	Message       string
+}
+
+type BillResultStatus uint8
+
+const (
+	Processed BillResultStatus = iota + 1
+	Failed
+	Skipped
+)
+
+func (s BillResultStatus) Name() string {
+	return billResultStatusNames[s]
+}
+
+var billResultStatusNames = []string{
+	"",
+	"processed",
+	"failed",
+	"skipped",
+}
+
+// NewBillResult returns the result of processing the bill, failed when err is not nil.
+func NewBillResult(billId string, err *internal_error.InternalError) *BillResult {
+	result := &BillResult{
+		BillId:      billId,
+		Status:      Processed,
+		ProcessedAt: time.Now(),
+	}
+
+	if err != nil {
+		result.Status = Failed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// NewSkippedBillResult returns the result of a bill not processed for the reason, e.g. a
+// suspended bill.
+func NewSkippedBillResult(billId string, reason string) *BillResult {
+	return &BillResult{
+		BillId:      billId,
+		Status:      Skipped,
+		Reason:      reason,
+		ProcessedAt: time.Now(),
+	}
 }
 
 type BillProcessingStatus uint8
@@ -112,13 +181,23 @@ type BillProcessingRepositoryInterface interface {
 	CreateBillProcessing(
 		ctx context.Context,
 		billProcessingEntity *BillProcessing) *internal_error.InternalError
+	// UpdateBillProcessing updates the status and the budget alerts, keeping the bill results.
 	UpdateBillProcessing(
 		ctx context.Context,
 		billProcessingEntity *BillProcessing) *internal_error.InternalError
+	AddBillResult(
+		ctx context.Context,
+		billProcessingId string,
+		billResult *BillResult) *internal_error.InternalError
 	FindBillProcessingById(
 		ctx context.Context, billProcessingId string) (*BillProcessing, *internal_error.InternalError)
 	GetProcessingsInProgressCount(
 		ctx context.Context) (int64, *internal_error.InternalError)
+	// LockProcessings writes the processing lock of the household within the transaction of ctx, so
+	// concurrent transactions that also take it (starting a processing, deleting a bill) conflict
+	// and one of them runs again after the other commits.
+	LockProcessings(
+		ctx context.Context) *internal_error.InternalError
 	FindBillProcessings(
 		ctx context.Context,
 		status BillProcessingStatus) ([]*BillProcessing, *internal_error.InternalError)
@@ -0,0 +1,75 @@
+package category_entity
+
+import (
+	"fmt"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// Budget is the spending of a category in a competence period against its monthly budget. The
+// spending of a category includes the one of its subcategories.
+type Budget struct {
+	Category *Category
+	Period   string
+	Spent    money.Money
+}
+
+func (b *Budget) Exceeded() bool {
+	return b.Spent > b.Category.MonthlyBudget
+}
+
+// Remaining returns the amount still available in the period, negative when exceeded.
+func (b *Budget) Remaining() money.Money {
+	return b.Category.MonthlyBudget - b.Spent
+}
+
+// Alert returns the message reported when the budget is exceeded, or empty when it is not.
+func (b *Budget) Alert() string {
+	if !b.Exceeded() {
+		return ""
+	}
+
+	return fmt.Sprintf("Budget of category %s exceeded in period %s: spent %s of %s",
+		b.Category.Name, b.Period, b.Spent, b.Category.MonthlyBudget)
+}
+
+// CalculateBudgets returns the budgets of the period of the categories that have one, given the
+// bills and the invoices of the period.
+func CalculateBudgets(
+	categories []*Category,
+	period string,
+	bills []*bill_entity.Bill,
+	invoices []*invoice_entity.Invoice) []*Budget {
+
+	categoryByBill := make(map[string]string)
+	for _, bill := range bills {
+		categoryByBill[bill.Id] = bill.CategoryId
+	}
+
+	spentByCategory := make(map[string]money.Money)
+	for _, invoice := range invoices {
+		if invoice.Period != period {
+			continue
+		}
+		if categoryId := categoryByBill[invoice.BillId]; categoryId != "" {
+			spentByCategory[categoryId] += invoice.Amount
+		}
+	}
+
+	budgets := make([]*Budget, 0)
+	for _, category := range categories {
+		if category.MonthlyBudget == 0 {
+			continue
+		}
+
+		budget := &Budget{Category: category, Period: period}
+		for _, categoryId := range Subtree(categories, category.Id) {
+			budget.Spent += spentByCategory[categoryId]
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets
+}
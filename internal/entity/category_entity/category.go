@@ -0,0 +1,115 @@
+package category_entity
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// Category groups bills (utilities, housing, subscriptions...). Categories form a tree through
+// ParentId, and the budget of a category covers the bills of its subcategories as well.
+type Category struct {
+	Id            string
+	Name          string
+	ParentId      string      // empty for top level categories
+	MonthlyBudget money.Money // zero when the category has no budget
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func CreateCategory(
+	name string,
+	parentId string,
+	monthlyBudget money.Money) (*Category, *internal_error.InternalError) {
+
+	category :=
+		&Category{
+			Id:            uuid.New().String(),
+			Name:          strings.TrimSpace(name),
+			ParentId:      parentId,
+			MonthlyBudget: monthlyBudget,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Update changes the informed fields. A nil parentId keeps the parent and an empty one moves
+// the category to the top level.
+func (category *Category) Update(
+	name string,
+	parentId *string,
+	monthlyBudget *money.Money) *internal_error.InternalError {
+
+	if name != "" {
+		category.Name = strings.TrimSpace(name)
+	}
+
+	if parentId != nil {
+		category.ParentId = *parentId
+	}
+
+	if monthlyBudget != nil {
+		category.MonthlyBudget = *monthlyBudget
+	}
+
+	category.UpdatedAt = time.Now()
+
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (category *Category) Validate() *internal_error.InternalError {
+	causes := make([]internal_error.Cause, 0)
+
+	if len(category.Name) < 2 {
+		causes = append(causes, internal_error.Cause{Field: "name", Message: "name must be at least 2 characters in length"})
+	}
+	if category.ParentId == category.Id {
+		causes = append(causes, internal_error.Cause{Field: "parentId", Message: "a category cannot be its own parent"})
+	}
+	if category.MonthlyBudget < 0 {
+		causes = append(causes, internal_error.Cause{Field: "monthlyBudget", Message: "monthlyBudget must not be negative"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid category object", causes...)
+	}
+
+	return nil
+}
+
+// Subtree returns the id of the category and of all categories below it.
+func Subtree(categories []*Category, categoryId string) []string {
+	childrenByParent := make(map[string][]string)
+	for _, category := range categories {
+		childrenByParent[category.ParentId] = append(childrenByParent[category.ParentId], category.Id)
+	}
+
+	ids := []string{categoryId}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, childrenByParent[ids[i]]...)
+	}
+
+	return ids
+}
+
+type CategoryRepositoryInterface interface {
+	CreateCategory(ctx context.Context, categoryEntity *Category) *internal_error.InternalError
+	UpdateCategory(ctx context.Context, categoryEntity *Category) *internal_error.InternalError
+	FindCategoryById(ctx context.Context, categoryId string) (*Category, *internal_error.InternalError)
+	FindCategories(ctx context.Context) ([]*Category, *internal_error.InternalError)
+	DeleteCategory(ctx context.Context, categoryId string) *internal_error.InternalError
+}
@@ -10,8 +10,11 @@ import (
 
 type EmailValueSource struct {
 	Id            string
+	MailAccountId string
 	Address       string
 	Subject       string
+	Labels        []string
+	Exclude       []string // search terms to negate, "in:trash" and "in:spam" when empty
 	DataExtractor EmailValueSourceDataExtractor
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
@@ -45,8 +48,11 @@ func GetEmailValueSourceDataExtractorByName(name string) (EmailValueSourceDataEx
 }
 
 func CreateEmailValueSource(
+	mailAccountId string,
 	address string,
 	subject string,
+	labels []string,
+	exclude []string,
 	dataExtractor string) (*EmailValueSource, *internal_error.InternalError) {
 
 	valueSourceDataExtractor, err := GetEmailValueSourceDataExtractorByName(dataExtractor)
@@ -57,8 +63,11 @@ func CreateEmailValueSource(
 	emailValueSource :=
 		&EmailValueSource{
 			Id:            uuid.New().String(),
+			MailAccountId: mailAccountId,
 			Address:       address,
 			Subject:       subject,
+			Labels:        labels,
+			Exclude:       exclude,
 			DataExtractor: valueSourceDataExtractor,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -72,10 +81,17 @@ func CreateEmailValueSource(
 }
 
 func (emailValueSource *EmailValueSource) Update(
+	mailAccountId string,
 	address string,
 	subject string,
+	labels []string,
+	exclude []string,
 	dataExtractor string) *internal_error.InternalError {
 
+	if mailAccountId != "" {
+		emailValueSource.MailAccountId = mailAccountId
+	}
+
 	if address != "" {
 		emailValueSource.Address = address
 	}
@@ -84,6 +100,14 @@ func (emailValueSource *EmailValueSource) Update(
 		emailValueSource.Subject = subject
 	}
 
+	if labels != nil {
+		emailValueSource.Labels = labels
+	}
+
+	if exclude != nil {
+		emailValueSource.Exclude = exclude
+	}
+
 	if dataExtractor != "" {
 		dataExtractor, err := GetEmailValueSourceDataExtractorByName(dataExtractor)
 		if err != nil {
@@ -103,7 +127,8 @@ func (emailValueSource *EmailValueSource) Update(
 
 func (emailValueSource *EmailValueSource) Validate() *internal_error.InternalError {
 	if len(emailValueSource.Address) < 5 {
-		return internal_error.NewBadRequestError("invalid emailValueSource object. invalid address")
+		return internal_error.NewBadRequestError("invalid emailValueSource object. invalid address",
+			internal_error.Cause{Field: "address", Message: "address must be at least 5 characters in length"})
 	}
 
 	return nil
@@ -117,4 +142,5 @@ type EmailValueSourceRepositoryInterface interface {
 		address string,
 		subject string) ([]*EmailValueSource, *internal_error.InternalError)
 	UpdateEmailValueSource(ctx context.Context, emailValueSourceEntity *EmailValueSource) *internal_error.InternalError
+	DeleteEmailValueSource(ctx context.Context, emailValueSourceId string) *internal_error.InternalError
 }
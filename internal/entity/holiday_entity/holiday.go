@@ -0,0 +1,99 @@
+package holiday_entity
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Holiday is a holiday not in the national calendar, e.g. a state holiday (20/09 in RS) or a
+// city one (02/02 in Porto Alegre). Yearly holidays repeat on the month and day of Date.
+type Holiday struct {
+	Id        string
+	Name      string
+	Date      string // YYYY-MM-DD
+	Yearly    bool
+	State     string // UF, empty for holidays of the whole country
+	City      string // empty for holidays of the whole state
+	CreatedAt time.Time
+}
+
+func CreateHoliday(
+	name string,
+	date string,
+	yearly bool,
+	state string,
+	city string) (*Holiday, *internal_error.InternalError) {
+
+	holiday :=
+		&Holiday{
+			Id:        uuid.New().String(),
+			Name:      strings.TrimSpace(name),
+			Date:      date,
+			Yearly:    yearly,
+			State:     strings.ToUpper(strings.TrimSpace(state)),
+			City:      strings.TrimSpace(city),
+			CreatedAt: time.Now(),
+		}
+
+	if err := holiday.Validate(); err != nil {
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// OccursOn tells whether the holiday falls on the day of date.
+func (holiday *Holiday) OccursOn(date time.Time) bool {
+	if holiday.Yearly {
+		return len(holiday.Date) == 10 && holiday.Date[5:] == date.Format("01-02")
+	}
+	return holiday.Date == date.Format("2006-01-02")
+}
+
+// AppliesTo tells whether the holiday is observed in the city of the state. Holidays of the
+// whole country apply everywhere, and state holidays to all of its cities.
+func (holiday *Holiday) AppliesTo(state string, city string) bool {
+	if holiday.State == "" {
+		return true
+	}
+	if !strings.EqualFold(holiday.State, state) {
+		return false
+	}
+	return holiday.City == "" || strings.EqualFold(holiday.City, strings.TrimSpace(city))
+}
+
+func (holiday *Holiday) Validate() *internal_error.InternalError {
+	causes := make([]internal_error.Cause, 0)
+
+	if len(holiday.Name) < 3 {
+		causes = append(causes, internal_error.Cause{Field: "name", Message: "name must be at least 3 characters in length"})
+	}
+	if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+		causes = append(causes, internal_error.Cause{Field: "date", Message: "date must be in the format YYYY-MM-DD"})
+	}
+	if holiday.State != "" && len(holiday.State) != 2 {
+		causes = append(causes, internal_error.Cause{Field: "state", Message: "state must be an UF, e.g. RS"})
+	}
+	if holiday.City != "" && holiday.State == "" {
+		causes = append(causes, internal_error.Cause{Field: "state", Message: "state is required for city holidays"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid holiday object", causes...)
+	}
+
+	return nil
+}
+
+type HolidayRepositoryInterface interface {
+	CreateHoliday(ctx context.Context, holidayEntity *Holiday) *internal_error.InternalError
+	FindHolidayById(ctx context.Context, holidayId string) (*Holiday, *internal_error.InternalError)
+	// FindHolidays returns the holidays of the state (all when empty), including the ones of the
+	// whole country.
+	FindHolidays(ctx context.Context, state string) ([]*Holiday, *internal_error.InternalError)
+	DeleteHoliday(ctx context.Context, holidayId string) *internal_error.InternalError
+}
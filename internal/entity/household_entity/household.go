@@ -0,0 +1,271 @@
+package household_entity
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Household owns bills, value sources and invoices, shared by its members. Every query made on
+// behalf of a member is scoped to the household (see WithScope).
+type Household struct {
+	Id        string
+	Name      string
+	Members   []Member
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Member is a user of the household. Members are invited by email and only have access after
+// accepting the invitation, when UserId is filled.
+type Member struct {
+	Id        string
+	UserId    string
+	Email     string
+	Role      Role
+	Status    MemberStatus
+	InvitedAt time.Time
+	JoinedAt  time.Time
+}
+
+type Role uint8
+
+const (
+	Owner  Role = iota + 1 // manages the members
+	Editor                 // changes bills, value sources and invoices
+	Viewer                 // only reads them
+)
+
+func (r Role) Name() string {
+	return roleNames[r]
+}
+
+var roleNames = []string{
+	"",
+	"owner",
+	"editor",
+	"viewer",
+}
+
+func GetRoleByName(name string) (Role, *internal_error.InternalError) {
+	for k, v := range roleNames {
+		if v == name {
+			return Role(k), nil
+		}
+	}
+
+	return Role(0), internal_error.NewBadRequestError("invalid household role name",
+		internal_error.Cause{Field: "role", Message: "role must be one of owner, editor or viewer"})
+}
+
+// CanEdit reports whether the role allows changing the records of the household.
+func (r Role) CanEdit() bool {
+	return r == Owner || r == Editor
+}
+
+type MemberStatus uint8
+
+const (
+	Invited MemberStatus = iota + 1
+	Joined
+)
+
+func (s MemberStatus) Name() string {
+	return memberStatusNames[s]
+}
+
+var memberStatusNames = []string{
+	"",
+	"invited",
+	"joined",
+}
+
+// CreateHousehold creates the household with the user as its owner.
+func CreateHousehold(name string, ownerId string, ownerEmail string) (*Household, *internal_error.InternalError) {
+	household := &Household{
+		Id:   uuid.New().String(),
+		Name: strings.TrimSpace(name),
+		Members: []Member{{
+			Id:        uuid.New().String(),
+			UserId:    ownerId,
+			Email:     normalizeEmail(ownerEmail),
+			Role:      Owner,
+			Status:    Joined,
+			InvitedAt: time.Now(),
+			JoinedAt:  time.Now(),
+		}},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := household.Validate(); err != nil {
+		return nil, err
+	}
+
+	return household, nil
+}
+
+func (household *Household) Validate() *internal_error.InternalError {
+	if len(household.Name) < 2 {
+		return internal_error.NewBadRequestError("invalid household object. invalid name",
+			internal_error.Cause{Field: "name", Message: "name must be at least 2 characters in length"})
+	}
+
+	return nil
+}
+
+// Invite adds a member with the role, who joins when accepting the invitation.
+func (household *Household) Invite(email string, role string) (*Member, *internal_error.InternalError) {
+	email = normalizeEmail(email)
+	if !strings.Contains(email, "@") {
+		return nil, internal_error.NewBadRequestError("invalid household invitation",
+			internal_error.Cause{Field: "email", Message: "email is invalid"})
+	}
+
+	memberRole, err := GetRoleByName(role)
+	if err != nil || memberRole == 0 {
+		return nil, internal_error.NewBadRequestError("invalid household invitation",
+			internal_error.Cause{Field: "role", Message: "role must be one of owner, editor or viewer"})
+	}
+
+	if slices.ContainsFunc(household.Members, func(m Member) bool { return m.Email == email }) {
+		return nil, internal_error.NewBadRequestError("invalid household invitation",
+			internal_error.Cause{Field: "email", Message: "email already invited to the household"})
+	}
+
+	member := Member{
+		Id:        uuid.New().String(),
+		Email:     email,
+		Role:      memberRole,
+		Status:    Invited,
+		InvitedAt: time.Now(),
+	}
+
+	household.Members = append(household.Members, member)
+	household.UpdatedAt = time.Now()
+
+	return &member, nil
+}
+
+// Accept makes the user invited with the email a member of the household.
+func (household *Household) Accept(userId string, email string) *internal_error.InternalError {
+	email = normalizeEmail(email)
+
+	for i := range household.Members {
+		member := &household.Members[i]
+		if member.Email != email || member.Status != Invited {
+			continue
+		}
+
+		member.UserId = userId
+		member.Status = Joined
+		member.JoinedAt = time.Now()
+		household.UpdatedAt = time.Now()
+		return nil
+	}
+
+	return internal_error.NewNotFoundError("Invitation not found for this email = " + email)
+}
+
+// ChangeRole changes the role of a member. The household always keeps an owner.
+func (household *Household) ChangeRole(memberId string, role string) *internal_error.InternalError {
+	memberRole, err := GetRoleByName(role)
+	if err != nil || memberRole == 0 {
+		return internal_error.NewBadRequestError("invalid household role",
+			internal_error.Cause{Field: "role", Message: "role must be one of owner, editor or viewer"})
+	}
+
+	member := household.findMember(memberId)
+	if member == nil {
+		return internal_error.NewNotFoundError("Member not found with this id = " + memberId)
+	}
+
+	if member.Role == Owner && memberRole != Owner && household.owners() == 1 {
+		return internal_error.NewBadRequestError("the household must keep at least one owner",
+			internal_error.Cause{Field: "role", Message: "the last owner cannot change role"})
+	}
+
+	member.Role = memberRole
+	household.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// RemoveMember removes a member or cancels an invitation. The household always keeps an owner.
+func (household *Household) RemoveMember(memberId string) *internal_error.InternalError {
+	for i, member := range household.Members {
+		if member.Id != memberId {
+			continue
+		}
+
+		if member.Role == Owner && member.Status == Joined && household.owners() == 1 {
+			return internal_error.NewBadRequestError("the household must keep at least one owner")
+		}
+
+		household.Members = append(household.Members[:i:i], household.Members[i+1:]...)
+		household.UpdatedAt = time.Now()
+		return nil
+	}
+
+	return internal_error.NewNotFoundError("Member not found with this id = " + memberId)
+}
+
+// RoleOf returns the role of the user in the household, or 0 when the user did not join it.
+func (household *Household) RoleOf(userId string) Role {
+	for _, member := range household.Members {
+		if member.UserId == userId && member.Status == Joined {
+			return member.Role
+		}
+	}
+	return 0
+}
+
+// MemberIds returns the users who joined the household.
+func (household *Household) MemberIds() []string {
+	memberIds := make([]string, 0, len(household.Members))
+	for _, member := range household.Members {
+		if member.Status == Joined {
+			memberIds = append(memberIds, member.UserId)
+		}
+	}
+	return memberIds
+}
+
+func (household *Household) findMember(memberId string) *Member {
+	for i := range household.Members {
+		if household.Members[i].Id == memberId {
+			return &household.Members[i]
+		}
+	}
+	return nil
+}
+
+func (household *Household) owners() int {
+	owners := 0
+	for _, member := range household.Members {
+		if member.Role == Owner && member.Status == Joined {
+			owners++
+		}
+	}
+	return owners
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type HouseholdRepositoryInterface interface {
+	CreateHousehold(ctx context.Context, householdEntity *Household) *internal_error.InternalError
+	UpdateHousehold(ctx context.Context, householdEntity *Household) *internal_error.InternalError
+	FindHouseholdById(ctx context.Context, householdId string) (*Household, *internal_error.InternalError)
+	FindHouseholds(ctx context.Context) ([]*Household, *internal_error.InternalError)
+	// FindHouseholdsByMember returns the households the user joined or was invited to by email.
+	FindHouseholdsByMember(
+		ctx context.Context,
+		userId string,
+		email string) ([]*Household, *internal_error.InternalError)
+}
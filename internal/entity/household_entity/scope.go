@@ -0,0 +1,49 @@
+package household_entity
+
+import (
+	"context"
+	"slices"
+)
+
+// Scope is the household a request acts on and the role of the calling user in it.
+type Scope struct {
+	HouseholdId string
+	UserId      string
+	Role        Role
+	// MemberIds are the users who joined the household
+	MemberIds []string
+}
+
+// HasMember reports whether the user joined the household of the scope.
+func (s Scope) HasMember(userId string) bool {
+	return slices.Contains(s.MemberIds, userId)
+}
+
+type scopeKey struct{}
+
+// WithScope returns a context whose repository queries are restricted to the household.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext returns the scope of the request, or false for contexts not bound to a
+// household.
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	scope, found := ctx.Value(scopeKey{}).(Scope)
+	return scope, found
+}
+
+type unscopedKey struct{}
+
+// WithoutScope marks a context of the system itself, such as the jobs run at startup, whose
+// repository queries see the records of every household. Contexts neither bound to a household
+// nor marked see no records.
+func WithoutScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether the context was marked with WithoutScope.
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
@@ -0,0 +1,20 @@
+package invoice_entity
+
+import "strings"
+
+// NormalizeBarcode returns the digits of a linha digitável (47 digits for boletos, 48 for
+// concessionárias) or código de barras (44 digits), or an empty string for any other value.
+func NormalizeBarcode(value string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if len(digits) == 44 || len(digits) == 47 || len(digits) == 48 {
+		return digits
+	}
+
+	return ""
+}
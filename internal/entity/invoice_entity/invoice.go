@@ -6,16 +6,96 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type Invoice struct {
-	Id        string
-	BillId    string
-	DueDate   string
-	Amount    float64
-	Status    InvoiceStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id            string
+	BillId        string
+	Period        string // competence period (YYYY-MM) the invoice refers to
+	DueDate       string
+	Amount        money.Money
+	Status        InvoiceStatus
+	EmailSource   *EmailSource
+	Barcode       string // linha digitável of the boleto, digits only
+	Shares        []Share
+	Payments      []*Payment
+	AmountChanges []*AmountChange
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Share is the part of the invoice of a shared bill owed by one of its users.
+type Share struct {
+	UserId string
+	Amount money.Money
+}
+
+// AmountChange records a different amount found for the invoice when its bill was processed again.
+type AmountChange struct {
+	PreviousAmount money.Money
+	Amount         money.Money
+	ChangedAt      time.Time
+}
+
+// EmailSource identifies the email an invoice was extracted from.
+type EmailSource struct {
+	MessageId   string
+	ThreadId    string
+	Sender      string
+	ReceivedAt  time.Time
+	ContentHash string
+	DuplicateOf string // invoice already extracted from an email with the same content (e.g. a forwarded copy)
+}
+
+type Payment struct {
+	Id          string
+	PaymentDate string
+	Amount      money.Money
+	Method      PaymentMethod
+	Notes       string
+	Reference   string       // e.g. the bank statement transaction id the payment was reconciled from
+	EmailSource *EmailSource // payment confirmation email the payment was registered from
+	Automatic   bool         // registered on the due date of a bill charged automatically
+	PaidBy      string       // user who paid, the owner of the bill when empty
+	CreatedAt   time.Time
+}
+
+// Confirmed reports whether the payment is known to have happened: automatic payments are only
+// assumed until a statement transaction confirms them.
+func (payment *Payment) Confirmed() bool {
+	return !payment.Automatic || payment.Reference != ""
+}
+
+type PaymentMethod uint8
+
+const (
+	Pix PaymentMethod = iota + 1
+	Boleto
+	DebitoAutomatico
+	Cartao
+)
+
+func (m PaymentMethod) Name() string {
+	return paymentMethodNames[m]
+}
+
+var paymentMethodNames = []string{
+	"",
+	"pix",
+	"boleto",
+	"debito_automatico",
+	"cartao",
+}
+
+func GetPaymentMethodByName(name string) (PaymentMethod, *internal_error.InternalError) {
+	for k, v := range paymentMethodNames {
+		if v == name {
+			return PaymentMethod(k), nil
+		}
+	}
+
+	return PaymentMethod(0), internal_error.NewBadRequestError("invalid payment method name")
 }
 
 type InvoiceStatus uint8
@@ -23,6 +103,7 @@ type InvoiceStatus uint8
 const (
 	Unpaid InvoiceStatus = iota + 1
 	Paid
+	PartiallyPaid
 )
 
 func (s InvoiceStatus) Name() string {
@@ -33,6 +114,7 @@ var invoiceStatusNames = []string{
 	"",
 	"unpaid",
 	"paid",
+	"partially_paid",
 }
 
 func GetInvoiceStatusByName(name string) (InvoiceStatus, *internal_error.InternalError) {
@@ -45,10 +127,13 @@ func GetInvoiceStatusByName(name string) (InvoiceStatus, *internal_error.Interna
 	return InvoiceStatus(0), internal_error.NewBadRequestError("invalid invoice status name")
 }
 
+// CreateInvoice creates an unpaid invoice, unless another status is informed. Without a period,
+// the invoice refers to the month before the due date.
 func CreateInvoice(
 	billId string,
+	period string,
 	dueDate string,
-	amount float64,
+	amount money.Money,
 	status string) (*Invoice, *internal_error.InternalError) {
 
 	var invoiceStatus InvoiceStatus
@@ -63,10 +148,15 @@ func CreateInvoice(
 		invoiceStatus = status
 	}
 
+	if period == "" {
+		period = PeriodFromDueDate(dueDate)
+	}
+
 	invoice :=
 		&Invoice{
 			Id:        uuid.New().String(),
 			BillId:    billId,
+			Period:    period,
 			DueDate:   dueDate,
 			Amount:    amount,
 			Status:    invoiceStatus,
@@ -81,24 +171,326 @@ func CreateInvoice(
 	return invoice, nil
 }
 
+// PeriodFromDueDate returns the period (YYYY-MM) billed by an invoice due on dueDate: the
+// month before it. It returns "" when dueDate is not a YYYY-MM-DD date.
+func PeriodFromDueDate(dueDate string) string {
+	date, err := time.Parse("2006-01-02", dueDate)
+	if err != nil {
+		return ""
+	}
+	return time.Date(date.Year(), date.Month()-1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+}
+
+// AddPayment registers a payment and updates the status from the paid amount. When not
+// informed, the payment date is today and the paid amount is the outstanding balance.
+func (invoice *Invoice) AddPayment(
+	paymentDate string,
+	amount money.Money,
+	method string,
+	notes string,
+	reference string) (*Payment, *internal_error.InternalError) {
+
+	if invoice.Status == Paid {
+		return nil, internal_error.NewBadRequestError("invoice already paid")
+	}
+
+	paymentMethod, err := GetPaymentMethodByName(method)
+	if err != nil {
+		return nil, err
+	}
+
+	if paymentDate == "" {
+		paymentDate = time.Now().Format("2006-01-02")
+	}
+
+	if amount == 0 {
+		amount = invoice.OutstandingBalance()
+	}
+
+	payment := &Payment{
+		Id:          uuid.New().String(),
+		PaymentDate: paymentDate,
+		Amount:      amount,
+		Method:      paymentMethod,
+		Notes:       notes,
+		Reference:   reference,
+		CreatedAt:   time.Now(),
+	}
+
+	invoice.Payments = append(invoice.Payments, payment)
+	invoice.updateStatus()
+
+	if err := invoice.Validate(); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// AddAutomaticPayment pays the outstanding balance on the due date, as charged by an automatic
+// debit or a credit card. The payment is unconfirmed until ConfirmAutomaticPayment.
+func (invoice *Invoice) AddAutomaticPayment(method PaymentMethod) (*Payment, *internal_error.InternalError) {
+	payment, err := invoice.AddPayment(invoice.DueDate, 0, method.Name(), "Pagamento automático no vencimento", "")
+	if err != nil {
+		return nil, err
+	}
+
+	payment.Automatic = true
+
+	return payment, nil
+}
+
+// UnconfirmedPayment returns the automatic payment not confirmed by a statement yet, or nil.
+func (invoice *Invoice) UnconfirmedPayment() *Payment {
+	for _, payment := range invoice.Payments {
+		if !payment.Confirmed() {
+			return payment
+		}
+	}
+	return nil
+}
+
+// ConfirmAutomaticPayment confirms the automatic payment with the statement transaction that
+// charged it, on the date it was actually charged (the due date when not informed). A charge of
+// another amount does not confirm the payment.
+func (invoice *Invoice) ConfirmAutomaticPayment(
+	paymentDate string,
+	amount money.Money,
+	reference string) (*Payment, *internal_error.InternalError) {
+
+	payment := invoice.UnconfirmedPayment()
+	if payment == nil {
+		return nil, internal_error.NewBadRequestError("invoice has no automatic payment to confirm")
+	}
+	if reference == "" {
+		return nil, internal_error.NewBadRequestError("the reference of the charge is required to confirm an automatic payment",
+			internal_error.Cause{Field: "reference", Message: "reference is required"})
+	}
+	if amount != 0 && amount != payment.Amount {
+		return nil, internal_error.NewBadRequestError("the amount charged differs from the automatic payment",
+			internal_error.Cause{Field: "amount", Message: "amount must be the amount of the automatic payment"})
+	}
+
+	if paymentDate != "" {
+		payment.PaymentDate = paymentDate
+	}
+	payment.Reference = reference
+	invoice.UpdatedAt = time.Now()
+
+	if err := invoice.Validate(); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// UpdateAmount replaces the amount, recording the previous one. It reports whether the amount
+// changed.
+func (invoice *Invoice) UpdateAmount(amount money.Money) bool {
+	if amount == invoice.Amount {
+		return false
+	}
+
+	invoice.AmountChanges = append(invoice.AmountChanges, &AmountChange{
+		PreviousAmount: invoice.Amount,
+		Amount:         amount,
+		ChangedAt:      time.Now(),
+	})
+	invoice.Amount = amount
+	invoice.UpdatedAt = time.Now()
+
+	return true
+}
+
+// RemovePayment undoes a payment registered by mistake.
+func (invoice *Invoice) RemovePayment(paymentId string) *internal_error.InternalError {
+	for i, payment := range invoice.Payments {
+		if payment.Id == paymentId {
+			invoice.Payments = append(invoice.Payments[:i], invoice.Payments[i+1:]...)
+			invoice.updateStatus()
+			return nil
+		}
+	}
+
+	return internal_error.NewNotFoundError("payment not found")
+}
+
+func (invoice *Invoice) HasPaymentReference(reference string) bool {
+	for _, payment := range invoice.Payments {
+		if payment.Reference != "" && payment.Reference == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPaymentFromEmail reports whether a payment was registered from the message, or from a
+// forwarded copy of it with the same content hash.
+func (invoice *Invoice) HasPaymentFromEmail(messageId string, contentHash string) bool {
+	for _, payment := range invoice.Payments {
+		if source := payment.EmailSource; source != nil &&
+			(source.MessageId == messageId || (contentHash != "" && source.ContentHash == contentHash)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (invoice *Invoice) PaidAmount() money.Money {
+	var paid money.Money
+	for _, payment := range invoice.Payments {
+		paid += payment.Amount
+	}
+	return paid
+}
+
+// OutstandingBalance is the amount still to be paid. It is negative when the invoice was overpaid.
+func (invoice *Invoice) OutstandingBalance() money.Money {
+	if invoice.Status == Paid && len(invoice.Payments) == 0 {
+		return 0 // created as paid, without payment details
+	}
+	return invoice.Amount - invoice.PaidAmount()
+}
+
+func (invoice *Invoice) updateStatus() {
+	switch {
+	case len(invoice.Payments) == 0:
+		invoice.Status = Unpaid
+	case invoice.OutstandingBalance() > 0:
+		invoice.Status = PartiallyPaid
+	default:
+		invoice.Status = Paid
+	}
+	invoice.UpdatedAt = time.Now()
+}
+
+// AmountDue is the amount needed to settle the invoice on a given date, with the late
+// payment charges broken down.
+type AmountDue struct {
+	AsOf        string
+	DaysOverdue int
+	Principal   money.Money
+	Fine        money.Money
+	Interest    money.Money
+	Total       money.Money
+}
+
+// IsOverdue reports whether the invoice is still open after its due date.
+func (invoice *Invoice) IsOverdue(asOf time.Time) bool {
+	return invoice.DaysOverdue(asOf) > 0
+}
+
+func (invoice *Invoice) DaysOverdue(asOf time.Time) int {
+	if invoice.Status == Paid {
+		return 0
+	}
+
+	dueDate, err := time.Parse("2006-01-02", invoice.DueDate)
+	if err != nil {
+		return 0
+	}
+
+	asOfDate := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(asOfDate.Sub(dueDate).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// CalculateAmountDue applies to the outstanding balance a one-off fine (multa) and a monthly
+// interest (juros de mora) charged pro rata die, i.e. monthly percentage / 30 per day overdue.
+func (invoice *Invoice) CalculateAmountDue(
+	asOf time.Time,
+	finePercentage float64,
+	monthlyInterestPercentage float64) AmountDue {
+
+	principal := invoice.OutstandingBalance()
+	if principal < 0 {
+		principal = 0
+	}
+
+	daysOverdue := invoice.DaysOverdue(asOf)
+
+	var fine, interest money.Money
+	if daysOverdue > 0 {
+		fine = principal.Percentage(finePercentage)
+		interest = principal.Percentage(monthlyInterestPercentage / 30 * float64(daysOverdue))
+	}
+
+	return AmountDue{
+		AsOf:        asOf.Format("2006-01-02"),
+		DaysOverdue: daysOverdue,
+		Principal:   principal,
+		Fine:        fine,
+		Interest:    interest,
+		Total:       principal + fine + interest,
+	}
+}
+
 func (invoice *Invoice) Validate() *internal_error.InternalError {
+	if invoice.BillId == "" {
+		return internal_error.NewBadRequestError("invalid invoice object. invalid bill id",
+			internal_error.Cause{Field: "billId", Message: "billId is required"})
+	}
+
 	if _, err := time.Parse("2006-01-02", invoice.DueDate); err != nil {
-		return internal_error.NewBadRequestError("invalid invoice object. invalid due date")
+		return internal_error.NewBadRequestError("invalid invoice object. invalid due date",
+			internal_error.Cause{Field: "dueDate", Message: "dueDate must be in the format YYYY-MM-DD"})
+	}
+
+	if _, err := time.Parse("2006-01", invoice.Period); err != nil {
+		return internal_error.NewBadRequestError("invalid invoice object. invalid period",
+			internal_error.Cause{Field: "period", Message: "period must be in the format YYYY-MM"})
+	}
+
+	for _, payment := range invoice.Payments {
+		if _, err := time.Parse("2006-01-02", payment.PaymentDate); err != nil {
+			return internal_error.NewBadRequestError("invalid invoice object. invalid payment date")
+		}
+		if payment.Amount <= 0 {
+			return internal_error.NewBadRequestError("invalid invoice object. invalid payment amount")
+		}
+		if payment.Method == 0 {
+			return internal_error.NewBadRequestError("invalid invoice object. invalid payment method")
+		}
 	}
 
 	return nil
 }
 
+// InvoiceFilter selects the invoices returned by FindInvoices. Empty fields match any invoice;
+// periods are YYYY-MM and the range is inclusive.
+type InvoiceFilter struct {
+	BillIds    []string
+	Status     InvoiceStatus
+	FromPeriod string
+	ToPeriod   string
+}
+
 type InvoiceRepositoryInterface interface {
 	CreateInvoice(ctx context.Context, invoiceEntity *Invoice) *internal_error.InternalError
+	UpdateInvoice(ctx context.Context, invoiceEntity *Invoice) *internal_error.InternalError
+	// UpsertInvoice inserts the invoice or replaces the one of the same bill and period, so
+	// processing a period again never duplicates its invoice.
+	UpsertInvoice(ctx context.Context, invoiceEntity *Invoice) *internal_error.InternalError
 	FindInvoiceById(ctx context.Context, invoiceId string) (*Invoice, *internal_error.InternalError)
-	FindInvoices(
+	FindInvoiceByBillAndPeriod(
 		ctx context.Context,
 		billId string,
-		status InvoiceStatus) ([]*Invoice, *internal_error.InternalError)
+		period string) (*Invoice, *internal_error.InternalError)
+	FindInvoices(
+		ctx context.Context,
+		filter InvoiceFilter) ([]*Invoice, *internal_error.InternalError)
+	// DeleteInvoices never deletes invoices with payments.
 	DeleteInvoices(
 		ctx context.Context,
 		billId string,
 		status InvoiceStatus,
 		dueDate string) (uint, *internal_error.InternalError)
+	FindInvoicesByEmailSource(
+		ctx context.Context,
+		messageId string,
+		contentHash string) ([]*Invoice, *internal_error.InternalError)
+	DeleteInvoice(ctx context.Context, invoiceId string) *internal_error.InternalError
 }
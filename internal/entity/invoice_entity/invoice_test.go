@@ -0,0 +1,87 @@
+package invoice_entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateAmountDue(t *testing.T) {
+	tests := []struct {
+		name    string
+		invoice Invoice
+		asOf    time.Time
+		want    AmountDue
+	}{
+		{
+			name:    "not due yet",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2024, time.May, 9, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-05-09", Principal: 10000, Total: 10000},
+		},
+		{
+			name:    "on the due date",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2024, time.May, 10, 23, 59, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-05-10", Principal: 10000, Total: 10000},
+		},
+		{
+			name:    "one day overdue, interest rounded down",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2024, time.May, 11, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-05-11", DaysOverdue: 1, Principal: 10000, Fine: 200, Interest: 3, Total: 10203},
+		},
+		{
+			name:    "two days overdue, interest rounded up",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2024, time.May, 12, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-05-12", DaysOverdue: 2, Principal: 10000, Fine: 200, Interest: 7, Total: 10207},
+		},
+		{
+			name:    "a month overdue",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 12345, Status: Unpaid},
+			asOf:    time.Date(2024, time.June, 9, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-06-09", DaysOverdue: 30, Principal: 12345, Fine: 247, Interest: 123, Total: 12715},
+		},
+		{
+			name:    "over February 29 of a leap year",
+			invoice: Invoice{DueDate: "2024-02-28", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-03-01", DaysOverdue: 2, Principal: 10000, Fine: 200, Interest: 7, Total: 10207},
+		},
+		{
+			name:    "end of February of a common year",
+			invoice: Invoice{DueDate: "2023-02-28", Amount: 10000, Status: Unpaid},
+			asOf:    time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2023-03-01", DaysOverdue: 1, Principal: 10000, Fine: 200, Interest: 3, Total: 10203},
+		},
+		{
+			name: "charges on the outstanding balance only",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: PartiallyPaid,
+				Payments: []*Payment{{Amount: 4000}}},
+			asOf: time.Date(2024, time.June, 9, 0, 0, 0, 0, time.UTC),
+			want: AmountDue{AsOf: "2024-06-09", DaysOverdue: 30, Principal: 6000, Fine: 120, Interest: 60, Total: 6180},
+		},
+		{
+			name: "overpaid",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Paid,
+				Payments: []*Payment{{Amount: 12000}}},
+			asOf: time.Date(2024, time.June, 9, 0, 0, 0, 0, time.UTC),
+			want: AmountDue{AsOf: "2024-06-09"},
+		},
+		{
+			name:    "created as paid",
+			invoice: Invoice{DueDate: "2024-05-10", Amount: 10000, Status: Paid},
+			asOf:    time.Date(2024, time.June, 9, 0, 0, 0, 0, time.UTC),
+			want:    AmountDue{AsOf: "2024-06-09"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.invoice.CalculateAmountDue(tt.asOf, 2, 1)
+			if got != tt.want {
+				t.Errorf("CalculateAmountDue() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mail_account_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type MailAccount struct {
+	Id        string
+	UserId    string
+	Provider  MailAccountProvider
+	Address   string
+	Status    MailAccountStatus
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type MailAccountProvider uint8
+
+const (
+	Gmail MailAccountProvider = iota + 1
+)
+
+type MailAccountStatus uint8
+
+const (
+	Pending MailAccountStatus = iota + 1
+	Connected
+	Disconnected
+)
+
+func (p MailAccountProvider) Name() string {
+	return mailAccountProviderNames[p]
+}
+
+var mailAccountProviderNames = []string{
+	"",
+	"gmail",
+}
+
+func GetMailAccountProviderByName(name string) (MailAccountProvider, *internal_error.InternalError) {
+	for k, v := range mailAccountProviderNames {
+		if v == name {
+			return MailAccountProvider(k), nil
+		}
+	}
+
+	return MailAccountProvider(0), internal_error.NewBadRequestError("invalid mailAccount provider name")
+}
+
+func (s MailAccountStatus) Name() string {
+	return mailAccountStatusNames[s]
+}
+
+var mailAccountStatusNames = []string{
+	"",
+	"pending",
+	"connected",
+	"disconnected",
+}
+
+func GetMailAccountStatusByName(name string) (MailAccountStatus, *internal_error.InternalError) {
+	for k, v := range mailAccountStatusNames {
+		if v == name {
+			return MailAccountStatus(k), nil
+		}
+	}
+
+	return MailAccountStatus(0), internal_error.NewBadRequestError("invalid mailAccount status name")
+}
+
+func CreateMailAccount(
+	userId string,
+	provider string,
+	address string) (*MailAccount, *internal_error.InternalError) {
+
+	mailAccountProvider, err := GetMailAccountProviderByName(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	mailAccount :=
+		&MailAccount{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			Provider:  mailAccountProvider,
+			Address:   address,
+			Status:    Pending,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+	if err := mailAccount.Validate(); err != nil {
+		return nil, err
+	}
+
+	return mailAccount, nil
+}
+
+// Connect marks the account as connected to the mailbox authorized by the user.
+func (mailAccount *MailAccount) Connect(address string) *internal_error.InternalError {
+	if address != "" {
+		mailAccount.Address = address
+	}
+
+	mailAccount.Status = Connected
+	mailAccount.UpdatedAt = time.Now()
+
+	return mailAccount.Validate()
+}
+
+func (mailAccount *MailAccount) Disconnect() {
+	mailAccount.Status = Disconnected
+	mailAccount.UpdatedAt = time.Now()
+}
+
+func (mailAccount *MailAccount) Validate() *internal_error.InternalError {
+	if mailAccount.UserId == "" {
+		return internal_error.NewBadRequestError("invalid mailAccount object. invalid userId",
+			internal_error.Cause{Field: "userId", Message: "userId is required"})
+	}
+	if len(mailAccount.Address) < 5 {
+		return internal_error.NewBadRequestError("invalid mailAccount object. invalid address",
+			internal_error.Cause{Field: "address", Message: "address must be at least 5 characters in length"})
+	}
+
+	return nil
+}
+
+type MailAccountRepositoryInterface interface {
+	CreateMailAccount(ctx context.Context, mailAccountEntity *MailAccount) *internal_error.InternalError
+	UpdateMailAccount(ctx context.Context, mailAccountEntity *MailAccount) *internal_error.InternalError
+	FindMailAccountById(ctx context.Context, mailAccountId string) (*MailAccount, *internal_error.InternalError)
+	FindMailAccounts(
+		ctx context.Context,
+		status MailAccountStatus,
+		userId string) ([]*MailAccount, *internal_error.InternalError)
+}
@@ -0,0 +1,117 @@
+package oauth_token_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type OAuthToken struct {
+	Id           string
+	Provider     OAuthTokenProvider
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	Expiry       time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type OAuthTokenProvider uint8
+
+const (
+	Gmail OAuthTokenProvider = iota + 1
+)
+
+func (p OAuthTokenProvider) Name() string {
+	return oauthTokenProviderNames[p]
+}
+
+var oauthTokenProviderNames = []string{
+	"",
+	"gmail",
+}
+
+func GetOAuthTokenProviderByName(name string) (OAuthTokenProvider, *internal_error.InternalError) {
+	for k, v := range oauthTokenProviderNames {
+		if v == name {
+			return OAuthTokenProvider(k), nil
+		}
+	}
+
+	return OAuthTokenProvider(0), internal_error.NewBadRequestError("invalid oauthToken provider name")
+}
+
+func CreateOAuthToken(
+	id string,
+	provider OAuthTokenProvider,
+	accessToken string,
+	tokenType string,
+	refreshToken string,
+	expiry time.Time) (*OAuthToken, *internal_error.InternalError) {
+
+	oauthToken :=
+		&OAuthToken{
+			Id:           id,
+			Provider:     provider,
+			AccessToken:  accessToken,
+			TokenType:    tokenType,
+			RefreshToken: refreshToken,
+			Expiry:       expiry,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+	if err := oauthToken.Validate(); err != nil {
+		return nil, err
+	}
+
+	return oauthToken, nil
+}
+
+func (oauthToken *OAuthToken) Update(
+	accessToken string,
+	tokenType string,
+	refreshToken string,
+	expiry time.Time) *internal_error.InternalError {
+
+	if accessToken != "" {
+		oauthToken.AccessToken = accessToken
+	}
+
+	if tokenType != "" {
+		oauthToken.TokenType = tokenType
+	}
+
+	// the provider only sends a refresh token on the first authorization
+	if refreshToken != "" {
+		oauthToken.RefreshToken = refreshToken
+	}
+
+	oauthToken.Expiry = expiry
+	oauthToken.UpdatedAt = time.Now()
+
+	if err := oauthToken.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (oauthToken *OAuthToken) Validate() *internal_error.InternalError {
+	if oauthToken.Id == "" || oauthToken.Provider == 0 {
+		return internal_error.NewBadRequestError("invalid oauthToken object")
+	}
+	if oauthToken.AccessToken == "" && oauthToken.RefreshToken == "" {
+		return internal_error.NewBadRequestError("invalid oauthToken object. missing access and refresh tokens")
+	}
+
+	return nil
+}
+
+type OAuthTokenRepositoryInterface interface {
+	SaveOAuthToken(ctx context.Context, oauthTokenEntity *OAuthToken) *internal_error.InternalError
+	FindOAuthTokenById(ctx context.Context, oauthTokenId string) (*OAuthToken, *internal_error.InternalError)
+	DeleteOAuthToken(ctx context.Context, oauthTokenId string) *internal_error.InternalError
+}
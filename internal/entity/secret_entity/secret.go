@@ -0,0 +1,74 @@
+package secret_entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// Secret holds a credential used by a value source (API keys, IMAP passwords...).
+// Value is only kept decrypted in memory and is never exposed by the API.
+type Secret struct {
+	Id        string
+	OwnerId   string
+	Name      string
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func CreateSecret(
+	ownerId string,
+	name string,
+	value string) (*Secret, *internal_error.InternalError) {
+
+	secret :=
+		&Secret{
+			Id:        uuid.New().String(),
+			OwnerId:   ownerId,
+			Name:      name,
+			Value:     value,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+	if err := secret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (secret *Secret) Update(value string) *internal_error.InternalError {
+	secret.Value = value
+	secret.UpdatedAt = time.Now()
+
+	return secret.Validate()
+}
+
+func (secret *Secret) Validate() *internal_error.InternalError {
+	if secret.OwnerId == "" || len(secret.Name) < 3 {
+		return internal_error.NewBadRequestError("invalid secret object")
+	}
+	if secret.Value == "" {
+		return internal_error.NewBadRequestError("invalid secret object. empty value")
+	}
+
+	return nil
+}
+
+type SecretRepositoryInterface interface {
+	CreateSecret(ctx context.Context, secretEntity *Secret) *internal_error.InternalError
+	UpdateSecret(ctx context.Context, secretEntity *Secret) *internal_error.InternalError
+	FindSecretById(ctx context.Context, secretId string) (*Secret, *internal_error.InternalError)
+	FindSecrets(ctx context.Context, ownerId string) ([]*Secret, *internal_error.InternalError)
+	DeleteSecret(ctx context.Context, secretId string) *internal_error.InternalError
+}
+
+// ReencryptableRepositoryInterface is implemented by the repositories that store encrypted
+// values, so they can be encrypted again under the current master key after a rotation.
+type ReencryptableRepositoryInterface interface {
+	ReencryptSecrets(ctx context.Context) (uint, *internal_error.InternalError)
+}
@@ -0,0 +1,231 @@
+package settlement_entity
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// above MAX_EXACT_SETTLEMENT_USERS users with open balances the transfers are no longer the fewest
+// possible, as searching them takes 2^n steps
+const MAX_EXACT_SETTLEMENT_USERS = 16
+
+// Settlement tells, for the invoices of a competence period, how much each user paid and owed,
+// and the transfers that settle the differences.
+type Settlement struct {
+	Period    string
+	Balances  []*Balance
+	Transfers []*Transfer
+}
+
+// Balance is what a user paid for the invoices of the period and their share of it.
+type Balance struct {
+	UserId string
+	Paid   money.Money
+	Owed   money.Money
+}
+
+// Net is positive when the user must receive and negative when they must pay.
+func (b *Balance) Net() money.Money {
+	return b.Paid - b.Owed
+}
+
+type Transfer struct {
+	FromUserId string
+	ToUserId   string
+	Amount     money.Money
+}
+
+// CalculateSettlement settles the invoices of the period. Only what was paid is settled: the
+// amount paid for an invoice is owed by its users in proportion to their shares, and payments with
+// no payer were made by the owner of the bill. Invoices without shares are owed by the owner.
+func CalculateSettlement(
+	period string,
+	bills []*bill_entity.Bill,
+	invoices []*invoice_entity.Invoice) *Settlement {
+
+	billsById := make(map[string]*bill_entity.Bill)
+	for _, bill := range bills {
+		billsById[bill.Id] = bill
+	}
+
+	balancesByUser := make(map[string]*Balance)
+	balance := func(userId string) *Balance {
+		if balancesByUser[userId] == nil {
+			balancesByUser[userId] = &Balance{UserId: userId}
+		}
+		return balancesByUser[userId]
+	}
+
+	for _, invoice := range invoices {
+		bill, found := billsById[invoice.BillId]
+		if invoice.Period != period || !found {
+			continue
+		}
+
+		paid := invoice.PaidAmount()
+		if invoice.Status == invoice_entity.Paid && len(invoice.Payments) == 0 {
+			paid = invoice.Amount // created as paid, by the owner
+			balance(bill.UserId).Paid += paid
+		}
+		if paid == 0 {
+			continue
+		}
+
+		for _, payment := range invoice.Payments {
+			payer := payment.PaidBy
+			if payer == "" {
+				payer = bill.UserId
+			}
+			balance(payer).Paid += payment.Amount
+		}
+
+		shares := invoice.Shares
+		if len(shares) == 0 {
+			shares = bill.InvoiceShares(invoice.Amount)
+		}
+
+		weights := make([]float64, len(shares))
+		for i, share := range shares {
+			weights[i] = float64(share.Amount)
+		}
+
+		for i, owed := range paid.Allocate(weights) {
+			balance(shares[i].UserId).Owed += owed
+		}
+	}
+
+	settlement := &Settlement{Period: period, Balances: make([]*Balance, 0, len(balancesByUser))}
+	for _, b := range balancesByUser {
+		settlement.Balances = append(settlement.Balances, b)
+	}
+	slices.SortFunc(settlement.Balances, func(a, b *Balance) int {
+		return strings.Compare(a.UserId, b.UserId)
+	})
+
+	settlement.Transfers = minimalTransfers(settlement.Balances)
+
+	return settlement
+}
+
+// minimalTransfers settles the balances with the fewest transfers. Users whose balances can be split
+// into k groups that sum to zero need n-k transfers, one less per group, so every subset of the users
+// is searched for the split with the most groups, and each group is then settled on its own.
+func minimalTransfers(balances []*Balance) []*Transfer {
+	open := make([]*Balance, 0)
+	for _, b := range balances {
+		if b.Net() != 0 {
+			open = append(open, b)
+		}
+	}
+
+	n := len(open)
+	if n > MAX_EXACT_SETTLEMENT_USERS {
+		return greedyTransfers(open)
+	}
+
+	// groups[mask] is the most zero-sum groups the users of the mask can be split into, counted
+	// while adding the users one by one
+	full := 1<<n - 1
+	sums := make([]money.Money, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		sums[mask] = sums[mask&(mask-1)] + open[bits.TrailingZeros(uint(mask))].Net()
+		for i := 0; i < n; i++ {
+			if bit := 1 << i; mask&bit != 0 {
+				groups[mask] = max(groups[mask], groups[mask^bit])
+			}
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// the order in which the users were added: a group closes whenever the sum goes back to zero
+	order := make([]int, 0, n)
+	for mask := full; mask != 0; {
+		closesGroup := 0
+		if sums[mask] == 0 {
+			closesGroup = 1
+		}
+		for i := 0; i < n; i++ {
+			if bit := 1 << i; mask&bit != 0 && groups[mask^bit]+closesGroup == groups[mask] {
+				order = append(order, i)
+				mask ^= bit
+				break
+			}
+		}
+	}
+	slices.Reverse(order)
+
+	transfers := make([]*Transfer, 0)
+	group := make([]*Balance, 0)
+	mask := 0
+	for _, i := range order {
+		mask |= 1 << i
+		group = append(group, open[i])
+		if sums[mask] == 0 {
+			transfers = append(transfers, greedyTransfers(group)...)
+			group = make([]*Balance, 0)
+		}
+	}
+
+	// balances that don't sum to zero, which payments never leave
+	return append(transfers, greedyTransfers(group)...)
+}
+
+// greedyTransfers pays the largest debt to the largest credit until every balance is settled.
+// It takes at most n-1 transfers for n users, not necessarily the fewest possible.
+func greedyTransfers(balances []*Balance) []*Transfer {
+	type position struct {
+		userId string
+		amount money.Money
+	}
+
+	debtors := make([]*position, 0)
+	creditors := make([]*position, 0)
+	for _, b := range balances {
+		switch net := b.Net(); {
+		case net < 0:
+			debtors = append(debtors, &position{b.UserId, -net})
+		case net > 0:
+			creditors = append(creditors, &position{b.UserId, net})
+		}
+	}
+
+	largestFirst := func(a, b *position) int {
+		if a.amount != b.amount {
+			return cmp.Compare(b.amount, a.amount)
+		}
+		return strings.Compare(a.userId, b.userId)
+	}
+	slices.SortFunc(debtors, largestFirst)
+	slices.SortFunc(creditors, largestFirst)
+
+	transfers := make([]*Transfer, 0)
+	for d, c := 0, 0; d < len(debtors) && c < len(creditors); {
+		amount := min(debtors[d].amount, creditors[c].amount)
+
+		transfers = append(transfers, &Transfer{
+			FromUserId: debtors[d].userId,
+			ToUserId:   creditors[c].userId,
+			Amount:     amount,
+		})
+
+		debtors[d].amount -= amount
+		creditors[c].amount -= amount
+		if debtors[d].amount == 0 {
+			d++
+		}
+		if creditors[c].amount == 0 {
+			c++
+		}
+	}
+
+	return transfers
+}
@@ -0,0 +1,159 @@
+package settlement_entity
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+func TestCalculateSettlement(t *testing.T) {
+	equalSplit := bill_entity.SplitRule{
+		Type:   bill_entity.EqualSplit,
+		Shares: []bill_entity.Share{{UserId: "ana"}, {UserId: "bia"}},
+	}
+
+	tests := []struct {
+		name      string
+		bills     []*bill_entity.Bill
+		invoices  []*invoice_entity.Invoice
+		balances  []Balance
+		transfers []Transfer
+	}{
+		{
+			name:  "unshared bill paid by the owner",
+			bills: []*bill_entity.Bill{{Id: "rent", UserId: "ana"}},
+			invoices: []*invoice_entity.Invoice{
+				paidInvoice("rent", 10000, &invoice_entity.Payment{Amount: 10000}),
+			},
+			balances: []Balance{{UserId: "ana", Paid: 10000, Owed: 10000}},
+		},
+		{
+			name:  "equal split with an odd cent",
+			bills: []*bill_entity.Bill{{Id: "water", UserId: "ana", Split: equalSplit}},
+			invoices: []*invoice_entity.Invoice{
+				paidInvoice("water", 10001, &invoice_entity.Payment{Amount: 10001}),
+			},
+			balances:  []Balance{{UserId: "ana", Paid: 10001, Owed: 5001}, {UserId: "bia", Owed: 5000}},
+			transfers: []Transfer{{FromUserId: "bia", ToUserId: "ana", Amount: 5000}},
+		},
+		{
+			name:  "paid by another user",
+			bills: []*bill_entity.Bill{{Id: "water", UserId: "ana", Split: equalSplit}},
+			invoices: []*invoice_entity.Invoice{
+				paidInvoice("water", 10000, &invoice_entity.Payment{Amount: 10000, PaidBy: "bia"}),
+			},
+			balances:  []Balance{{UserId: "ana", Owed: 5000}, {UserId: "bia", Paid: 10000, Owed: 5000}},
+			transfers: []Transfer{{FromUserId: "ana", ToUserId: "bia", Amount: 5000}},
+		},
+		{
+			name:  "created as paid",
+			bills: []*bill_entity.Bill{{Id: "water", UserId: "ana", Split: equalSplit}},
+			invoices: []*invoice_entity.Invoice{
+				paidInvoice("water", 8000),
+			},
+			balances:  []Balance{{UserId: "ana", Paid: 8000, Owed: 4000}, {UserId: "bia", Owed: 4000}},
+			transfers: []Transfer{{FromUserId: "bia", ToUserId: "ana", Amount: 4000}},
+		},
+		{
+			name:  "partial payment owed in proportion to the shares",
+			bills: []*bill_entity.Bill{{Id: "power", UserId: "ana"}},
+			invoices: []*invoice_entity.Invoice{{
+				BillId:   "power",
+				Period:   "2024-05",
+				Amount:   10000,
+				Status:   invoice_entity.PartiallyPaid,
+				Shares:   []invoice_entity.Share{{UserId: "ana", Amount: 6000}, {UserId: "bia", Amount: 4000}},
+				Payments: []*invoice_entity.Payment{{Amount: 5000}},
+			}},
+			balances:  []Balance{{UserId: "ana", Paid: 5000, Owed: 3000}, {UserId: "bia", Owed: 2000}},
+			transfers: []Transfer{{FromUserId: "bia", ToUserId: "ana", Amount: 2000}},
+		},
+		{
+			name:  "unpaid invoices, other periods and unknown bills are ignored",
+			bills: []*bill_entity.Bill{{Id: "water", UserId: "ana", Split: equalSplit}},
+			invoices: []*invoice_entity.Invoice{
+				{BillId: "water", Period: "2024-05", Amount: 10000, Status: invoice_entity.Unpaid},
+				{BillId: "water", Period: "2024-04", Amount: 10000, Status: invoice_entity.Paid},
+				{BillId: "gas", Period: "2024-05", Amount: 10000, Status: invoice_entity.Paid},
+			},
+		},
+		{
+			// nets of +20, +30, -10, -20 and -20 settle in 3 transfers, as {ana, dan} and
+			// {bia, caio, edu} sum to zero. Paying the largest debts first would take 4.
+			name: "balances split into groups that sum to zero",
+			bills: []*bill_entity.Bill{
+				{Id: "rent", UserId: "ana"},
+				{Id: "market", UserId: "bia"},
+			},
+			invoices: []*invoice_entity.Invoice{
+				sharedInvoice("rent", invoice_entity.Share{UserId: "dan", Amount: 2000}),
+				sharedInvoice("market",
+					invoice_entity.Share{UserId: "caio", Amount: 1000},
+					invoice_entity.Share{UserId: "edu", Amount: 2000}),
+			},
+			balances: []Balance{
+				{UserId: "ana", Paid: 2000},
+				{UserId: "bia", Paid: 3000},
+				{UserId: "caio", Owed: 1000},
+				{UserId: "dan", Owed: 2000},
+				{UserId: "edu", Owed: 2000},
+			},
+			transfers: []Transfer{
+				{FromUserId: "caio", ToUserId: "bia", Amount: 1000},
+				{FromUserId: "dan", ToUserId: "ana", Amount: 2000},
+				{FromUserId: "edu", ToUserId: "bia", Amount: 2000},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settlement := CalculateSettlement("2024-05", tt.bills, tt.invoices)
+
+			balances := make([]Balance, len(settlement.Balances))
+			for i, b := range settlement.Balances {
+				balances[i] = *b
+			}
+			if !slices.Equal(balances, tt.balances) {
+				t.Errorf("balances = %+v, want %+v", balances, tt.balances)
+			}
+
+			transfers := make([]Transfer, len(settlement.Transfers))
+			for i, transfer := range settlement.Transfers {
+				transfers[i] = *transfer
+			}
+			slices.SortFunc(transfers, func(a, b Transfer) int {
+				return cmp.Or(strings.Compare(a.FromUserId, b.FromUserId), strings.Compare(a.ToUserId, b.ToUserId))
+			})
+			if !slices.Equal(transfers, tt.transfers) {
+				t.Errorf("transfers = %+v, want %+v", transfers, tt.transfers)
+			}
+		})
+	}
+}
+
+func paidInvoice(billId string, amount money.Money, payments ...*invoice_entity.Payment) *invoice_entity.Invoice {
+	return &invoice_entity.Invoice{
+		BillId:   billId,
+		Period:   "2024-05",
+		Amount:   amount,
+		Status:   invoice_entity.Paid,
+		Payments: payments,
+	}
+}
+
+func sharedInvoice(billId string, shares ...invoice_entity.Share) *invoice_entity.Invoice {
+	var amount money.Money
+	for _, share := range shares {
+		amount += share.Amount
+	}
+
+	invoice := paidInvoice(billId, amount, &invoice_entity.Payment{Amount: amount})
+	invoice.Shares = shares
+	return invoice
+}
@@ -2,10 +2,12 @@ package table_value_source_entity
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type TableValueSource struct {
@@ -19,7 +21,7 @@ type TableValueSource struct {
 
 type TableValueSourceData struct {
 	Period TableValueSourceDataPeriod
-	Amount float64
+	Amount money.Money
 }
 
 type TableValueSourceDataPeriod struct {
@@ -51,7 +53,8 @@ func GetTableValueSourceStatusByName(name string) (TableValueSourceStatus, *inte
 		}
 	}
 
-	return TableValueSourceStatus(0), internal_error.NewBadRequestError("invalid tableValueSource status name")
+	return TableValueSourceStatus(0), internal_error.NewBadRequestError("invalid tableValueSource status name",
+		internal_error.Cause{Field: "status", Message: "status must be one of active or inactive"})
 }
 
 func CreateTableValueSource(
@@ -123,13 +126,33 @@ func (tableValueSource *TableValueSource) Update(
 }
 
 func (tableValueSource *TableValueSource) Validate() *internal_error.InternalError {
+	causes := make([]internal_error.Cause, 0)
+
 	if len(tableValueSource.Name) < 3 {
-		return internal_error.NewBadRequestError("invalid tableValueSource object")
+		causes = append(causes, internal_error.Cause{Field: "name", Message: "name must be at least 3 characters in length"})
 	}
-	for _, v := range tableValueSource.Data {
-		if v.Period.Month > 12 || v.Period.Year > 9999 {
-			return internal_error.NewBadRequestError("invalid tableValueSource data")
+
+	periods := make(map[TableValueSourceDataPeriod]bool)
+	for i, v := range tableValueSource.Data {
+		field := fmt.Sprintf("data[%d]", i)
+
+		if v.Period.Month < 1 || v.Period.Month > 12 {
+			causes = append(causes, internal_error.Cause{Field: field + ".period.month", Message: "month must be between 1 and 12"})
+		}
+		if v.Period.Year < 1900 || v.Period.Year > 9999 {
+			causes = append(causes, internal_error.Cause{Field: field + ".period.year", Message: "year must be between 1900 and 9999"})
 		}
+		if v.Amount < 0 {
+			causes = append(causes, internal_error.Cause{Field: field + ".amount", Message: "amount must not be negative"})
+		}
+		if periods[v.Period] {
+			causes = append(causes, internal_error.Cause{Field: field + ".period", Message: "period is informed more than once"})
+		}
+		periods[v.Period] = true
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid tableValueSource object", causes...)
 	}
 
 	return nil
@@ -143,4 +166,5 @@ type TableValueSourceRepositoryInterface interface {
 		status TableValueSourceStatus,
 		name string) ([]*TableValueSource, *internal_error.InternalError)
 	UpdateTableValueSource(ctx context.Context, tableValueSourceEntity *TableValueSource) *internal_error.InternalError
+	DeleteTableValueSource(ctx context.Context, tableValueSourceId string) *internal_error.InternalError
 }
@@ -6,15 +6,19 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
-	Id        string
-	Name      string
-	Email     string
-	Status    UserStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id     string
+	Name   string
+	Email  string
+	Status UserStatus
+	// PasswordHash is the bcrypt hash of the password. Users created before authentication existed
+	// have none until they define it.
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type UserStatus uint8
@@ -46,7 +50,8 @@ func GetUserStatusByName(name string) (UserStatus, *internal_error.InternalError
 
 func CreateUser(
 	name string,
-	email string) (*User, *internal_error.InternalError) {
+	email string,
+	password string) (*User, *internal_error.InternalError) {
 
 	user :=
 		&User{
@@ -62,13 +67,51 @@ func CreateUser(
 		return nil, err
 	}
 
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
+// SetPassword replaces the password of the user, keeping only its hash.
+func (user *User) SetPassword(password string) *internal_error.InternalError {
+	if len(password) < 8 || len(password) > 72 {
+		return internal_error.NewBadRequestError("invalid user password",
+			internal_error.Cause{Field: "password", Message: "password must be between 8 and 72 characters in length"})
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return internal_error.NewInternalServerError("Error trying to hash the user password")
+	}
+
+	user.PasswordHash = string(hash)
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// CheckPassword reports whether the password is the one of the user.
+func (user *User) CheckPassword(password string) bool {
+	if user.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
 func (user *User) Validate() *internal_error.InternalError {
-	if len(user.Name) <= 1 ||
-		len(user.Email) <= 5 {
-		return internal_error.NewBadRequestError("invalid user object")
+	causes := make([]internal_error.Cause, 0)
+
+	if len(user.Name) <= 1 {
+		causes = append(causes, internal_error.Cause{Field: "name", Message: "name must be at least 2 characters in length"})
+	}
+	if len(user.Email) <= 5 {
+		causes = append(causes, internal_error.Cause{Field: "email", Message: "email must be at least 6 characters in length"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid user object", causes...)
 	}
 
 	return nil
@@ -76,7 +119,10 @@ func (user *User) Validate() *internal_error.InternalError {
 
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, userEntity *User) *internal_error.InternalError
+	UpdateUser(ctx context.Context, userEntity *User) *internal_error.InternalError
 	FindUserById(ctx context.Context, userId string) (*User, *internal_error.InternalError)
+	FindUserByEmail(ctx context.Context, email string) (*User, *internal_error.InternalError)
+	FindUsersByIds(ctx context.Context, userIds []string) ([]*User, *internal_error.InternalError)
 	FindUsers(
 		ctx context.Context,
 		status UserStatus,
@@ -0,0 +1,108 @@
+package attachment_controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/attachment_usecase"
+)
+
+// room for the other form fields and the multipart boundaries, besides the file itself
+const MULTIPART_OVERHEAD = 1 << 20
+
+type AttachmentController struct {
+	attachmentUseCase attachment_usecase.AttachmentUseCaseInterface
+}
+
+func NewAttachmentController(attachmentUseCase attachment_usecase.AttachmentUseCaseInterface) *AttachmentController {
+	return &AttachmentController{
+		attachmentUseCase: attachmentUseCase,
+	}
+}
+
+// CreateAttachment receives a multipart form with the document in "file" and, optionally,
+// its "kind" (boleto, receipt or other).
+func (u *AttachmentController) CreateAttachment(c *gin.Context) {
+	invoiceId := c.Param("id")
+
+	if !validateIds(c, "id") {
+		return
+	}
+
+	// the body is limited before parsing, so an oversized upload is not spooled to disk first
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, u.attachmentUseCase.MaxSize()+MULTIPART_OVERHEAD)
+
+	fileHeader, err := c.FormFile("file")
+	if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "file",
+			Message: fmt.Sprintf("File too large. Maximum size is %d bytes", u.attachmentUseCase.MaxSize()),
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "file",
+			Message: "File is required",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if fileHeader.Size > u.attachmentUseCase.MaxSize() {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "file",
+			Message: fmt.Sprintf("File too large. Maximum size is %d bytes", u.attachmentUseCase.MaxSize()),
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to read uploaded file")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	defer file.Close()
+
+	attachmentData, e := u.attachmentUseCase.CreateAttachment(c.Request.Context(), attachment_usecase.CreateAttachmentInputDTO{
+		InvoiceId: invoiceId,
+		Kind:      c.PostForm("kind"),
+		FileName:  filepath.Base(fileHeader.Filename),
+		Content:   file,
+	})
+	if e != nil {
+		restErr := rest_err.ConvertError(e)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachmentData)
+}
+
+func validateIds(c *gin.Context, params ...string) bool {
+	for _, param := range params {
+		if err := uuid.Validate(c.Param(param)); err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   param,
+				Message: "Invalid UUID value",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,23 @@
+package attachment_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+)
+
+func (u *AttachmentController) DeleteAttachment(c *gin.Context) {
+	if !validateIds(c, "id", "attachmentId") {
+		return
+	}
+
+	err := u.attachmentUseCase.DeleteAttachment(c.Request.Context(), c.Param("id"), c.Param("attachmentId"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package attachment_controller
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+)
+
+func (u *AttachmentController) FindAttachments(c *gin.Context) {
+	if !validateIds(c, "id") {
+		return
+	}
+
+	attachments, err := u.attachmentUseCase.FindAttachments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (u *AttachmentController) DownloadAttachment(c *gin.Context) {
+	if !validateIds(c, "id", "attachmentId") {
+		return
+	}
+
+	attachment, content, err := u.attachmentUseCase.DownloadAttachment(c.Request.Context(),
+		c.Param("id"), c.Param("attachmentId"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"ETag":                `"` + attachment.Checksum + `"`,
+	})
+}
@@ -0,0 +1,102 @@
+package auth_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/auth_usecase"
+)
+
+type AuthController struct {
+	authUseCase auth_usecase.AuthUseCaseInterface
+}
+
+func NewAuthController(authUseCase auth_usecase.AuthUseCaseInterface) *AuthController {
+	return &AuthController{
+		authUseCase: authUseCase,
+	}
+}
+
+func (u *AuthController) Login(c *gin.Context) {
+	var loginInputDTO auth_usecase.LoginInputDTO
+
+	if err := c.ShouldBindJSON(&loginInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.authUseCase.Login(c.Request.Context(), loginInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (u *AuthController) Refresh(c *gin.Context) {
+	var refreshInputDTO auth_usecase.RefreshInputDTO
+
+	if err := c.ShouldBindJSON(&refreshInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.authUseCase.Refresh(c.Request.Context(), refreshInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (u *AuthController) Logout(c *gin.Context) {
+	var refreshInputDTO auth_usecase.RefreshInputDTO
+
+	if err := c.ShouldBindJSON(&refreshInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	if err := u.authUseCase.Logout(c.Request.Context(), refreshInputDTO); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *AuthController) SetupPassword(c *gin.Context) {
+	var setupInputDTO auth_usecase.PasswordSetupInputDTO
+
+	if err := c.ShouldBindJSON(&setupInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	if err := u.authUseCase.SetupPassword(c.Request.Context(), setupInputDTO); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -1,7 +1,6 @@
 package bill_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +29,7 @@ func (u *BillController) CreateBill(c *gin.Context) {
 		return
 	}
 
-	err := u.billUseCase.CreateBill(context.Background(), billInputDTO)
+	err := u.billUseCase.CreateBill(c.Request.Context(), billInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
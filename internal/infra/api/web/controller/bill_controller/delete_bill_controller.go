@@ -0,0 +1,25 @@
+package bill_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+)
+
+func (u *BillController) DeleteBill(c *gin.Context) {
+	billId := c.Param("id")
+
+	if !validateBillId(c, billId) {
+		return
+	}
+
+	output, err := u.billUseCase.DeleteBill(c.Request.Context(), billId, c.Query("valueSource"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -1,13 +1,13 @@
 package bill_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/bill_usecase"
 )
 
 func (u *BillController) FindBillById(c *gin.Context) {
@@ -23,7 +23,7 @@ func (u *BillController) FindBillById(c *gin.Context) {
 		return
 	}
 
-	billData, err := u.billUseCase.FindBillById(context.Background(), billId)
+	billData, err := u.billUseCase.FindBillById(c.Request.Context(), billId, c.Query("period"))
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -46,8 +46,15 @@ func (u *BillController) FindBills(c *gin.Context) {
 		return
 	}
 
-	auctions, err := u.billUseCase.FindBills(context.Background(),
-		billStatus, userId, name, company)
+	auctions, err := u.billUseCase.FindBills(c.Request.Context(), bill_usecase.FindBillsInputDTO{
+		Status:     billStatus,
+		UserId:     userId,
+		Name:       name,
+		Company:    company,
+		CategoryId: c.Query("categoryId"),
+		Tag:        c.Query("tag"),
+		Period:     c.Query("period"),
+	})
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -0,0 +1,65 @@
+package bill_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/bill_usecase"
+)
+
+func (u *BillController) AddBillSuspension(c *gin.Context) {
+	billId := c.Param("id")
+
+	if !validateBillId(c, billId) {
+		return
+	}
+
+	var suspensionInputDTO bill_usecase.SuspensionInputDTO
+
+	if err := c.ShouldBindJSON(&suspensionInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.billUseCase.AddBillSuspension(c.Request.Context(), billId, suspensionInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *BillController) DeleteBillSuspension(c *gin.Context) {
+	billId := c.Param("id")
+	suspensionId := c.Param("suspensionId")
+
+	for _, field := range []string{"id", "suspensionId"} {
+		if err := uuid.Validate(c.Param(field)); err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   field,
+				Message: "Invalid UUID value",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+	}
+
+	err := u.billUseCase.DeleteBillSuspension(c.Request.Context(), billId, suspensionId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
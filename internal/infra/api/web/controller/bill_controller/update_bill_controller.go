@@ -0,0 +1,79 @@
+package bill_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/bill_usecase"
+)
+
+func (u *BillController) UpdateBill(c *gin.Context) {
+	billId := c.Param("id")
+
+	if !validateBillId(c, billId) {
+		return
+	}
+
+	var billInputDTO bill_usecase.UpdateBillInputDTO
+
+	if err := c.ShouldBindJSON(&billInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	err := u.billUseCase.UpdateBill(c.Request.Context(), billId, billInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (u *BillController) UpdateBillStatus(c *gin.Context) {
+	billId := c.Param("id")
+
+	if !validateBillId(c, billId) {
+		return
+	}
+
+	var statusInputDTO bill_usecase.UpdateBillStatusInputDTO
+
+	if err := c.ShouldBindJSON(&statusInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	err := u.billUseCase.UpdateBillStatus(c.Request.Context(), billId, statusInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func validateBillId(c *gin.Context, billId string) bool {
+	if err := uuid.Validate(billId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return false
+	}
+
+	return true
+}
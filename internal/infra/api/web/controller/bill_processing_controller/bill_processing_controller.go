@@ -1,7 +1,6 @@
 package bill_processing_controller
 
 import (
-	"context"
 	"net/http"
 	"time"
 
@@ -37,7 +36,7 @@ func (u *BillProcessingController) StartBillProcessing(c *gin.Context) {
 		}
 	}
 
-	billProcessingOutput, err := u.billProcessingUseCase.StartBillProcessing(context.Background(), period)
+	billProcessingOutput, err := u.billProcessingUseCase.StartBillProcessing(c.Request.Context(), period)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -51,7 +50,7 @@ func (u *BillProcessingController) StartBillProcessing(c *gin.Context) {
 func (u *BillProcessingController) GetBillProcessingStatus(c *gin.Context) {
 	billProcessingId := c.Param("id")
 
-	status, err := u.billProcessingUseCase.GetBillProcessingStatus(context.Background(), billProcessingId)
+	status, err := u.billProcessingUseCase.GetBillProcessingStatus(c.Request.Context(), billProcessingId)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -72,7 +71,7 @@ func (u *BillProcessingController) FindBillProcessings(c *gin.Context) {
 		return
 	}
 
-	billProcessings, err := u.billProcessingUseCase.FindBillProcessings(context.Background(), billStatus)
+	billProcessings, err := u.billProcessingUseCase.FindBillProcessings(c.Request.Context(), billStatus)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
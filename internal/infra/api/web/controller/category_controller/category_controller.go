@@ -0,0 +1,137 @@
+package category_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/category_usecase"
+)
+
+type CategoryController struct {
+	categoryUseCase category_usecase.CategoryUseCaseInterface
+}
+
+func NewCategoryController(categoryUseCase category_usecase.CategoryUseCaseInterface) *CategoryController {
+	return &CategoryController{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+func (u *CategoryController) CreateCategory(c *gin.Context) {
+	var categoryInputDTO category_usecase.CategoryInputDTO
+
+	if err := c.ShouldBindJSON(&categoryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.categoryUseCase.CreateCategory(c.Request.Context(), categoryInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *CategoryController) FindCategoryById(c *gin.Context) {
+	categoryId := c.Param("id")
+	if !validateCategoryId(c, categoryId) {
+		return
+	}
+
+	categoryData, err := u.categoryUseCase.FindCategoryById(c.Request.Context(), categoryId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categoryData)
+}
+
+func (u *CategoryController) FindCategories(c *gin.Context) {
+	categories, err := u.categoryUseCase.FindCategories(c.Request.Context())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func (u *CategoryController) UpdateCategory(c *gin.Context) {
+	categoryId := c.Param("id")
+	if !validateCategoryId(c, categoryId) {
+		return
+	}
+
+	var categoryInputDTO category_usecase.UpdateCategoryInputDTO
+
+	if err := c.ShouldBindJSON(&categoryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	err := u.categoryUseCase.UpdateCategory(c.Request.Context(), categoryId, categoryInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (u *CategoryController) DeleteCategory(c *gin.Context) {
+	categoryId := c.Param("id")
+	if !validateCategoryId(c, categoryId) {
+		return
+	}
+
+	err := u.categoryUseCase.DeleteCategory(c.Request.Context(), categoryId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *CategoryController) FindCategoryBudgets(c *gin.Context) {
+	budgets, err := u.categoryUseCase.FindCategoryBudgets(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+func validateCategoryId(c *gin.Context, categoryId string) bool {
+	if err := uuid.Validate(categoryId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return false
+	}
+
+	return true
+}
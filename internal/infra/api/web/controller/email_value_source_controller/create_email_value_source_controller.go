@@ -1,7 +1,6 @@
 package email_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +29,7 @@ func (u *EmailValueSourceController) CreateEmailValueSource(c *gin.Context) {
 		return
 	}
 
-	err := u.EmailValueSourceUseCase.CreateEmailValueSource(context.Background(), emailValueSourceInputDTO)
+	err := u.EmailValueSourceUseCase.CreateEmailValueSource(c.Request.Context(), emailValueSourceInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
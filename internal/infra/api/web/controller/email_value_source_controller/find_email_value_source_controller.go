@@ -1,7 +1,6 @@
 package email_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +21,7 @@ func (u *EmailValueSourceController) FindEmailValueSourceById(c *gin.Context) {
 		return
 	}
 
-	emailValueSourceData, err := u.EmailValueSourceUseCase.FindEmailValueSourceById(context.Background(), emailValueSourceId)
+	emailValueSourceData, err := u.EmailValueSourceUseCase.FindEmailValueSourceById(c.Request.Context(), emailValueSourceId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -36,7 +35,7 @@ func (u *EmailValueSourceController) FindEmailValueSources(c *gin.Context) {
 	address := c.Query("address")
 	subject := c.Query("subject")
 
-	auctions, err := u.EmailValueSourceUseCase.FindEmailValueSources(context.Background(), address, subject)
+	auctions, err := u.EmailValueSourceUseCase.FindEmailValueSources(c.Request.Context(), address, subject)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -1,7 +1,6 @@
 package email_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +21,7 @@ func (u *EmailValueSourceController) UpdateEmailValueSource(c *gin.Context) {
 		return
 	}
 
-	err := u.EmailValueSourceUseCase.UpdateEmailValueSource(context.Background(), emailValueSourceId, emailValueSourceInputDTO)
+	err := u.EmailValueSourceUseCase.UpdateEmailValueSource(c.Request.Context(), emailValueSourceId, emailValueSourceInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
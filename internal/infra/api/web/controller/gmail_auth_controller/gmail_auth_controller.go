@@ -0,0 +1,69 @@
+package gmail_auth_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/gmail_auth_usecase"
+)
+
+type GmailAuthController struct {
+	gmailAuthUseCase gmail_auth_usecase.GmailAuthUseCaseInterface
+}
+
+func NewGmailAuthController(gmailAuthUseCase gmail_auth_usecase.GmailAuthUseCaseInterface) *GmailAuthController {
+	return &GmailAuthController{
+		gmailAuthUseCase: gmailAuthUseCase,
+	}
+}
+
+func (u *GmailAuthController) StartGmailAuth(c *gin.Context) {
+	mailAccountId := c.Query("mailAccountId")
+
+	output, err := u.gmailAuthUseCase.StartGmailAuth(c.Request.Context(), mailAccountId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Redirect(http.StatusFound, output.AuthURL)
+}
+
+func (u *GmailAuthController) GmailAuthCallback(c *gin.Context) {
+	if authErr := c.Query("error"); authErr != "" {
+		restErr := rest_err.NewBadRequestError("Gmail authorization denied: " + authErr)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	state := c.Query("state")
+	code := c.Query("code")
+
+	status, err := u.gmailAuthUseCase.CompleteGmailAuth(c.Request.Context(), state, code)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (u *GmailAuthController) GetGmailAuthStatus(c *gin.Context) {
+	mailAccountId := c.Query("mailAccountId")
+
+	status, err := u.gmailAuthUseCase.GetGmailAuthStatus(c.Request.Context(), mailAccountId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
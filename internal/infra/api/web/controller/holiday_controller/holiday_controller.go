@@ -0,0 +1,95 @@
+package holiday_controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/holiday_usecase"
+)
+
+type HolidayController struct {
+	holidayUseCase holiday_usecase.HolidayUseCaseInterface
+}
+
+func NewHolidayController(holidayUseCase holiday_usecase.HolidayUseCaseInterface) *HolidayController {
+	return &HolidayController{
+		holidayUseCase: holidayUseCase,
+	}
+}
+
+func (u *HolidayController) CreateHoliday(c *gin.Context) {
+	var holidayInputDTO holiday_usecase.HolidayInputDTO
+
+	if err := c.ShouldBindJSON(&holidayInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.holidayUseCase.CreateHoliday(c.Request.Context(), holidayInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *HolidayController) FindHolidays(c *gin.Context) {
+	year := time.Now().Year()
+
+	if yearParam := c.Query("year"); yearParam != "" {
+		parsed, e := strconv.Atoi(yearParam)
+		if e != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "year",
+				Message: "Invalid year value",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+		year = parsed
+	}
+
+	holidays, err := u.holidayUseCase.FindHolidays(c.Request.Context(), year, c.Query("state"), c.Query("city"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+func (u *HolidayController) DeleteHoliday(c *gin.Context) {
+	holidayId := c.Param("id")
+
+	if err := uuid.Validate(holidayId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	err := u.holidayUseCase.DeleteHoliday(c.Request.Context(), holidayId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
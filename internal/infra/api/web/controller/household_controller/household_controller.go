@@ -0,0 +1,172 @@
+package household_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/middleware"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/household_usecase"
+)
+
+type HouseholdController struct {
+	householdUseCase household_usecase.HouseholdUseCaseInterface
+}
+
+func NewHouseholdController(householdUseCase household_usecase.HouseholdUseCaseInterface) *HouseholdController {
+	return &HouseholdController{
+		householdUseCase: householdUseCase,
+	}
+}
+
+func (u *HouseholdController) CreateHousehold(c *gin.Context) {
+	var householdInputDTO household_usecase.HouseholdInputDTO
+
+	if err := c.ShouldBindJSON(&householdInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.householdUseCase.CreateHousehold(c.Request.Context(), c.GetString(middleware.UserIdKey), householdInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *HouseholdController) FindHouseholdById(c *gin.Context) {
+	householdId := c.Param("id")
+	if !validateId(c, "id", householdId) {
+		return
+	}
+
+	householdData, err := u.householdUseCase.FindHouseholdById(c.Request.Context(), c.GetString(middleware.UserIdKey), householdId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, householdData)
+}
+
+func (u *HouseholdController) FindHouseholds(c *gin.Context) {
+	households, err := u.householdUseCase.FindHouseholds(c.Request.Context(), c.GetString(middleware.UserIdKey))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, households)
+}
+
+func (u *HouseholdController) InviteMember(c *gin.Context) {
+	householdId := c.Param("id")
+	if !validateId(c, "id", householdId) {
+		return
+	}
+
+	var memberInputDTO household_usecase.MemberInputDTO
+
+	if err := c.ShouldBindJSON(&memberInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.householdUseCase.InviteMember(c.Request.Context(), c.GetString(middleware.UserIdKey), householdId, memberInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *HouseholdController) ChangeMemberRole(c *gin.Context) {
+	householdId := c.Param("id")
+	memberId := c.Param("memberId")
+	if !validateId(c, "id", householdId) || !validateId(c, "memberId", memberId) {
+		return
+	}
+
+	var roleInputDTO household_usecase.MemberRoleInputDTO
+
+	if err := c.ShouldBindJSON(&roleInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	err := u.householdUseCase.ChangeMemberRole(c.Request.Context(), c.GetString(middleware.UserIdKey), householdId, memberId, roleInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (u *HouseholdController) RemoveMember(c *gin.Context) {
+	householdId := c.Param("id")
+	memberId := c.Param("memberId")
+	if !validateId(c, "id", householdId) || !validateId(c, "memberId", memberId) {
+		return
+	}
+
+	err := u.householdUseCase.RemoveMember(c.Request.Context(), c.GetString(middleware.UserIdKey), householdId, memberId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *HouseholdController) AcceptInvitation(c *gin.Context) {
+	householdId := c.Param("id")
+	if !validateId(c, "id", householdId) {
+		return
+	}
+
+	err := u.householdUseCase.AcceptInvitation(c.Request.Context(), c.GetString(middleware.UserIdKey), householdId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func validateId(c *gin.Context, field string, id string) bool {
+	if err := uuid.Validate(id); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   field,
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return false
+	}
+
+	return true
+}
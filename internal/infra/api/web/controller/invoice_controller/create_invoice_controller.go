@@ -1,7 +1,6 @@
 package invoice_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +29,7 @@ func (u *InvoiceController) CreateInvoice(c *gin.Context) {
 		return
 	}
 
-	err := u.invoiceUseCase.CreateInvoice(context.Background(), invoiceInputDTO)
+	err := u.invoiceUseCase.CreateInvoice(c.Request.Context(), invoiceInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
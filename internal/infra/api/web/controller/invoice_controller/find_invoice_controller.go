@@ -1,13 +1,13 @@
 package invoice_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/invoice_usecase"
 )
 
 func (u *InvoiceController) FindInvoiceById(c *gin.Context) {
@@ -23,7 +23,9 @@ func (u *InvoiceController) FindInvoiceById(c *gin.Context) {
 		return
 	}
 
-	invoiceData, err := u.invoiceUseCase.FindInvoiceById(context.Background(), invoiceId)
+	asOf := c.Query("asOf")
+
+	invoiceData, err := u.invoiceUseCase.FindInvoiceById(c.Request.Context(), invoiceId, asOf)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -44,8 +46,16 @@ func (u *InvoiceController) FindInvoices(c *gin.Context) {
 		return
 	}
 
-	auctions, err := u.invoiceUseCase.FindInvoices(context.Background(),
-		billId, invoiceStatus)
+	auctions, err := u.invoiceUseCase.FindInvoices(c.Request.Context(), invoice_usecase.FindInvoicesInputDTO{
+		BillId:     billId,
+		UserId:     c.Query("userId"),
+		CategoryId: c.Query("categoryId"),
+		Tag:        c.Query("tag"),
+		Status:     invoiceStatus,
+		Period:     c.Query("period"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	})
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
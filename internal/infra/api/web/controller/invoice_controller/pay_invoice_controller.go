@@ -0,0 +1,118 @@
+package invoice_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/invoice_usecase"
+)
+
+func (u *InvoiceController) PayInvoice(c *gin.Context) {
+	invoiceId := c.Param("id")
+
+	if err := uuid.Validate(invoiceId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var payInvoiceInputDTO invoice_usecase.PayInvoiceInputDTO
+
+	if err := c.ShouldBindJSON(&payInvoiceInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	invoiceData, err := u.invoiceUseCase.PayInvoice(c.Request.Context(), invoiceId, payInvoiceInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoiceData)
+}
+
+func (u *InvoiceController) PayInvoices(c *gin.Context) {
+	var payInvoicesInputDTO invoice_usecase.PayInvoicesInputDTO
+
+	if err := c.ShouldBindJSON(&payInvoicesInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	paidInvoices, err := u.invoiceUseCase.PayInvoices(c.Request.Context(), payInvoicesInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, paidInvoices)
+}
+
+func (u *InvoiceController) DeleteInvoicePayment(c *gin.Context) {
+	invoiceId := c.Param("id")
+	paymentId := c.Param("paymentId")
+
+	for _, field := range []string{"id", "paymentId"} {
+		if err := uuid.Validate(c.Param(field)); err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   field,
+				Message: "Invalid UUID value",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+	}
+
+	invoiceData, err := u.invoiceUseCase.DeleteInvoicePayment(c.Request.Context(), invoiceId, paymentId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoiceData)
+}
+
+func (u *InvoiceController) FindPaymentDiscrepancies(c *gin.Context) {
+	billId := c.Query("billId")
+
+	discrepancies, err := u.invoiceUseCase.FindPaymentDiscrepancies(c.Request.Context(), billId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, discrepancies)
+}
+
+func (u *InvoiceController) FindUnconfirmedPayments(c *gin.Context) {
+	billId := c.Query("billId")
+
+	invoices, err := u.invoiceUseCase.FindUnconfirmedPayments(c.Request.Context(), billId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
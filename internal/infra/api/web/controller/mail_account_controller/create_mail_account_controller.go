@@ -0,0 +1,41 @@
+package mail_account_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/mail_account_usecase"
+)
+
+type MailAccountController struct {
+	mailAccountUseCase mail_account_usecase.MailAccountUseCaseInterface
+}
+
+func NewMailAccountController(mailAccountUseCase mail_account_usecase.MailAccountUseCaseInterface) *MailAccountController {
+	return &MailAccountController{
+		mailAccountUseCase: mailAccountUseCase,
+	}
+}
+
+func (u *MailAccountController) CreateMailAccount(c *gin.Context) {
+	var mailAccountInputDTO mail_account_usecase.MailAccountInputDTO
+
+	if err := c.ShouldBindJSON(&mailAccountInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.mailAccountUseCase.CreateMailAccount(c.Request.Context(), mailAccountInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
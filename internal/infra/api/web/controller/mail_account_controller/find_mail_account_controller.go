@@ -0,0 +1,54 @@
+package mail_account_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+)
+
+func (u *MailAccountController) FindMailAccountById(c *gin.Context) {
+	mailAccountId := c.Param("id")
+
+	if err := uuid.Validate(mailAccountId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	mailAccountData, err := u.mailAccountUseCase.FindMailAccountById(c.Request.Context(), mailAccountId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, mailAccountData)
+}
+
+func (u *MailAccountController) FindMailAccounts(c *gin.Context) {
+	status := c.Query("status")
+	userId := c.Query("userId")
+
+	mailAccountStatus, err := mail_account_entity.GetMailAccountStatusByName(status)
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate mailAccount status param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	mailAccounts, err := u.mailAccountUseCase.FindMailAccounts(c.Request.Context(), mailAccountStatus, userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, mailAccounts)
+}
@@ -0,0 +1,32 @@
+package reconciliation_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/reconciliation_usecase"
+)
+
+func (u *ReconciliationController) ReconcilePaymentEmails(c *gin.Context) {
+	var input reconciliation_usecase.ReconcilePaymentEmailsInputDTO
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			restErr := validation.ValidateErr(err)
+
+			c.JSON(restErr.Code, restErr)
+			return
+		}
+	}
+
+	result, err := u.reconciliationUseCase.ReconcilePaymentEmails(c.Request.Context(), input)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,74 @@
+package reconciliation_controller
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/statement_parser"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/reconciliation_usecase"
+)
+
+const (
+	MAX_STATEMENT_SIZE = 5 << 20 // 5 MiB
+)
+
+type ReconciliationController struct {
+	reconciliationUseCase reconciliation_usecase.ReconciliationUseCaseInterface
+}
+
+func NewReconciliationController(reconciliationUseCase reconciliation_usecase.ReconciliationUseCaseInterface) *ReconciliationController {
+	return &ReconciliationController{
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+func (u *ReconciliationController) ReconcileOfxStatement(c *gin.Context) {
+	u.reconcileStatement(c, statement_parser.OFX)
+}
+
+func (u *ReconciliationController) ReconcileCsvStatement(c *gin.Context) {
+	u.reconcileStatement(c, statement_parser.CSV)
+}
+
+// reconcileStatement accepts the statement either as the "file" field of a multipart form
+// or as the raw request body.
+func (u *ReconciliationController) reconcileStatement(c *gin.Context, format statement_parser.StatementFormat) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MAX_STATEMENT_SIZE)
+
+	var statement io.Reader = c.Request.Body
+
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "file",
+				Message: "Statement file is required",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Error trying to read uploaded file")
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+		defer file.Close()
+
+		statement = file
+	}
+
+	result, err := u.reconciliationUseCase.ReconcileStatement(c.Request.Context(), format, statement)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
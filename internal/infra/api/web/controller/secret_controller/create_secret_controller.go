@@ -0,0 +1,53 @@
+package secret_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/secret_usecase"
+)
+
+type SecretController struct {
+	secretUseCase secret_usecase.SecretUseCaseInterface
+}
+
+func NewSecretController(secretUseCase secret_usecase.SecretUseCaseInterface) *SecretController {
+	return &SecretController{
+		secretUseCase: secretUseCase,
+	}
+}
+
+func (u *SecretController) CreateSecret(c *gin.Context) {
+	var secretInputDTO secret_usecase.SecretInputDTO
+
+	if err := c.ShouldBindJSON(&secretInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := u.secretUseCase.CreateSecret(c.Request.Context(), secretInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (u *SecretController) RotateSecrets(c *gin.Context) {
+	output, err := u.secretUseCase.RotateSecrets(c.Request.Context())
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
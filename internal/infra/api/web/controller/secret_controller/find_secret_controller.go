@@ -0,0 +1,45 @@
+package secret_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+)
+
+func (u *SecretController) FindSecretById(c *gin.Context) {
+	secretId := c.Param("id")
+
+	if err := uuid.Validate(secretId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	secretData, err := u.secretUseCase.FindSecretById(c.Request.Context(), secretId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, secretData)
+}
+
+func (u *SecretController) FindSecrets(c *gin.Context) {
+	ownerId := c.Query("ownerId")
+
+	secrets, err := u.secretUseCase.FindSecrets(c.Request.Context(), ownerId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, secrets)
+}
@@ -0,0 +1,47 @@
+package secret_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/secret_usecase"
+)
+
+func (u *SecretController) UpdateSecret(c *gin.Context) {
+	secretId := c.Param("id")
+
+	var secretInputDTO secret_usecase.UpdateSecretInputDTO
+
+	if err := c.ShouldBindJSON(&secretInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	err := u.secretUseCase.UpdateSecret(c.Request.Context(), secretId, secretInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (u *SecretController) DeleteSecret(c *gin.Context) {
+	secretId := c.Param("id")
+
+	err := u.secretUseCase.DeleteSecret(c.Request.Context(), secretId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
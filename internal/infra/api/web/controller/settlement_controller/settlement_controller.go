@@ -0,0 +1,30 @@
+package settlement_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/settlement_usecase"
+)
+
+type SettlementController struct {
+	settlementUseCase settlement_usecase.SettlementUseCaseInterface
+}
+
+func NewSettlementController(settlementUseCase settlement_usecase.SettlementUseCaseInterface) *SettlementController {
+	return &SettlementController{
+		settlementUseCase: settlementUseCase,
+	}
+}
+
+func (u *SettlementController) FindSettlement(c *gin.Context) {
+	settlement, err := u.settlementUseCase.FindSettlement(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
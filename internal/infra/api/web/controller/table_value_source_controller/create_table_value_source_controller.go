@@ -1,7 +1,6 @@
 package table_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,7 +29,7 @@ func (u *TableValueSourceController) CreateTableValueSource(c *gin.Context) {
 		return
 	}
 
-	err := u.TableValueSourceUseCase.CreateTableValueSource(context.Background(), tableValueSourceInputDTO)
+	err := u.TableValueSourceUseCase.CreateTableValueSource(c.Request.Context(), tableValueSourceInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -1,7 +1,6 @@
 package table_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +22,7 @@ func (u *TableValueSourceController) FindTableValueSourceById(c *gin.Context) {
 		return
 	}
 
-	tableValueSourceData, err := u.TableValueSourceUseCase.FindTableValueSourceById(context.Background(), tableValueSourceId)
+	tableValueSourceData, err := u.TableValueSourceUseCase.FindTableValueSourceById(c.Request.Context(), tableValueSourceId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -44,7 +43,7 @@ func (u *TableValueSourceController) FindTableValueSources(c *gin.Context) {
 		return
 	}
 
-	auctions, err := u.TableValueSourceUseCase.FindTableValueSources(context.Background(),
+	auctions, err := u.TableValueSourceUseCase.FindTableValueSources(c.Request.Context(),
 		tableValueSourceStatus, name)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
@@ -1,7 +1,6 @@
 package table_value_source_controller
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +21,7 @@ func (u *TableValueSourceController) UpdateTableValueSource(c *gin.Context) {
 		return
 	}
 
-	err := u.TableValueSourceUseCase.UpdateTableValueSource(context.Background(), tableValueSourceId, tableValueSourceInputDTO)
+	err := u.TableValueSourceUseCase.UpdateTableValueSource(c.Request.Context(), tableValueSourceId, tableValueSourceInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -0,0 +1,44 @@
+package user_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/middleware"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/api/web/validation"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/user_usecase"
+)
+
+func (u *UserController) UpdateUserPassword(c *gin.Context) {
+	userId := c.Param("id")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var passwordInputDTO user_usecase.UserPasswordInputDTO
+
+	if err := c.ShouldBindJSON(&passwordInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	if err := u.userUseCase.UpdateUserPassword(c.Request.Context(), c.GetString(middleware.UserIdKey), userId, passwordInputDTO); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
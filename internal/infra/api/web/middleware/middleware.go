@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/rest_err"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/auth_usecase"
+	"github.com/regismartiny/lembrador-contas-go/internal/usecase/household_usecase"
+)
+
+// UserIdKey is the key of the calling user in the gin context.
+const UserIdKey = "userId"
+
+// Authenticated identifies the user making the request by the JWT access token of the
+// Authorization header ("Bearer <token>").
+func Authenticated(authUseCase auth_usecase.AuthUseCaseInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || accessToken == "" {
+			errRest := rest_err.NewUnauthorizedError("missing access token")
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		userId, err := authUseCase.Authenticate(accessToken)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		c.Set(UserIdKey, userId)
+		c.Next()
+	}
+}
+
+// HouseholdScope restricts the request to the household informed in the X-Household-Id header,
+// or to the only household of the caller. Viewers can only read.
+func HouseholdScope(householdUseCase household_usecase.HouseholdUseCaseInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		householdId := c.GetHeader("X-Household-Id")
+
+		if householdId != "" {
+			if err := uuid.Validate(householdId); err != nil {
+				errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+					Field:   "X-Household-Id",
+					Message: "Invalid UUID value",
+				})
+
+				c.AbortWithStatusJSON(errRest.Code, errRest)
+				return
+			}
+		}
+
+		scope, err := householdUseCase.ResolveScope(c.Request.Context(), c.GetString(UserIdKey), householdId)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		if !scope.Role.CanEdit() && c.Request.Method != http.MethodGet {
+			errRest := rest_err.NewForbiddenError("viewers cannot change the household records")
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		c.Request = c.Request.WithContext(household_entity.WithScope(c.Request.Context(), scope))
+		c.Next()
+	}
+}
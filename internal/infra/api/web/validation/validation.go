@@ -3,6 +3,8 @@ package validation
 import (
 	"encoding/json"
 	"errors"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
@@ -23,6 +25,15 @@ func init() {
 		enTransl := ut.New(en, en)
 		transl, _ = enTransl.GetTranslator("en")
 		validator_en.RegisterDefaultTranslations(value, transl)
+
+		// causes name the fields as in the JSON body, like the ones returned by the use cases
+		value.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
 	}
 }
 
@@ -0,0 +1,161 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/attachment_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AttachmentEntityMongo struct {
+	Id          string                           `bson:"_id"`
+	HouseholdId string                           `bson:"household_id,omitempty"`
+	InvoiceId   string                           `bson:"invoice_id"`
+	Kind        attachment_entity.AttachmentKind `bson:"kind"`
+	FileName    string                           `bson:"file_name"`
+	ContentType string                           `bson:"content_type"`
+	Size        int64                            `bson:"size"`
+	Checksum    string                           `bson:"checksum"`
+	StorageKey  string                           `bson:"storage_key"`
+	CreatedAt   int64                            `bson:"created_at"`
+}
+
+type AttachmentRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAttachmentRepository(ctx context.Context, database *mongo.Database) *AttachmentRepository {
+	coll := database.Collection("attachments")
+
+	createAttachmentInvoiceIndex(ctx, coll)
+
+	return &AttachmentRepository{
+		Collection: coll,
+	}
+}
+
+func createAttachmentInvoiceIndex(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "invoice_id", Value: 1}},
+	})
+	if err != nil {
+		logger.Error("Error creating attachment invoice index", err)
+	}
+}
+
+func (ur *AttachmentRepository) CreateAttachment(
+	ctx context.Context,
+	attachmentEntity *attachment_entity.Attachment) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	AttachmentEntityMongo := toAttachmentEntityMongo(attachmentEntity)
+	AttachmentEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.InsertOne(ctx, AttachmentEntityMongo); err != nil {
+		logger.Error("Error trying to insert attachment", err)
+		return internal_error.NewInternalServerError("Error trying to insert attachment")
+	}
+
+	return nil
+}
+
+func (ur *AttachmentRepository) FindAttachmentById(
+	ctx context.Context, attachmentId string) (*attachment_entity.Attachment, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": attachmentId})
+
+	var attachmentEntityMongo AttachmentEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&attachmentEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Attachment not found with this id = %s", attachmentId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Attachment not found with this id = %s", attachmentId))
+		}
+
+		logger.Error("Error trying to find attachment by attachmentId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find attachment by attachmentId")
+	}
+
+	return toAttachmentEntity(attachmentEntityMongo), nil
+}
+
+func (repo *AttachmentRepository) FindAttachments(
+	ctx context.Context,
+	invoiceId string) ([]*attachment_entity.Attachment, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if invoiceId != "" {
+		filter["invoice_id"] = invoiceId
+	}
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding attachments", err)
+		return nil, internal_error.NewInternalServerError("Error finding attachments")
+	}
+	defer cursor.Close(ctx)
+
+	var attachmentsMongo []AttachmentEntityMongo
+	if err := cursor.All(ctx, &attachmentsMongo); err != nil {
+		logger.Error("Error decoding attachments", err)
+		return nil, internal_error.NewInternalServerError("Error decoding attachments")
+	}
+
+	attachmentsEntity := make([]*attachment_entity.Attachment, len(attachmentsMongo))
+	for i, attachment := range attachmentsMongo {
+		attachmentsEntity[i] = toAttachmentEntity(attachment)
+	}
+
+	return attachmentsEntity, nil
+}
+
+func (repo *AttachmentRepository) DeleteAttachment(
+	ctx context.Context, attachmentId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": attachmentId})
+
+	if _, err := repo.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error deleting attachment", err)
+		return internal_error.NewInternalServerError("Error deleting attachment")
+	}
+
+	return nil
+}
+
+func toAttachmentEntityMongo(attachmentEntity *attachment_entity.Attachment) *AttachmentEntityMongo {
+	return &AttachmentEntityMongo{
+		Id:          attachmentEntity.Id,
+		InvoiceId:   attachmentEntity.InvoiceId,
+		Kind:        attachmentEntity.Kind,
+		FileName:    attachmentEntity.FileName,
+		ContentType: attachmentEntity.ContentType,
+		Size:        attachmentEntity.Size,
+		Checksum:    attachmentEntity.Checksum,
+		StorageKey:  attachmentEntity.StorageKey,
+		CreatedAt:   attachmentEntity.CreatedAt.Unix(),
+	}
+}
+
+func toAttachmentEntity(attachmentEntityMongo AttachmentEntityMongo) *attachment_entity.Attachment {
+	return &attachment_entity.Attachment{
+		Id:          attachmentEntityMongo.Id,
+		InvoiceId:   attachmentEntityMongo.InvoiceId,
+		Kind:        attachmentEntityMongo.Kind,
+		FileName:    attachmentEntityMongo.FileName,
+		ContentType: attachmentEntityMongo.ContentType,
+		Size:        attachmentEntityMongo.Size,
+		Checksum:    attachmentEntityMongo.Checksum,
+		StorageKey:  attachmentEntityMongo.StorageKey,
+		CreatedAt:   time.Unix(attachmentEntityMongo.CreatedAt, 0),
+	}
+}
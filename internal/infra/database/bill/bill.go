@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/migration"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,17 +21,64 @@ import (
 
 type BillEntityMongo struct {
 	Id              string                      `bson:"_id"`
+	HouseholdId     string                      `bson:"household_id,omitempty"`
 	UserId          string                      `bson:"user_id"`
 	Name            string                      `bson:"name"`
 	Company         string                      `bson:"company"`
 	ValueSourceType bill_entity.ValueSourceType `bson:"value_source_type"`
 	ValueSourceId   string                      `bson:"value_source_id"`
+	CategoryId      string                      `bson:"category_id"`
+	Tags            []string                    `bson:"tags"`
 	DueDay          uint8                       `bson:"due_day"`
+	Recurrence      RecurrenceMongo             `bson:"recurrence"`
+	DueDateRule     DueDateRuleMongo            `bson:"due_date_rule"`
+	LatePaymentRule LatePaymentRuleMongo        `bson:"late_payment_rule"`
+	PaymentMode     bill_entity.PaymentMode     `bson:"payment_mode"`
+	Split           SplitRuleMongo              `bson:"split"`
+	Suspensions     []SuspensionMongo           `bson:"suspensions,omitempty"`
 	Status          bill_entity.BillStatus      `bson:"status"`
 	CreatedAt       int64                       `bson:"created_at"`
 	UpdatedAt       int64                       `bson:"updated_at"`
 }
 
+type RecurrenceMongo struct {
+	Type       bill_entity.RecurrenceType `bson:"type"`
+	Interval   uint8                      `bson:"interval,omitempty"`
+	Month      time.Month                 `bson:"month,omitempty"`
+	Months     []time.Month               `bson:"months,omitempty"`
+	StartMonth string                     `bson:"start_month,omitempty"`
+}
+
+type DueDateRuleMongo struct {
+	Adjustment bill_entity.DueDateAdjustment `bson:"adjustment"`
+	State      string                        `bson:"state,omitempty"`
+	City       string                        `bson:"city,omitempty"`
+}
+
+type LatePaymentRuleMongo struct {
+	FinePercentage            float64 `bson:"fine_percentage"`
+	MonthlyInterestPercentage float64 `bson:"monthly_interest_percentage"`
+}
+
+type SplitRuleMongo struct {
+	Type   bill_entity.SplitType `bson:"type,omitempty"`
+	Shares []ShareMongo          `bson:"shares,omitempty"`
+}
+
+type ShareMongo struct {
+	UserId     string      `bson:"user_id"`
+	Percentage float64     `bson:"percentage,omitempty"`
+	Amount     money.Money `bson:"amount,omitempty"`
+}
+
+type SuspensionMongo struct {
+	Id         string `bson:"id"`
+	FromPeriod string `bson:"from_period"`
+	ToPeriod   string `bson:"to_period"`
+	Reason     string `bson:"reason,omitempty"`
+	CreatedAt  int64  `bson:"created_at"`
+}
+
 type BillRepository struct {
 	Collection *mongo.Collection
 }
@@ -35,16 +86,72 @@ type BillRepository struct {
 func NewBillRepository(ctx context.Context, database *mongo.Database) *BillRepository {
 	coll := database.Collection("bills")
 
+	migrateBillRecurrences(ctx, coll)
+	migrateBillPaymentModes(ctx, coll)
+	migrateBillDueDateRules(ctx, coll)
 	createBillNameUniqueIndex(ctx, coll)
+	createBillCategoryIndex(ctx, coll)
 
 	return &BillRepository{
 		Collection: coll,
 	}
 }
 
+// migrateBillRecurrences makes the bills saved before recurrences existed monthly.
+func migrateBillRecurrences(ctx context.Context, coll *mongo.Collection) {
+	result, err := coll.UpdateMany(ctx,
+		bson.M{"recurrence": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"recurrence": RecurrenceMongo{Type: bill_entity.Monthly}}})
+	if err != nil {
+		logger.Error("Error trying to set the recurrence of bills", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info(fmt.Sprintf("Bills set as monthly: %d", result.ModifiedCount))
+	}
+}
+
+// migrateBillPaymentModes makes the bills saved before payment modes existed paid manually.
+func migrateBillPaymentModes(ctx context.Context, coll *mongo.Collection) {
+	result, err := coll.UpdateMany(ctx,
+		bson.M{"payment_mode": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"payment_mode": bill_entity.Manual}})
+	if err != nil {
+		logger.Error("Error trying to set the payment mode of bills", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info(fmt.Sprintf("Bills set as paid manually: %d", result.ModifiedCount))
+	}
+}
+
+// migrateBillDueDateRules makes the due dates of the bills saved before due date rules existed
+// not adjusted to business days.
+func migrateBillDueDateRules(ctx context.Context, coll *mongo.Collection) {
+	result, err := coll.UpdateMany(ctx,
+		bson.M{"due_date_rule.adjustment": bson.M{"$in": bson.A{nil, 0}}},
+		bson.M{"$set": bson.M{"due_date_rule.adjustment": bill_entity.NoAdjustment}})
+	if err != nil {
+		logger.Error("Error trying to set the due date rule of bills", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info(fmt.Sprintf("Bills set without due date adjustment: %d", result.ModifiedCount))
+	}
+}
+
+// createBillNameUniqueIndex makes the bill names unique within a household.
 func createBillNameUniqueIndex(ctx context.Context, coll *mongo.Collection) {
+	// the names used to be unique across all the bills
+	if err := migration.DropIndex(ctx, coll, "name_1"); err != nil {
+		logger.Error("Error dropping bill name index", err)
+	}
+
 	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.M{"name": 1},
+		Keys:    bson.D{{Key: "household_id", Value: 1}, {Key: "name", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	})
 	if err != nil {
@@ -52,18 +159,41 @@ func createBillNameUniqueIndex(ctx context.Context, coll *mongo.Collection) {
 	}
 }
 
+func createBillCategoryIndex(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"category_id": 1},
+	})
+	if err != nil {
+		logger.Error("Error creating bill category index", err)
+	}
+}
+
 func (ur *BillRepository) CreateBill(
 	ctx context.Context,
 	billEntity *bill_entity.Bill) *internal_error.InternalError {
 
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
 	BillEntityMongo := &BillEntityMongo{
 		Id:              billEntity.Id,
+		HouseholdId:     householdId,
 		UserId:          billEntity.UserId,
 		Name:            billEntity.Name,
 		Company:         billEntity.Company,
 		ValueSourceType: billEntity.ValueSourceType,
 		ValueSourceId:   billEntity.ValueSourceId,
+		CategoryId:      billEntity.CategoryId,
+		Tags:            billEntity.Tags,
 		DueDay:          billEntity.DueDay,
+		Recurrence:      RecurrenceMongo(billEntity.Recurrence),
+		DueDateRule:     DueDateRuleMongo(billEntity.DueDateRule),
+		LatePaymentRule: LatePaymentRuleMongo(billEntity.LatePaymentRule),
+		PaymentMode:     billEntity.PaymentMode,
+		Split:           toSplitRuleMongo(billEntity.Split),
+		Suspensions:     toSuspensionsMongo(billEntity.Suspensions),
 		Status:          billEntity.Status,
 		CreatedAt:       billEntity.CreatedAt.Unix(),
 		UpdatedAt:       billEntity.UpdatedAt.Unix(),
@@ -79,7 +209,7 @@ func (ur *BillRepository) CreateBill(
 
 func (ur *BillRepository) FindBillById(
 	ctx context.Context, billId string) (*bill_entity.Bill, *internal_error.InternalError) {
-	filter := bson.M{"_id": billId}
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billId})
 
 	var billEntityMongo BillEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&billEntityMongo)
@@ -101,7 +231,15 @@ func (ur *BillRepository) FindBillById(
 		Company:         billEntityMongo.Company,
 		ValueSourceType: billEntityMongo.ValueSourceType,
 		ValueSourceId:   billEntityMongo.ValueSourceId,
+		CategoryId:      billEntityMongo.CategoryId,
+		Tags:            billEntityMongo.Tags,
 		DueDay:          billEntityMongo.DueDay,
+		Recurrence:      bill_entity.Recurrence(billEntityMongo.Recurrence),
+		DueDateRule:     bill_entity.DueDateRule(billEntityMongo.DueDateRule),
+		LatePaymentRule: bill_entity.LatePaymentRule(billEntityMongo.LatePaymentRule),
+		PaymentMode:     billEntityMongo.PaymentMode,
+		Split:           toSplitRule(billEntityMongo.Split),
+		Suspensions:     toSuspensions(billEntityMongo.Suspensions),
 		Status:          billEntityMongo.Status,
 		CreatedAt:       time.Unix(billEntityMongo.CreatedAt, 0),
 		UpdatedAt:       time.Unix(billEntityMongo.UpdatedAt, 0),
@@ -112,25 +250,31 @@ func (ur *BillRepository) FindBillById(
 
 func (repo *BillRepository) FindBills(
 	ctx context.Context,
-	status bill_entity.BillStatus,
-	userId string,
-	name, company string) ([]*bill_entity.Bill, *internal_error.InternalError) {
-	filter := bson.M{}
+	billFilter bill_entity.BillFilter) ([]*bill_entity.Bill, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if billFilter.Status != 0 {
+		filter["status"] = billFilter.Status
+	}
+
+	if billFilter.UserId != "" {
+		filter["user_id"] = billFilter.UserId
+	}
 
-	if status != 0 {
-		filter["status"] = status
+	if billFilter.Name != "" {
+		filter["name"] = primitive.Regex{Pattern: billFilter.Name, Options: "i"}
 	}
 
-	if userId != "" {
-		filter["user_id"] = userId
+	if billFilter.Company != "" {
+		filter["company"] = primitive.Regex{Pattern: billFilter.Company, Options: "i"}
 	}
 
-	if name != "" {
-		filter["name"] = primitive.Regex{Pattern: name, Options: "i"}
+	if billFilter.CategoryIds != nil {
+		filter["category_id"] = bson.M{"$in": billFilter.CategoryIds}
 	}
 
-	if company != "" {
-		filter["company"] = primitive.Regex{Pattern: company, Options: "i"}
+	if billFilter.Tag != "" {
+		filter["tags"] = strings.ToLower(strings.TrimSpace(billFilter.Tag))
 	}
 
 	cursor, err := repo.Collection.Find(ctx, filter)
@@ -155,7 +299,15 @@ func (repo *BillRepository) FindBills(
 			Company:         bill.Company,
 			ValueSourceType: bill.ValueSourceType,
 			ValueSourceId:   bill.ValueSourceId,
+			CategoryId:      bill.CategoryId,
+			Tags:            bill.Tags,
 			DueDay:          bill.DueDay,
+			Recurrence:      bill_entity.Recurrence(bill.Recurrence),
+			DueDateRule:     bill_entity.DueDateRule(bill.DueDateRule),
+			LatePaymentRule: bill_entity.LatePaymentRule(bill.LatePaymentRule),
+			PaymentMode:     bill.PaymentMode,
+			Split:           toSplitRule(bill.Split),
+			Suspensions:     toSuspensions(bill.Suspensions),
 			Status:          bill.Status,
 			CreatedAt:       time.Unix(bill.CreatedAt, 0),
 			UpdatedAt:       time.Unix(bill.UpdatedAt, 0),
@@ -164,3 +316,163 @@ func (repo *BillRepository) FindBills(
 
 	return billsEntity, nil
 }
+
+func (ur *BillRepository) UpdateBill(
+	ctx context.Context,
+	billEntity *bill_entity.Bill) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billEntity.Id})
+
+	// suspensions are left out (and omitted from $set), since they are changed by
+	// AddBillSuspension and RemoveBillSuspension
+	BillEntityMongo := &BillEntityMongo{
+		Id:              billEntity.Id,
+		HouseholdId:     householdId,
+		UserId:          billEntity.UserId,
+		Name:            billEntity.Name,
+		Company:         billEntity.Company,
+		ValueSourceType: billEntity.ValueSourceType,
+		ValueSourceId:   billEntity.ValueSourceId,
+		CategoryId:      billEntity.CategoryId,
+		Tags:            billEntity.Tags,
+		DueDay:          billEntity.DueDay,
+		Recurrence:      RecurrenceMongo(billEntity.Recurrence),
+		DueDateRule:     DueDateRuleMongo(billEntity.DueDateRule),
+		LatePaymentRule: LatePaymentRuleMongo(billEntity.LatePaymentRule),
+		PaymentMode:     billEntity.PaymentMode,
+		Split:           toSplitRuleMongo(billEntity.Split),
+		Status:          billEntity.Status,
+		CreatedAt:       billEntity.CreatedAt.Unix(),
+		UpdatedAt:       billEntity.UpdatedAt.Unix(),
+	}
+
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": BillEntityMongo}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError(fmt.Sprintf("A bill named %s already exists", billEntity.Name))
+		}
+
+		logger.Error("Error trying to update bill", err)
+		return internal_error.NewInternalServerError("Error trying to update bill")
+	}
+
+	return nil
+}
+
+// AddBillSuspension adds the suspension unless the bill has another one overlapping it, checked
+// in the same operation. It returns a not found error when the bill has none or the suspension
+// overlaps.
+func (ur *BillRepository) AddBillSuspension(
+	ctx context.Context,
+	billId string,
+	suspension bill_entity.Suspension) *internal_error.InternalError {
+
+	filter := household.ScopeFilter(ctx, bson.M{
+		"_id": billId,
+		"suspensions": bson.M{"$not": bson.M{"$elemMatch": bson.M{
+			"from_period": bson.M{"$lte": suspension.ToPeriod},
+			"to_period":   bson.M{"$gte": suspension.FromPeriod},
+		}}},
+	})
+	update := bson.M{
+		"$push": bson.M{"suspensions": toSuspensionsMongo([]bill_entity.Suspension{suspension})[0]},
+		"$set":  bson.M{"updated_at": time.Now().Unix()},
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to add bill suspension", err)
+		return internal_error.NewInternalServerError("Error trying to add bill suspension")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Bill not found with this id = %s or already suspended in the periods", billId))
+	}
+
+	return nil
+}
+
+func (ur *BillRepository) RemoveBillSuspension(
+	ctx context.Context,
+	billId string,
+	suspensionId string) *internal_error.InternalError {
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billId, "suspensions.id": suspensionId})
+	update := bson.M{
+		"$pull": bson.M{"suspensions": bson.M{"id": suspensionId}},
+		"$set":  bson.M{"updated_at": time.Now().Unix()},
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to remove bill suspension", err)
+		return internal_error.NewInternalServerError("Error trying to remove bill suspension")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError("Suspension not found with this id = " + suspensionId)
+	}
+
+	return nil
+}
+
+func (ur *BillRepository) DeleteBill(
+	ctx context.Context, billId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billId})
+
+	if _, err := ur.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete bill", err)
+		return internal_error.NewInternalServerError("Error trying to delete bill")
+	}
+
+	return nil
+}
+
+func toSplitRuleMongo(split bill_entity.SplitRule) SplitRuleMongo {
+	splitMongo := SplitRuleMongo{Type: split.Type}
+	for _, share := range split.Shares {
+		splitMongo.Shares = append(splitMongo.Shares, ShareMongo(share))
+	}
+	return splitMongo
+}
+
+func toSplitRule(splitMongo SplitRuleMongo) bill_entity.SplitRule {
+	split := bill_entity.SplitRule{Type: splitMongo.Type}
+	for _, share := range splitMongo.Shares {
+		split.Shares = append(split.Shares, bill_entity.Share(share))
+	}
+	return split
+}
+
+func toSuspensionsMongo(suspensions []bill_entity.Suspension) []SuspensionMongo {
+	suspensionsMongo := make([]SuspensionMongo, len(suspensions))
+	for i, suspension := range suspensions {
+		suspensionsMongo[i] = SuspensionMongo{
+			Id:         suspension.Id,
+			FromPeriod: suspension.FromPeriod,
+			ToPeriod:   suspension.ToPeriod,
+			Reason:     suspension.Reason,
+			CreatedAt:  suspension.CreatedAt.Unix(),
+		}
+	}
+	return suspensionsMongo
+}
+
+func toSuspensions(suspensionsMongo []SuspensionMongo) []bill_entity.Suspension {
+	suspensions := make([]bill_entity.Suspension, len(suspensionsMongo))
+	for i, suspension := range suspensionsMongo {
+		suspensions[i] = bill_entity.Suspension{
+			Id:         suspension.Id,
+			FromPeriod: suspension.FromPeriod,
+			ToPeriod:   suspension.ToPeriod,
+			Reason:     suspension.Reason,
+			CreatedAt:  time.Unix(suspension.CreatedAt, 0),
+		}
+	}
+	return suspensions
+}
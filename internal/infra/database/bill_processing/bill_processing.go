@@ -4,32 +4,55 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_processing_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type BillProcessingEntityMongo struct {
-	Id        string                                      `bson:"_id"`
-	Status    bill_processing_entity.BillProcessingStatus `bson:"status"`
-	CreatedAt int64                                       `bson:"created_at"`
-	UpdatedAt int64                                       `bson:"updated_at"`
+	Id           string                                      `bson:"_id"`
+	HouseholdId  string                                      `bson:"household_id,omitempty"`
+	Status       bill_processing_entity.BillProcessingStatus `bson:"status"`
+	Period       string                                      `bson:"period,omitempty"`
+	BillResults  []BillResultMongo                           `bson:"bill_results,omitempty"`
+	BudgetAlerts []BudgetAlertMongo                          `bson:"budget_alerts,omitempty"`
+	CreatedAt    int64                                       `bson:"created_at"`
+	UpdatedAt    int64                                       `bson:"updated_at"`
+}
+
+type BillResultMongo struct {
+	BillId      string                                  `bson:"bill_id"`
+	Status      bill_processing_entity.BillResultStatus `bson:"status"`
+	Error       string                                  `bson:"error,omitempty"`
+	Reason      string                                  `bson:"reason,omitempty"`
+	ProcessedAt int64                                   `bson:"processed_at"`
+}
+
+type BudgetAlertMongo struct {
+	CategoryId    string      `bson:"category_id"`
+	Spent         money.Money `bson:"spent"`
+	MonthlyBudget money.Money `bson:"monthly_budget"`
+	Message       string      `bson:"message"`
 }
 
 type BillProcessingRepository struct {
-	Collection *mongo.Collection
+	Collection     *mongo.Collection
+	LockCollection *mongo.Collection
 }
 
 func NewBillProcessingRepository(ctx context.Context, database *mongo.Database) *BillProcessingRepository {
 	coll := database.Collection("billProcessings")
 
 	return &BillProcessingRepository{
-		Collection: coll,
+		Collection:     coll,
+		LockCollection: database.Collection("processingLocks"),
 	}
 }
 
@@ -37,11 +60,18 @@ func (ur *BillProcessingRepository) CreateBillProcessing(
 	ctx context.Context,
 	billProcessingEntity *bill_processing_entity.BillProcessing) *internal_error.InternalError {
 
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
 	BillProcessingEntityMongo := &BillProcessingEntityMongo{
-		Id:        billProcessingEntity.Id,
-		Status:    billProcessingEntity.Status,
-		CreatedAt: billProcessingEntity.CreatedAt.Unix(),
-		UpdatedAt: billProcessingEntity.UpdatedAt.Unix(),
+		Id:          billProcessingEntity.Id,
+		HouseholdId: householdId,
+		Status:      billProcessingEntity.Status,
+		Period:      billProcessingEntity.Period,
+		CreatedAt:   billProcessingEntity.CreatedAt.Unix(),
+		UpdatedAt:   billProcessingEntity.UpdatedAt.Unix(),
 	}
 
 	if _, err := ur.Collection.InsertOne(ctx, BillProcessingEntityMongo); err != nil {
@@ -54,7 +84,7 @@ func (ur *BillProcessingRepository) CreateBillProcessing(
 
 func (ur *BillProcessingRepository) FindBillProcessingById(
 	ctx context.Context, billProcessingId string) (*bill_processing_entity.BillProcessing, *internal_error.InternalError) {
-	filter := bson.M{"_id": billProcessingId}
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billProcessingId})
 
 	var billProcessingEntityMongo BillProcessingEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&billProcessingEntityMongo)
@@ -69,20 +99,13 @@ func (ur *BillProcessingRepository) FindBillProcessingById(
 		return nil, internal_error.NewInternalServerError("Error trying to find billProcessing by billProcessingId")
 	}
 
-	billProcessingEntity := &bill_processing_entity.BillProcessing{
-		Id:        billProcessingEntityMongo.Id,
-		Status:    billProcessingEntityMongo.Status,
-		CreatedAt: time.Unix(billProcessingEntityMongo.CreatedAt, 0),
-		UpdatedAt: time.Unix(billProcessingEntityMongo.UpdatedAt, 0),
-	}
-
-	return billProcessingEntity, nil
+	return toBillProcessingEntity(&billProcessingEntityMongo), nil
 }
 
 func (repo *BillProcessingRepository) FindBillProcessings(
 	ctx context.Context,
 	status bill_processing_entity.BillProcessingStatus) ([]*bill_processing_entity.BillProcessing, *internal_error.InternalError) {
-	filter := bson.M{}
+	filter := household.ScopeFilter(ctx, bson.M{})
 
 	if status != 0 {
 		filter["status"] = status
@@ -102,13 +125,8 @@ func (repo *BillProcessingRepository) FindBillProcessings(
 	}
 
 	billProcessingsEntity := make([]*bill_processing_entity.BillProcessing, len(billProcessingsMongo))
-	for i, billProcessing := range billProcessingsMongo {
-		billProcessingsEntity[i] = &bill_processing_entity.BillProcessing{
-			Id:        billProcessing.Id,
-			Status:    billProcessing.Status,
-			CreatedAt: time.Unix(billProcessing.CreatedAt, 0),
-			UpdatedAt: time.Unix(billProcessing.UpdatedAt, 0),
-		}
+	for i := range billProcessingsMongo {
+		billProcessingsEntity[i] = toBillProcessingEntity(&billProcessingsMongo[i])
 	}
 
 	return billProcessingsEntity, nil
@@ -116,36 +134,133 @@ func (repo *BillProcessingRepository) FindBillProcessings(
 
 func (repo *BillProcessingRepository) GetProcessingsInProgressCount(
 	ctx context.Context) (int64, *internal_error.InternalError) {
-	filter := bson.M{}
+	filter := household.ScopeFilter(ctx, bson.M{})
 
 	filter["status"] = bill_processing_entity.Started
 
 	count, err := repo.Collection.CountDocuments(ctx, filter)
 	if err != nil {
-		log.Fatal(err)
+		logger.Error("Error trying to count billProcessings in progress", err)
+		return 0, internal_error.NewInternalServerError("Error trying to count billProcessings in progress")
 	}
 
 	return count, nil
 }
 
+func (repo *BillProcessingRepository) LockProcessings(
+	ctx context.Context) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": householdId}
+	update := bson.M{"$set": bson.M{"locked_at": time.Now().UnixNano()}}
+
+	if _, err := repo.LockCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to lock billProcessings", err)
+		return internal_error.NewInternalServerError("Error trying to lock billProcessings")
+	}
+
+	return nil
+}
+
 func (repo *BillProcessingRepository) UpdateBillProcessing(
 	ctx context.Context,
 	billProcessingEntity *bill_processing_entity.BillProcessing) *internal_error.InternalError {
 
-	filter := bson.M{"_id": billProcessingEntity.Id}
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billProcessingEntity.Id})
 
+	// bill results are left out (and omitted from $set), since they are added by AddBillResult
 	BillProcessingEntityMongo := &BillProcessingEntityMongo{
-		Id:        billProcessingEntity.Id,
-		Status:    billProcessingEntity.Status,
-		CreatedAt: billProcessingEntity.CreatedAt.Unix(),
-		UpdatedAt: billProcessingEntity.UpdatedAt.Unix(),
+		Id:          billProcessingEntity.Id,
+		HouseholdId: householdId,
+		Status:      billProcessingEntity.Status,
+		Period:      billProcessingEntity.Period,
+		CreatedAt:   billProcessingEntity.CreatedAt.Unix(),
+		UpdatedAt:   billProcessingEntity.UpdatedAt.Unix(),
+	}
+	for _, budgetAlert := range billProcessingEntity.BudgetAlerts {
+		BillProcessingEntityMongo.BudgetAlerts = append(BillProcessingEntityMongo.BudgetAlerts, BudgetAlertMongo{
+			CategoryId:    budgetAlert.CategoryId,
+			Spent:         budgetAlert.Spent,
+			MonthlyBudget: budgetAlert.MonthlyBudget,
+			Message:       budgetAlert.Message,
+		})
 	}
 
-	_, err := repo.Collection.UpdateOne(ctx, filter, bson.M{"$set": BillProcessingEntityMongo})
-	if err != nil {
+	if _, err := repo.Collection.UpdateOne(ctx, filter, bson.M{"$set": BillProcessingEntityMongo}); err != nil {
 		logger.Error("Error trying to update billProcessing", err)
 		return internal_error.NewInternalServerError("Error trying to update billProcessing")
 	}
 
 	return nil
 }
+
+func (repo *BillProcessingRepository) AddBillResult(
+	ctx context.Context,
+	billProcessingId string,
+	billResult *bill_processing_entity.BillResult) *internal_error.InternalError {
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": billProcessingId})
+
+	update := bson.M{
+		"$push": bson.M{"bill_results": toBillResultMongo(billResult)},
+		"$set":  bson.M{"updated_at": time.Now().Unix()},
+	}
+
+	if _, err := repo.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error trying to add billProcessing bill result", err)
+		return internal_error.NewInternalServerError("Error trying to add billProcessing bill result")
+	}
+
+	return nil
+}
+
+func toBillResultMongo(billResult *bill_processing_entity.BillResult) BillResultMongo {
+	return BillResultMongo{
+		BillId:      billResult.BillId,
+		Status:      billResult.Status,
+		Error:       billResult.Error,
+		Reason:      billResult.Reason,
+		ProcessedAt: billResult.ProcessedAt.Unix(),
+	}
+}
+
+func toBillProcessingEntity(billProcessingEntityMongo *BillProcessingEntityMongo) *bill_processing_entity.BillProcessing {
+	billProcessingEntity := &bill_processing_entity.BillProcessing{
+		Id:          billProcessingEntityMongo.Id,
+		Status:      billProcessingEntityMongo.Status,
+		Period:      billProcessingEntityMongo.Period,
+		BillResults: make([]*bill_processing_entity.BillResult, len(billProcessingEntityMongo.BillResults)),
+		CreatedAt:   time.Unix(billProcessingEntityMongo.CreatedAt, 0),
+		UpdatedAt:   time.Unix(billProcessingEntityMongo.UpdatedAt, 0),
+	}
+
+	for i, billResult := range billProcessingEntityMongo.BillResults {
+		billProcessingEntity.BillResults[i] = &bill_processing_entity.BillResult{
+			BillId:      billResult.BillId,
+			Status:      billResult.Status,
+			Error:       billResult.Error,
+			Reason:      billResult.Reason,
+			ProcessedAt: time.Unix(billResult.ProcessedAt, 0),
+		}
+	}
+
+	for _, budgetAlert := range billProcessingEntityMongo.BudgetAlerts {
+		billProcessingEntity.BudgetAlerts = append(billProcessingEntity.BudgetAlerts, &bill_processing_entity.BudgetAlert{
+			CategoryId:    budgetAlert.CategoryId,
+			Spent:         budgetAlert.Spent,
+			MonthlyBudget: budgetAlert.MonthlyBudget,
+			Message:       budgetAlert.Message,
+		})
+	}
+
+	return billProcessingEntity
+}
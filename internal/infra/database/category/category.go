@@ -0,0 +1,187 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/migration"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryEntityMongo struct {
+	Id            string      `bson:"_id"`
+	HouseholdId   string      `bson:"household_id,omitempty"`
+	Name          string      `bson:"name"`
+	ParentId      string      `bson:"parent_id"`
+	MonthlyBudget money.Money `bson:"monthly_budget"`
+	CreatedAt     int64       `bson:"created_at"`
+	UpdatedAt     int64       `bson:"updated_at"`
+}
+
+type CategoryRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCategoryRepository(ctx context.Context, database *mongo.Database) *CategoryRepository {
+	coll := database.Collection("categories")
+
+	createCategoryNameUniqueIndex(ctx, coll)
+
+	return &CategoryRepository{
+		Collection: coll,
+	}
+}
+
+// createCategoryNameUniqueIndex allows the same name under different parents, e.g.
+// "Moradia > Seguro" and "Veículos > Seguro", and in different households.
+func createCategoryNameUniqueIndex(ctx context.Context, coll *mongo.Collection) {
+	// the index used to be shared by all the households
+	if err := migration.DropIndex(ctx, coll, "parent_id_1_name_1"); err != nil {
+		logger.Error("Error dropping category parent name index", err)
+	}
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "household_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("Error creating category name unique index", err)
+	}
+}
+
+func (ur *CategoryRepository) CreateCategory(
+	ctx context.Context,
+	categoryEntity *category_entity.Category) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	CategoryEntityMongo := toCategoryEntityMongo(categoryEntity)
+	CategoryEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.InsertOne(ctx, CategoryEntityMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError(fmt.Sprintf("A category named %s already exists", categoryEntity.Name))
+		}
+
+		logger.Error("Error trying to insert category", err)
+		return internal_error.NewInternalServerError("Error trying to insert category")
+	}
+
+	return nil
+}
+
+func (ur *CategoryRepository) UpdateCategory(
+	ctx context.Context,
+	categoryEntity *category_entity.Category) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": categoryEntity.Id})
+
+	CategoryEntityMongo := toCategoryEntityMongo(categoryEntity)
+	CategoryEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": CategoryEntityMongo}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError(fmt.Sprintf("A category named %s already exists", categoryEntity.Name))
+		}
+
+		logger.Error("Error trying to update category", err)
+		return internal_error.NewInternalServerError("Error trying to update category")
+	}
+
+	return nil
+}
+
+func (ur *CategoryRepository) FindCategoryById(
+	ctx context.Context, categoryId string) (*category_entity.Category, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": categoryId})
+
+	var categoryEntityMongo CategoryEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&categoryEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Category not found with this id = %s", categoryId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Category not found with this id = %s", categoryId))
+		}
+
+		logger.Error("Error trying to find category by categoryId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find category by categoryId")
+	}
+
+	return toCategoryEntity(categoryEntityMongo), nil
+}
+
+func (repo *CategoryRepository) FindCategories(
+	ctx context.Context) ([]*category_entity.Category, *internal_error.InternalError) {
+
+	cursor, err := repo.Collection.Find(ctx, household.ScopeFilter(ctx, bson.M{}), options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		logger.Error("Error finding categories", err)
+		return nil, internal_error.NewInternalServerError("Error finding categories")
+	}
+	defer cursor.Close(ctx)
+
+	var categoriesMongo []CategoryEntityMongo
+	if err := cursor.All(ctx, &categoriesMongo); err != nil {
+		logger.Error("Error decoding categories", err)
+		return nil, internal_error.NewInternalServerError("Error decoding categories")
+	}
+
+	categoriesEntity := make([]*category_entity.Category, len(categoriesMongo))
+	for i, category := range categoriesMongo {
+		categoriesEntity[i] = toCategoryEntity(category)
+	}
+
+	return categoriesEntity, nil
+}
+
+func (repo *CategoryRepository) DeleteCategory(
+	ctx context.Context, categoryId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": categoryId})
+
+	if _, err := repo.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete category", err)
+		return internal_error.NewInternalServerError("Error trying to delete category")
+	}
+
+	return nil
+}
+
+func toCategoryEntityMongo(categoryEntity *category_entity.Category) *CategoryEntityMongo {
+	return &CategoryEntityMongo{
+		Id:            categoryEntity.Id,
+		Name:          categoryEntity.Name,
+		ParentId:      categoryEntity.ParentId,
+		MonthlyBudget: categoryEntity.MonthlyBudget,
+		CreatedAt:     categoryEntity.CreatedAt.Unix(),
+		UpdatedAt:     categoryEntity.UpdatedAt.Unix(),
+	}
+}
+
+func toCategoryEntity(categoryEntityMongo CategoryEntityMongo) *category_entity.Category {
+	return &category_entity.Category{
+		Id:            categoryEntityMongo.Id,
+		Name:          categoryEntityMongo.Name,
+		ParentId:      categoryEntityMongo.ParentId,
+		MonthlyBudget: categoryEntityMongo.MonthlyBudget,
+		CreatedAt:     time.Unix(categoryEntityMongo.CreatedAt, 0),
+		UpdatedAt:     time.Unix(categoryEntityMongo.UpdatedAt, 0),
+	}
+}
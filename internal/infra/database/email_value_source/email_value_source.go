@@ -8,6 +8,7 @@ import (
 
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,8 +17,12 @@ import (
 
 type EmailValueSourceEntityMongo struct {
 	Id            string                                                  `bson:"_id"`
+	HouseholdId   string                                                  `bson:"household_id,omitempty"`
+	MailAccountId string                                                  `bson:"mail_account_id"`
 	Address       string                                                  `bson:"address"`
 	Subject       string                                                  `bson:"subject"`
+	Labels        []string                                                `bson:"labels"`
+	Exclude       []string                                                `bson:"exclude"`
 	DataExtractor email_value_source_entity.EmailValueSourceDataExtractor `bson:"data_extractor"`
 	CreatedAt     int64                                                   `bson:"created_at"`
 	UpdatedAt     int64                                                   `bson:"updated_at"`
@@ -39,10 +44,19 @@ func (ur *EmailValueSourceRepository) CreateEmailValueSource(
 	ctx context.Context,
 	emailValueSourceEntity *email_value_source_entity.EmailValueSource) *internal_error.InternalError {
 
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
 	EmailValueSourceEntityMongo := &EmailValueSourceEntityMongo{
 		Id:            emailValueSourceEntity.Id,
+		HouseholdId:   householdId,
+		MailAccountId: emailValueSourceEntity.MailAccountId,
 		Address:       emailValueSourceEntity.Address,
 		Subject:       emailValueSourceEntity.Subject,
+		Labels:        emailValueSourceEntity.Labels,
+		Exclude:       emailValueSourceEntity.Exclude,
 		DataExtractor: emailValueSourceEntity.DataExtractor,
 		CreatedAt:     emailValueSourceEntity.CreatedAt.Unix(),
 		UpdatedAt:     emailValueSourceEntity.UpdatedAt.Unix(),
@@ -60,19 +74,27 @@ func (ur *EmailValueSourceRepository) UpdateEmailValueSource(
 	ctx context.Context,
 	emailValueSourceEntity *email_value_source_entity.EmailValueSource) *internal_error.InternalError {
 
-	filter := bson.M{"_id": emailValueSourceEntity.Id}
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": emailValueSourceEntity.Id})
 
 	EmailValueSourceEntityMongo := &EmailValueSourceEntityMongo{
 		Id:            emailValueSourceEntity.Id,
+		HouseholdId:   householdId,
+		MailAccountId: emailValueSourceEntity.MailAccountId,
 		Address:       emailValueSourceEntity.Address,
 		Subject:       emailValueSourceEntity.Subject,
+		Labels:        emailValueSourceEntity.Labels,
+		Exclude:       emailValueSourceEntity.Exclude,
 		DataExtractor: emailValueSourceEntity.DataExtractor,
 		CreatedAt:     emailValueSourceEntity.CreatedAt.Unix(),
 		UpdatedAt:     emailValueSourceEntity.UpdatedAt.Unix(),
 	}
 
-	_, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": EmailValueSourceEntityMongo})
-	if err != nil {
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": EmailValueSourceEntityMongo}); err != nil {
 		logger.Error("Error trying to update emailValueSource", err)
 		return internal_error.NewInternalServerError("Error trying to update emailValueSource")
 	}
@@ -82,7 +104,7 @@ func (ur *EmailValueSourceRepository) UpdateEmailValueSource(
 
 func (ur *EmailValueSourceRepository) FindEmailValueSourceById(
 	ctx context.Context, emailValueSourceId string) (*email_value_source_entity.EmailValueSource, *internal_error.InternalError) {
-	filter := bson.M{"_id": emailValueSourceId}
+	filter := household.ScopeFilter(ctx, bson.M{"_id": emailValueSourceId})
 
 	var emailValueSourceEntityMongo EmailValueSourceEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&emailValueSourceEntityMongo)
@@ -99,8 +121,11 @@ func (ur *EmailValueSourceRepository) FindEmailValueSourceById(
 
 	emailValueSourceEntity := &email_value_source_entity.EmailValueSource{
 		Id:            emailValueSourceEntityMongo.Id,
+		MailAccountId: emailValueSourceEntityMongo.MailAccountId,
 		Address:       emailValueSourceEntityMongo.Address,
 		Subject:       emailValueSourceEntityMongo.Subject,
+		Labels:        emailValueSourceEntityMongo.Labels,
+		Exclude:       emailValueSourceEntityMongo.Exclude,
 		DataExtractor: emailValueSourceEntityMongo.DataExtractor,
 		CreatedAt:     time.Unix(emailValueSourceEntityMongo.CreatedAt, 0),
 		UpdatedAt:     time.Unix(emailValueSourceEntityMongo.UpdatedAt, 0),
@@ -112,7 +137,7 @@ func (ur *EmailValueSourceRepository) FindEmailValueSourceById(
 func (repo *EmailValueSourceRepository) FindEmailValueSources(
 	ctx context.Context,
 	address, subject string) ([]*email_value_source_entity.EmailValueSource, *internal_error.InternalError) {
-	filter := bson.M{}
+	filter := household.ScopeFilter(ctx, bson.M{})
 
 	if address != "" {
 		filter["address"] = primitive.Regex{Pattern: address, Options: "i"}
@@ -139,8 +164,11 @@ func (repo *EmailValueSourceRepository) FindEmailValueSources(
 	for i, emailValueSource := range emailValueSourcesMongo {
 		emailValueSourcesEntity[i] = &email_value_source_entity.EmailValueSource{
 			Id:            emailValueSource.Id,
+			MailAccountId: emailValueSource.MailAccountId,
 			Address:       emailValueSource.Address,
 			Subject:       emailValueSource.Subject,
+			Labels:        emailValueSource.Labels,
+			Exclude:       emailValueSource.Exclude,
 			DataExtractor: emailValueSource.DataExtractor,
 			CreatedAt:     time.Unix(emailValueSource.CreatedAt, 0),
 			UpdatedAt:     time.Unix(emailValueSource.UpdatedAt, 0),
@@ -149,3 +177,15 @@ func (repo *EmailValueSourceRepository) FindEmailValueSources(
 
 	return emailValueSourcesEntity, nil
 }
+
+func (ur *EmailValueSourceRepository) DeleteEmailValueSource(
+	ctx context.Context, emailValueSourceId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": emailValueSourceId})
+
+	if _, err := ur.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete emailValueSource", err)
+		return internal_error.NewInternalServerError("Error trying to delete emailValueSource")
+	}
+
+	return nil
+}
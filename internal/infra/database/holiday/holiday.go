@@ -0,0 +1,156 @@
+package holiday
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/holiday_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type HolidayEntityMongo struct {
+	Id          string `bson:"_id"`
+	HouseholdId string `bson:"household_id,omitempty"`
+	Name        string `bson:"name"`
+	Date        string `bson:"date"`
+	Yearly      bool   `bson:"yearly"`
+	State       string `bson:"state"`
+	City        string `bson:"city"`
+	CreatedAt   int64  `bson:"created_at"`
+}
+
+type HolidayRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewHolidayRepository(ctx context.Context, database *mongo.Database) *HolidayRepository {
+	coll := database.Collection("holidays")
+
+	createHolidayStateIndex(ctx, coll)
+
+	return &HolidayRepository{
+		Collection: coll,
+	}
+}
+
+func createHolidayStateIndex(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"state": 1},
+	})
+	if err != nil {
+		logger.Error("Error creating holiday state index", err)
+	}
+}
+
+func (ur *HolidayRepository) CreateHoliday(
+	ctx context.Context,
+	holidayEntity *holiday_entity.Holiday) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	HolidayEntityMongo := toHolidayEntityMongo(holidayEntity)
+	HolidayEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.InsertOne(ctx, HolidayEntityMongo); err != nil {
+		logger.Error("Error trying to insert holiday", err)
+		return internal_error.NewInternalServerError("Error trying to insert holiday")
+	}
+
+	return nil
+}
+
+func (ur *HolidayRepository) FindHolidayById(
+	ctx context.Context, holidayId string) (*holiday_entity.Holiday, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": holidayId})
+
+	var holidayEntityMongo HolidayEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&holidayEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Holiday not found with this id = %s", holidayId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Holiday not found with this id = %s", holidayId))
+		}
+
+		logger.Error("Error trying to find holiday by holidayId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find holiday by holidayId")
+	}
+
+	return toHolidayEntity(holidayEntityMongo), nil
+}
+
+func (repo *HolidayRepository) FindHolidays(
+	ctx context.Context,
+	state string) ([]*holiday_entity.Holiday, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if state != "" {
+		filter["state"] = bson.M{"$in": bson.A{"", strings.ToUpper(state)}}
+	}
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding holidays", err)
+		return nil, internal_error.NewInternalServerError("Error finding holidays")
+	}
+	defer cursor.Close(ctx)
+
+	var holidaysMongo []HolidayEntityMongo
+	if err := cursor.All(ctx, &holidaysMongo); err != nil {
+		logger.Error("Error decoding holidays", err)
+		return nil, internal_error.NewInternalServerError("Error decoding holidays")
+	}
+
+	holidaysEntity := make([]*holiday_entity.Holiday, len(holidaysMongo))
+	for i, holiday := range holidaysMongo {
+		holidaysEntity[i] = toHolidayEntity(holiday)
+	}
+
+	return holidaysEntity, nil
+}
+
+func (repo *HolidayRepository) DeleteHoliday(
+	ctx context.Context, holidayId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": holidayId})
+
+	if _, err := repo.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete holiday", err)
+		return internal_error.NewInternalServerError("Error trying to delete holiday")
+	}
+
+	return nil
+}
+
+func toHolidayEntityMongo(holidayEntity *holiday_entity.Holiday) *HolidayEntityMongo {
+	return &HolidayEntityMongo{
+		Id:        holidayEntity.Id,
+		Name:      holidayEntity.Name,
+		Date:      holidayEntity.Date,
+		Yearly:    holidayEntity.Yearly,
+		State:     holidayEntity.State,
+		City:      holidayEntity.City,
+		CreatedAt: holidayEntity.CreatedAt.Unix(),
+	}
+}
+
+func toHolidayEntity(holidayEntityMongo HolidayEntityMongo) *holiday_entity.Holiday {
+	return &holiday_entity.Holiday{
+		Id:        holidayEntityMongo.Id,
+		Name:      holidayEntityMongo.Name,
+		Date:      holidayEntityMongo.Date,
+		Yearly:    holidayEntityMongo.Yearly,
+		State:     holidayEntityMongo.State,
+		City:      holidayEntityMongo.City,
+		CreatedAt: time.Unix(holidayEntityMongo.CreatedAt, 0),
+	}
+}
@@ -0,0 +1,296 @@
+package household
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HouseholdEntityMongo struct {
+	Id        string        `bson:"_id"`
+	Name      string        `bson:"name"`
+	Members   []MemberMongo `bson:"members"`
+	CreatedAt int64         `bson:"created_at"`
+	UpdatedAt int64         `bson:"updated_at"`
+}
+
+type MemberMongo struct {
+	Id        string                        `bson:"id"`
+	UserId    string                        `bson:"user_id,omitempty"`
+	Email     string                        `bson:"email"`
+	Role      household_entity.Role         `bson:"role"`
+	Status    household_entity.MemberStatus `bson:"status"`
+	InvitedAt int64                         `bson:"invited_at"`
+	JoinedAt  int64                         `bson:"joined_at,omitempty"`
+}
+
+type HouseholdRepository struct {
+	Collection *mongo.Collection
+}
+
+// scopedCollections hold the records owned by a household.
+var scopedCollections = []string{"bills", "invoices", "tableValueSources", "emailValueSources", "categories",
+	"billProcessings", "attachments", "mailAccounts", "secrets", "oauthTokens", "holidays"}
+
+func NewHouseholdRepository(ctx context.Context, database *mongo.Database) *HouseholdRepository {
+	coll := database.Collection("households")
+
+	createHouseholdMemberIndexes(ctx, coll)
+	migrateRecordsToDefaultHousehold(ctx, database, coll)
+
+	return &HouseholdRepository{
+		Collection: coll,
+	}
+}
+
+func createHouseholdMemberIndexes(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"members.user_id": 1}},
+		{Keys: bson.M{"members.email": 1}},
+	})
+	if err != nil {
+		logger.Error("Error creating household member indexes", err)
+	}
+}
+
+// migrateRecordsToDefaultHousehold moves the records saved before households existed, which were
+// shared by everyone, to the first household, created with all the users as owners when there is
+// none yet. It runs on every start, so the collections scoped later are moved as well.
+func migrateRecordsToDefaultHousehold(ctx context.Context, database *mongo.Database, coll *mongo.Collection) {
+	householdId, err := findDefaultHousehold(ctx, coll)
+	if err != nil {
+		logger.Error("Error trying to find the default household", err)
+		return
+	}
+
+	if householdId == "" {
+		if householdId, err = createDefaultHousehold(ctx, database, coll); err != nil {
+			logger.Error("Error trying to create the default household", err)
+			return
+		}
+		if householdId == "" {
+			return
+		}
+	}
+
+	for _, name := range scopedCollections {
+		result, err := database.Collection(name).UpdateMany(ctx,
+			bson.M{"household_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"household_id": householdId}})
+		if err != nil {
+			logger.Error("Error trying to set the household of "+name, err)
+			continue
+		}
+
+		if result.ModifiedCount > 0 {
+			logger.Info(fmt.Sprintf("Records of %s moved to the default household: %d", name, result.ModifiedCount))
+		}
+	}
+}
+
+// findDefaultHousehold returns the oldest household, or an empty id when there is none.
+func findDefaultHousehold(ctx context.Context, coll *mongo.Collection) (string, error) {
+	var household HouseholdEntityMongo
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	if err := coll.FindOne(ctx, bson.M{}, opts).Decode(&household); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return household.Id, nil
+}
+
+// createDefaultHousehold creates a household with all the users as owners. No household is created
+// before the first user.
+func createDefaultHousehold(ctx context.Context, database *mongo.Database, coll *mongo.Collection) (string, error) {
+	cursor, err := database.Collection("users").Find(ctx, bson.M{})
+	if err != nil {
+		return "", err
+	}
+
+	var users []struct {
+		Id    string `bson:"_id"`
+		Email string `bson:"email"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return "", err
+	}
+
+	if len(users) == 0 {
+		return "", nil
+	}
+
+	now := time.Now().Unix()
+	household := HouseholdEntityMongo{
+		Id:        uuid.New().String(),
+		Name:      "Casa",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, user := range users {
+		household.Members = append(household.Members, MemberMongo{
+			Id:        uuid.New().String(),
+			UserId:    user.Id,
+			Email:     user.Email,
+			Role:      household_entity.Owner,
+			Status:    household_entity.Joined,
+			InvitedAt: now,
+			JoinedAt:  now,
+		})
+	}
+
+	if _, err := coll.InsertOne(ctx, household); err != nil {
+		return "", err
+	}
+
+	return household.Id, nil
+}
+
+func (repo *HouseholdRepository) CreateHousehold(
+	ctx context.Context,
+	householdEntity *household_entity.Household) *internal_error.InternalError {
+
+	if _, err := repo.Collection.InsertOne(ctx, toHouseholdEntityMongo(householdEntity)); err != nil {
+		logger.Error("Error trying to insert household", err)
+		return internal_error.NewInternalServerError("Error trying to insert household")
+	}
+
+	return nil
+}
+
+func (repo *HouseholdRepository) UpdateHousehold(
+	ctx context.Context,
+	householdEntity *household_entity.Household) *internal_error.InternalError {
+
+	filter := bson.M{"_id": householdEntity.Id}
+
+	if _, err := repo.Collection.UpdateOne(ctx, filter, bson.M{"$set": toHouseholdEntityMongo(householdEntity)}); err != nil {
+		logger.Error("Error trying to update household", err)
+		return internal_error.NewInternalServerError("Error trying to update household")
+	}
+
+	return nil
+}
+
+func (repo *HouseholdRepository) FindHouseholdById(
+	ctx context.Context, householdId string) (*household_entity.Household, *internal_error.InternalError) {
+	filter := bson.M{"_id": householdId}
+
+	var householdEntityMongo HouseholdEntityMongo
+	if err := repo.Collection.FindOne(ctx, filter).Decode(&householdEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Household not found with this id = %s", householdId))
+		}
+
+		logger.Error("Error trying to find household by householdId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find household by householdId")
+	}
+
+	return toHouseholdEntity(&householdEntityMongo), nil
+}
+
+func (repo *HouseholdRepository) FindHouseholds(
+	ctx context.Context) ([]*household_entity.Household, *internal_error.InternalError) {
+	return repo.findHouseholds(ctx, bson.M{})
+}
+
+func (repo *HouseholdRepository) FindHouseholdsByMember(
+	ctx context.Context,
+	userId string,
+	email string) ([]*household_entity.Household, *internal_error.InternalError) {
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"members.user_id": userId},
+		bson.M{"members.email": email},
+	}}
+
+	return repo.findHouseholds(ctx, filter)
+}
+
+func (repo *HouseholdRepository) findHouseholds(
+	ctx context.Context, filter bson.M) ([]*household_entity.Household, *internal_error.InternalError) {
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding households", err)
+		return nil, internal_error.NewInternalServerError("Error finding households")
+	}
+	defer cursor.Close(ctx)
+
+	var householdsMongo []HouseholdEntityMongo
+	if err := cursor.All(ctx, &householdsMongo); err != nil {
+		logger.Error("Error decoding households", err)
+		return nil, internal_error.NewInternalServerError("Error decoding households")
+	}
+
+	households := make([]*household_entity.Household, len(householdsMongo))
+	for i := range householdsMongo {
+		households[i] = toHouseholdEntity(&householdsMongo[i])
+	}
+
+	return households, nil
+}
+
+func toHouseholdEntityMongo(householdEntity *household_entity.Household) *HouseholdEntityMongo {
+	householdEntityMongo := &HouseholdEntityMongo{
+		Id:        householdEntity.Id,
+		Name:      householdEntity.Name,
+		Members:   make([]MemberMongo, len(householdEntity.Members)),
+		CreatedAt: householdEntity.CreatedAt.Unix(),
+		UpdatedAt: householdEntity.UpdatedAt.Unix(),
+	}
+
+	for i, member := range householdEntity.Members {
+		householdEntityMongo.Members[i] = MemberMongo{
+			Id:        member.Id,
+			UserId:    member.UserId,
+			Email:     member.Email,
+			Role:      member.Role,
+			Status:    member.Status,
+			InvitedAt: member.InvitedAt.Unix(),
+		}
+		if !member.JoinedAt.IsZero() {
+			householdEntityMongo.Members[i].JoinedAt = member.JoinedAt.Unix()
+		}
+	}
+
+	return householdEntityMongo
+}
+
+func toHouseholdEntity(householdEntityMongo *HouseholdEntityMongo) *household_entity.Household {
+	householdEntity := &household_entity.Household{
+		Id:        householdEntityMongo.Id,
+		Name:      householdEntityMongo.Name,
+		Members:   make([]household_entity.Member, len(householdEntityMongo.Members)),
+		CreatedAt: time.Unix(householdEntityMongo.CreatedAt, 0),
+		UpdatedAt: time.Unix(householdEntityMongo.UpdatedAt, 0),
+	}
+
+	for i, member := range householdEntityMongo.Members {
+		householdEntity.Members[i] = household_entity.Member{
+			Id:        member.Id,
+			UserId:    member.UserId,
+			Email:     member.Email,
+			Role:      member.Role,
+			Status:    member.Status,
+			InvitedAt: time.Unix(member.InvitedAt, 0),
+		}
+		if member.JoinedAt != 0 {
+			householdEntity.Members[i].JoinedAt = time.Unix(member.JoinedAt, 0)
+		}
+	}
+
+	return householdEntity
+}
@@ -0,0 +1,38 @@
+package household
+
+import (
+	"context"
+	"errors"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ScopeFilter restricts the filter to the records of the household of the request. Only contexts
+// marked with household_entity.WithoutScope (e.g. startup jobs) see every record; any other
+// context without a household matches nothing.
+func ScopeFilter(ctx context.Context, filter bson.M) bson.M {
+	if scope, found := household_entity.ScopeFromContext(ctx); found {
+		filter["household_id"] = scope.HouseholdId
+		return filter
+	}
+
+	if !household_entity.IsUnscoped(ctx) {
+		logger.Error("Query without household scope", errors.New("context not bound to a household"))
+		filter["household_id"] = bson.M{"$in": bson.A{}}
+	}
+	return filter
+}
+
+// ScopeId returns the household of the request, saved in the records it creates or replaces.
+// Records can't be written without a household, not even by unscoped contexts.
+func ScopeId(ctx context.Context) (string, *internal_error.InternalError) {
+	scope, found := household_entity.ScopeFromContext(ctx)
+	if !found {
+		logger.Error("Write without household scope", errors.New("context not bound to a household"))
+		return "", internal_error.NewInternalServerError("Operation not bound to a household")
+	}
+	return scope.HouseholdId, nil
+}
@@ -6,32 +6,296 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/migration"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InvoiceEntityMongo struct {
-	Id        string                       `bson:"_id"`
-	BillId    string                       `bson:"bill_id"`
-	DueDate   string                       `bson:"due_date"`
-	Amount    int64                        `bson:"amount"`
-	Status    invoice_entity.InvoiceStatus `bson:"status"`
-	CreatedAt int64                        `bson:"created_at"`
-	UpdatedAt int64                        `bson:"updated_at"`
+	Id            string                       `bson:"_id"`
+	HouseholdId   string                       `bson:"household_id,omitempty"`
+	BillId        string                       `bson:"bill_id"`
+	Period        string                       `bson:"period"`
+	DueDate       string                       `bson:"due_date"`
+	Amount        money.Money                  `bson:"amount"`
+	Status        invoice_entity.InvoiceStatus `bson:"status"`
+	EmailSource   *EmailSourceMongo            `bson:"email_source,omitempty"`
+	Barcode       string                       `bson:"barcode,omitempty"`
+	Shares        []ShareMongo                 `bson:"shares,omitempty"`
+	Payments      []PaymentMongo               `bson:"payments"`
+	AmountChanges []AmountChangeMongo          `bson:"amount_changes,omitempty"`
+	CreatedAt     int64                        `bson:"created_at"`
+	UpdatedAt     int64                        `bson:"updated_at"`
+}
+
+type ShareMongo struct {
+	UserId string      `bson:"user_id"`
+	Amount money.Money `bson:"amount"`
+}
+
+type AmountChangeMongo struct {
+	PreviousAmount money.Money `bson:"previous_amount"`
+	Amount         money.Money `bson:"amount"`
+	ChangedAt      int64       `bson:"changed_at"`
+}
+
+type EmailSourceMongo struct {
+	MessageId   string `bson:"message_id"`
+	ThreadId    string `bson:"thread_id"`
+	Sender      string `bson:"sender"`
+	ReceivedAt  int64  `bson:"received_at"`
+	ContentHash string `bson:"content_hash"`
+	DuplicateOf string `bson:"duplicate_of,omitempty"`
+}
+
+type PaymentMongo struct {
+	Id          string                       `bson:"id"`
+	PaymentDate string                       `bson:"payment_date"`
+	Amount      money.Money                  `bson:"amount"`
+	Method      invoice_entity.PaymentMethod `bson:"method"`
+	Notes       string                       `bson:"notes"`
+	Reference   string                       `bson:"reference,omitempty"`
+	EmailSource *EmailSourceMongo            `bson:"email_source,omitempty"`
+	Automatic   bool                         `bson:"automatic,omitempty"`
+	PaidBy      string                       `bson:"paid_by,omitempty"`
+	CreatedAt   int64                        `bson:"created_at"`
 }
 
 type InvoiceRepository struct {
 	Collection *mongo.Collection
 }
 
-func NewInvoiceRepository(ctx context.Context, database *mongo.Database) *InvoiceRepository {
+// NewInvoiceRepository fails when the invoices can not be keyed by bill and period, as processing
+// without the unique index could create duplicated invoices.
+func NewInvoiceRepository(ctx context.Context, database *mongo.Database) (*InvoiceRepository, error) {
 	coll := database.Collection("invoices")
 
+	migrateInvoicePeriods(ctx, coll)
+	migrateInvoicePayments(ctx, coll)
+	if err := removeDuplicatedInvoices(ctx, database, coll); err != nil {
+		return nil, err
+	}
+	if err := createInvoiceBillPeriodIndexes(ctx, coll); err != nil {
+		return nil, err
+	}
+	createInvoiceEmailSourceIndexes(ctx, coll)
+
 	return &InvoiceRepository{
 		Collection: coll,
+	}, nil
+}
+
+// migrateInvoicePeriods fills the period of the invoices created before it existed, inferring it
+// from the due date.
+func migrateInvoicePeriods(ctx context.Context, coll *mongo.Collection) {
+	filter := bson.M{"period": bson.M{"$in": bson.A{nil, ""}}}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"due_date": 1}))
+	if err != nil {
+		logger.Error("Error finding invoices without period", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var invoicesMongo []InvoiceEntityMongo
+	if err := cursor.All(ctx, &invoicesMongo); err != nil {
+		logger.Error("Error decoding invoices without period", err)
+		return
+	}
+
+	for _, invoiceMongo := range invoicesMongo {
+		period := invoice_entity.PeriodFromDueDate(invoiceMongo.DueDate)
+		if period == "" {
+			continue
+		}
+
+		if _, err := coll.UpdateByID(ctx, invoiceMongo.Id, bson.M{"$set": bson.M{"period": period}}); err != nil {
+			logger.Error("Error setting invoice period", err)
+			return
+		}
+	}
+
+	if len(invoicesMongo) > 0 {
+		logger.Info(fmt.Sprintf("Invoice periods migrated: %d", len(invoicesMongo)))
+	}
+}
+
+// migrateInvoicePayments moves the single payment the invoices used to have into their list of
+// payments, so the invoices paid before multiple payments existed keep their payment.
+func migrateInvoicePayments(ctx context.Context, coll *mongo.Collection) {
+	filter := bson.M{"payment": bson.M{"$exists": true}}
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"payment": 1, "payments": 1, "updated_at": 1}))
+	if err != nil {
+		logger.Error("Error finding invoices with a single payment", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var invoicesMongo []struct {
+		Id        string         `bson:"_id"`
+		Payment   *PaymentMongo  `bson:"payment"`
+		Payments  []PaymentMongo `bson:"payments"`
+		UpdatedAt int64          `bson:"updated_at"`
+	}
+	if err := cursor.All(ctx, &invoicesMongo); err != nil {
+		logger.Error("Error decoding invoices with a single payment", err)
+		return
+	}
+
+	for _, invoiceMongo := range invoicesMongo {
+		payments := invoiceMongo.Payments
+		if payments == nil {
+			payments = []PaymentMongo{}
+		}
+
+		if payment := invoiceMongo.Payment; payment != nil {
+			payment.Id = uuid.New().String()
+			payment.CreatedAt = invoiceMongo.UpdatedAt
+			payments = append(payments, *payment)
+		}
+
+		update := bson.M{
+			"$set":   bson.M{"payments": payments},
+			"$unset": bson.M{"payment": ""},
+		}
+		if _, err := coll.UpdateByID(ctx, invoiceMongo.Id, update); err != nil {
+			logger.Error("Error moving invoice payment", err)
+			return
+		}
+	}
+
+	if len(invoicesMongo) > 0 {
+		logger.Info(fmt.Sprintf("Invoice payments migrated: %d", len(invoicesMongo)))
+	}
+}
+
+// removeDuplicatedInvoices keeps a single invoice per bill and period before the unique index is
+// created. The kept invoice is the one with payments or, when none has, the last updated; the
+// attachments of the removed ones move to it. Bills and periods with more than one paid invoice
+// are reported and must be fixed by hand.
+func removeDuplicatedInvoices(ctx context.Context, database *mongo.Database, coll *mongo.Collection) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"period": bson.M{"$nin": bson.A{nil, ""}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"bill_id": "$bill_id", "period": "$period"},
+			"invoices": bson.M{"$push": bson.M{"id": "$_id", "payments": bson.M{"$size": bson.M{"$ifNull": bson.A{"$payments", bson.A{}}}}}},
+			"count":    bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error finding duplicated invoices", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var duplicates []struct {
+		Key struct {
+			BillId string `bson:"bill_id"`
+			Period string `bson:"period"`
+		} `bson:"_id"`
+		Invoices []struct {
+			Id       string `bson:"id"`
+			Payments int    `bson:"payments"`
+		} `bson:"invoices"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		logger.Error("Error decoding duplicated invoices", err)
+		return err
+	}
+
+	conflicts := 0
+	for _, duplicate := range duplicates {
+		keep := duplicate.Invoices[0].Id
+		paid := 0
+		for _, invoice := range duplicate.Invoices {
+			if invoice.Payments > 0 {
+				if paid == 0 {
+					keep = invoice.Id
+				}
+				paid++
+			}
+		}
+
+		if paid > 1 {
+			logger.Info(fmt.Sprintf("Bill %s has %d paid invoices for period %s. Remove the duplicated ones by hand",
+				duplicate.Key.BillId, paid, duplicate.Key.Period))
+			conflicts++
+			continue
+		}
+
+		remove := make([]string, 0, len(duplicate.Invoices)-1)
+		for _, invoice := range duplicate.Invoices {
+			if invoice.Id != keep {
+				remove = append(remove, invoice.Id)
+			}
+		}
+
+		if _, err := database.Collection("attachments").UpdateMany(ctx,
+			bson.M{"invoice_id": bson.M{"$in": remove}},
+			bson.M{"$set": bson.M{"invoice_id": keep}}); err != nil {
+			logger.Error("Error moving attachments of duplicated invoices", err)
+			return err
+		}
+
+		if _, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": remove}}); err != nil {
+			logger.Error("Error removing duplicated invoices", err)
+			return err
+		}
+
+		logger.Info(fmt.Sprintf("Duplicated invoices of bill %s for period %s removed: %d",
+			duplicate.Key.BillId, duplicate.Key.Period, len(remove)))
+	}
+
+	if conflicts > 0 {
+		return fmt.Errorf("%d bills have more than one paid invoice for the same period", conflicts)
+	}
+
+	return nil
+}
+
+// createInvoiceBillPeriodIndexes makes the bill and period the key of an invoice.
+func createInvoiceBillPeriodIndexes(ctx context.Context, coll *mongo.Collection) error {
+	// the key used to be the bill and due date, which changes with the bill due day
+	if err := migration.DropIndex(ctx, coll, "bill_id_1_due_date_1"); err != nil {
+		logger.Error("Error dropping invoice bill due date index", err)
+		return err
+	}
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "bill_id", Value: 1}, {Key: "period", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "period", Value: 1}}},
+	})
+	if err != nil {
+		logger.Error("Error creating invoice bill period indexes", err)
+		return err
+	}
+
+	return nil
+}
+
+func createInvoiceEmailSourceIndexes(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email_source.message_id", Value: 1}}},
+		{Keys: bson.D{{Key: "email_source.content_hash", Value: 1}}},
+		{Keys: bson.D{{Key: "payments.email_source.message_id", Value: 1}}},
+	})
+	if err != nil {
+		logger.Error("Error creating invoice email source indexes", err)
 	}
 }
 
@@ -39,17 +303,19 @@ func (ur *InvoiceRepository) CreateInvoice(
 	ctx context.Context,
 	invoiceEntity *invoice_entity.Invoice) *internal_error.InternalError {
 
-	InvoiceEntityMongo := &InvoiceEntityMongo{
-		Id:        invoiceEntity.Id,
-		BillId:    invoiceEntity.BillId,
-		DueDate:   invoiceEntity.DueDate,
-		Amount:    int64(invoiceEntity.Amount * 100),
-		Status:    invoiceEntity.Status,
-		CreatedAt: invoiceEntity.CreatedAt.Unix(),
-		UpdatedAt: invoiceEntity.UpdatedAt.Unix(),
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
 	}
 
+	InvoiceEntityMongo := toInvoiceEntityMongo(invoiceEntity)
+	InvoiceEntityMongo.HouseholdId = householdId
+
 	if _, err := ur.Collection.InsertOne(ctx, InvoiceEntityMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError("There is already an invoice for this bill and period",
+				internal_error.Cause{Field: "period", Message: "there is already an invoice for this bill and period"})
+		}
 		logger.Error("Error trying to insert invoice", err)
 		return internal_error.NewInternalServerError("Error trying to insert invoice")
 	}
@@ -57,9 +323,76 @@ func (ur *InvoiceRepository) CreateInvoice(
 	return nil
 }
 
+func (ur *InvoiceRepository) UpdateInvoice(
+	ctx context.Context,
+	invoiceEntity *invoice_entity.Invoice) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": invoiceEntity.Id})
+
+	InvoiceEntityMongo := toInvoiceEntityMongo(invoiceEntity)
+	InvoiceEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.ReplaceOne(ctx, filter, InvoiceEntityMongo); err != nil {
+		logger.Error("Error trying to update invoice", err)
+		return internal_error.NewInternalServerError("Error trying to update invoice")
+	}
+
+	return nil
+}
+
+func (ur *InvoiceRepository) UpsertInvoice(
+	ctx context.Context,
+	invoiceEntity *invoice_entity.Invoice) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"bill_id": invoiceEntity.BillId, "period": invoiceEntity.Period})
+
+	InvoiceEntityMongo := toInvoiceEntityMongo(invoiceEntity)
+	InvoiceEntityMongo.HouseholdId = householdId
+
+	opts := options.Replace().SetUpsert(true)
+
+	if _, err := ur.Collection.ReplaceOne(ctx, filter, InvoiceEntityMongo, opts); err != nil {
+		logger.Error("Error trying to upsert invoice", err)
+		return internal_error.NewInternalServerError("Error trying to upsert invoice")
+	}
+
+	return nil
+}
+
+func (ur *InvoiceRepository) FindInvoiceByBillAndPeriod(
+	ctx context.Context,
+	billId string,
+	period string) (*invoice_entity.Invoice, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"bill_id": billId, "period": period})
+
+	var invoiceEntityMongo InvoiceEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&invoiceEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Invoice not found with billId = %s and period = %s", billId, period))
+		}
+
+		logger.Error("Error trying to find invoice by bill and period", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find invoice by bill and period")
+	}
+
+	return toInvoiceEntity(&invoiceEntityMongo), nil
+}
+
 func (ur *InvoiceRepository) FindInvoiceById(
 	ctx context.Context, invoiceId string) (*invoice_entity.Invoice, *internal_error.InternalError) {
-	filter := bson.M{"_id": invoiceId}
+	filter := household.ScopeFilter(ctx, bson.M{"_id": invoiceId})
 
 	var invoiceEntityMongo InvoiceEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&invoiceEntityMongo)
@@ -74,33 +407,63 @@ func (ur *InvoiceRepository) FindInvoiceById(
 		return nil, internal_error.NewInternalServerError("Error trying to find invoice by invoiceId")
 	}
 
-	invoiceEntity := &invoice_entity.Invoice{
-		Id:        invoiceEntityMongo.Id,
-		BillId:    invoiceEntityMongo.BillId,
-		DueDate:   invoiceEntityMongo.DueDate,
-		Amount:    float64(invoiceEntityMongo.Amount / 100),
-		Status:    invoiceEntityMongo.Status,
-		CreatedAt: time.Unix(invoiceEntityMongo.CreatedAt, 0),
-		UpdatedAt: time.Unix(invoiceEntityMongo.UpdatedAt, 0),
+	return toInvoiceEntity(&invoiceEntityMongo), nil
+}
+
+func (repo *InvoiceRepository) FindInvoices(
+	ctx context.Context,
+	invoiceFilter invoice_entity.InvoiceFilter) ([]*invoice_entity.Invoice, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if invoiceFilter.BillIds != nil {
+		filter["bill_id"] = bson.M{"$in": invoiceFilter.BillIds}
+	}
+
+	if invoiceFilter.Status != 0 {
+		filter["status"] = invoiceFilter.Status
+	}
+
+	period := bson.M{}
+	if invoiceFilter.FromPeriod != "" {
+		period["$gte"] = invoiceFilter.FromPeriod
+	}
+	if invoiceFilter.ToPeriod != "" {
+		period["$lte"] = invoiceFilter.ToPeriod
+	}
+	if len(period) > 0 {
+		filter["period"] = period
 	}
 
-	return invoiceEntity, nil
+	return repo.findInvoices(ctx, filter)
 }
 
-func (repo *InvoiceRepository) FindInvoices(
+// FindInvoicesByEmailSource returns the invoices extracted from the message with the given id
+// or from any message with the given content hash.
+func (repo *InvoiceRepository) FindInvoicesByEmailSource(
 	ctx context.Context,
-	billId string,
-	status invoice_entity.InvoiceStatus) ([]*invoice_entity.Invoice, *internal_error.InternalError) {
-	filter := bson.M{}
+	messageId string,
+	contentHash string) ([]*invoice_entity.Invoice, *internal_error.InternalError) {
 
-	if billId != "" {
-		filter["bill_id"] = billId
+	conditions := bson.A{}
+
+	if messageId != "" {
+		conditions = append(conditions, bson.M{"email_source.message_id": messageId})
 	}
 
-	if status != 0 {
-		filter["status"] = status
+	if contentHash != "" {
+		conditions = append(conditions, bson.M{"email_source.content_hash": contentHash})
 	}
 
+	if len(conditions) == 0 {
+		return []*invoice_entity.Invoice{}, nil
+	}
+
+	return repo.findInvoices(ctx, household.ScopeFilter(ctx, bson.M{"$or": conditions}))
+}
+
+func (repo *InvoiceRepository) findInvoices(
+	ctx context.Context, filter bson.M) ([]*invoice_entity.Invoice, *internal_error.InternalError) {
+
 	cursor, err := repo.Collection.Find(ctx, filter)
 	if err != nil {
 		logger.Error("Error finding invoices", err)
@@ -115,16 +478,8 @@ func (repo *InvoiceRepository) FindInvoices(
 	}
 
 	invoicesEntity := make([]*invoice_entity.Invoice, len(invoicesMongo))
-	for i, invoice := range invoicesMongo {
-		invoicesEntity[i] = &invoice_entity.Invoice{
-			Id:        invoice.Id,
-			BillId:    invoice.BillId,
-			DueDate:   invoice.DueDate,
-			Amount:    float64(invoice.Amount) / 100,
-			Status:    invoice.Status,
-			CreatedAt: time.Unix(invoice.CreatedAt, 0),
-			UpdatedAt: time.Unix(invoice.UpdatedAt, 0),
-		}
+	for i := range invoicesMongo {
+		invoicesEntity[i] = toInvoiceEntity(&invoicesMongo[i])
 	}
 
 	return invoicesEntity, nil
@@ -136,15 +491,22 @@ func (repo *InvoiceRepository) DeleteInvoices(
 	status invoice_entity.InvoiceStatus,
 	dueDate string) (uint, *internal_error.InternalError) {
 
-	filter := bson.M{}
+	filter := household.ScopeFilter(ctx, bson.M{})
 
 	if billId != "" {
 		filter["bill_id"] = billId
 	}
 
+	if status == invoice_entity.Paid || status == invoice_entity.PartiallyPaid {
+		return 0, internal_error.NewBadRequestError("Invoices with payments cannot be deleted")
+	}
+
 	if status != 0 {
 		filter["status"] = status
+	} else {
+		filter["status"] = bson.M{"$nin": bson.A{invoice_entity.Paid, invoice_entity.PartiallyPaid}}
 	}
+	filter["payments.0"] = bson.M{"$exists": false}
 
 	if dueDate != "" {
 		filter["due_date"] = dueDate
@@ -158,3 +520,137 @@ func (repo *InvoiceRepository) DeleteInvoices(
 
 	return uint(result.DeletedCount), nil
 }
+
+func (repo *InvoiceRepository) DeleteInvoice(
+	ctx context.Context, invoiceId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": invoiceId})
+
+	if _, err := repo.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete invoice", err)
+		return internal_error.NewInternalServerError("Error trying to delete invoice")
+	}
+
+	return nil
+}
+
+func toInvoiceEntityMongo(invoiceEntity *invoice_entity.Invoice) *InvoiceEntityMongo {
+	invoiceEntityMongo := &InvoiceEntityMongo{
+		Id:        invoiceEntity.Id,
+		BillId:    invoiceEntity.BillId,
+		Period:    invoiceEntity.Period,
+		DueDate:   invoiceEntity.DueDate,
+		Amount:    invoiceEntity.Amount,
+		Status:    invoiceEntity.Status,
+		Barcode:   invoiceEntity.Barcode,
+		CreatedAt: invoiceEntity.CreatedAt.Unix(),
+		UpdatedAt: invoiceEntity.UpdatedAt.Unix(),
+	}
+
+	invoiceEntityMongo.EmailSource = toEmailSourceMongo(invoiceEntity.EmailSource)
+
+	for _, share := range invoiceEntity.Shares {
+		invoiceEntityMongo.Shares = append(invoiceEntityMongo.Shares, ShareMongo(share))
+	}
+
+	invoiceEntityMongo.Payments = make([]PaymentMongo, len(invoiceEntity.Payments))
+	for i, payment := range invoiceEntity.Payments {
+		invoiceEntityMongo.Payments[i] = PaymentMongo{
+			Id:          payment.Id,
+			PaymentDate: payment.PaymentDate,
+			Amount:      payment.Amount,
+			Method:      payment.Method,
+			Notes:       payment.Notes,
+			Reference:   payment.Reference,
+			EmailSource: toEmailSourceMongo(payment.EmailSource),
+			Automatic:   payment.Automatic,
+			PaidBy:      payment.PaidBy,
+			CreatedAt:   payment.CreatedAt.Unix(),
+		}
+	}
+
+	for _, change := range invoiceEntity.AmountChanges {
+		invoiceEntityMongo.AmountChanges = append(invoiceEntityMongo.AmountChanges, AmountChangeMongo{
+			PreviousAmount: change.PreviousAmount,
+			Amount:         change.Amount,
+			ChangedAt:      change.ChangedAt.Unix(),
+		})
+	}
+
+	return invoiceEntityMongo
+}
+
+func toInvoiceEntity(invoiceEntityMongo *InvoiceEntityMongo) *invoice_entity.Invoice {
+	invoiceEntity := &invoice_entity.Invoice{
+		Id:        invoiceEntityMongo.Id,
+		BillId:    invoiceEntityMongo.BillId,
+		Period:    invoiceEntityMongo.Period,
+		DueDate:   invoiceEntityMongo.DueDate,
+		Amount:    invoiceEntityMongo.Amount,
+		Status:    invoiceEntityMongo.Status,
+		Barcode:   invoiceEntityMongo.Barcode,
+		CreatedAt: time.Unix(invoiceEntityMongo.CreatedAt, 0),
+		UpdatedAt: time.Unix(invoiceEntityMongo.UpdatedAt, 0),
+	}
+
+	invoiceEntity.EmailSource = toEmailSource(invoiceEntityMongo.EmailSource)
+
+	for _, share := range invoiceEntityMongo.Shares {
+		invoiceEntity.Shares = append(invoiceEntity.Shares, invoice_entity.Share(share))
+	}
+
+	invoiceEntity.Payments = make([]*invoice_entity.Payment, len(invoiceEntityMongo.Payments))
+	for i, payment := range invoiceEntityMongo.Payments {
+		invoiceEntity.Payments[i] = &invoice_entity.Payment{
+			Id:          payment.Id,
+			PaymentDate: payment.PaymentDate,
+			Amount:      payment.Amount,
+			Method:      payment.Method,
+			Notes:       payment.Notes,
+			Reference:   payment.Reference,
+			EmailSource: toEmailSource(payment.EmailSource),
+			Automatic:   payment.Automatic,
+			PaidBy:      payment.PaidBy,
+			CreatedAt:   time.Unix(payment.CreatedAt, 0),
+		}
+	}
+
+	for _, change := range invoiceEntityMongo.AmountChanges {
+		invoiceEntity.AmountChanges = append(invoiceEntity.AmountChanges, &invoice_entity.AmountChange{
+			PreviousAmount: change.PreviousAmount,
+			Amount:         change.Amount,
+			ChangedAt:      time.Unix(change.ChangedAt, 0),
+		})
+	}
+
+	return invoiceEntity
+}
+
+func toEmailSourceMongo(source *invoice_entity.EmailSource) *EmailSourceMongo {
+	if source == nil {
+		return nil
+	}
+
+	return &EmailSourceMongo{
+		MessageId:   source.MessageId,
+		ThreadId:    source.ThreadId,
+		Sender:      source.Sender,
+		ReceivedAt:  source.ReceivedAt.Unix(),
+		ContentHash: source.ContentHash,
+		DuplicateOf: source.DuplicateOf,
+	}
+}
+
+func toEmailSource(source *EmailSourceMongo) *invoice_entity.EmailSource {
+	if source == nil {
+		return nil
+	}
+
+	return &invoice_entity.EmailSource{
+		MessageId:   source.MessageId,
+		ThreadId:    source.ThreadId,
+		Sender:      source.Sender,
+		ReceivedAt:  time.Unix(source.ReceivedAt, 0),
+		ContentHash: source.ContentHash,
+		DuplicateOf: source.DuplicateOf,
+	}
+}
@@ -0,0 +1,172 @@
+package mail_account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MailAccountEntityMongo struct {
+	Id          string                                  `bson:"_id"`
+	HouseholdId string                                  `bson:"household_id,omitempty"`
+	UserId      string                                  `bson:"user_id"`
+	Provider    mail_account_entity.MailAccountProvider `bson:"provider"`
+	Address     string                                  `bson:"address"`
+	Status      mail_account_entity.MailAccountStatus   `bson:"status"`
+	CreatedAt   int64                                   `bson:"created_at"`
+	UpdatedAt   int64                                   `bson:"updated_at"`
+}
+
+type MailAccountRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewMailAccountRepository(ctx context.Context, database *mongo.Database) *MailAccountRepository {
+	coll := database.Collection("mailAccounts")
+
+	createMailAccountAddressUniqueIndex(ctx, coll)
+
+	return &MailAccountRepository{
+		Collection: coll,
+	}
+}
+
+func createMailAccountAddressUniqueIndex(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "address", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("Error creating mailAccount address unique index", err)
+	}
+}
+
+func (ur *MailAccountRepository) CreateMailAccount(
+	ctx context.Context,
+	mailAccountEntity *mail_account_entity.MailAccount) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	MailAccountEntityMongo := toMailAccountEntityMongo(mailAccountEntity)
+	MailAccountEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.InsertOne(ctx, MailAccountEntityMongo); err != nil {
+		logger.Error("Error trying to insert mailAccount", err)
+		return internal_error.NewInternalServerError("Error trying to insert mailAccount")
+	}
+
+	return nil
+}
+
+func (ur *MailAccountRepository) UpdateMailAccount(
+	ctx context.Context,
+	mailAccountEntity *mail_account_entity.MailAccount) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": mailAccountEntity.Id})
+
+	MailAccountEntityMongo := toMailAccountEntityMongo(mailAccountEntity)
+	MailAccountEntityMongo.HouseholdId = householdId
+
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": MailAccountEntityMongo}); err != nil {
+		logger.Error("Error trying to update mailAccount", err)
+		return internal_error.NewInternalServerError("Error trying to update mailAccount")
+	}
+
+	return nil
+}
+
+func (ur *MailAccountRepository) FindMailAccountById(
+	ctx context.Context, mailAccountId string) (*mail_account_entity.MailAccount, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": mailAccountId})
+
+	var mailAccountEntityMongo MailAccountEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&mailAccountEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("MailAccount not found with this id = %s", mailAccountId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("MailAccount not found with this id = %s", mailAccountId))
+		}
+
+		logger.Error("Error trying to find mailAccount by mailAccountId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find mailAccount by mailAccountId")
+	}
+
+	return toMailAccountEntity(mailAccountEntityMongo), nil
+}
+
+func (repo *MailAccountRepository) FindMailAccounts(
+	ctx context.Context,
+	status mail_account_entity.MailAccountStatus,
+	userId string) ([]*mail_account_entity.MailAccount, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if status != 0 {
+		filter["status"] = status
+	}
+
+	if userId != "" {
+		filter["user_id"] = userId
+	}
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding mailAccounts", err)
+		return nil, internal_error.NewInternalServerError("Error finding mailAccounts")
+	}
+	defer cursor.Close(ctx)
+
+	var mailAccountsMongo []MailAccountEntityMongo
+	if err := cursor.All(ctx, &mailAccountsMongo); err != nil {
+		logger.Error("Error decoding mailAccounts", err)
+		return nil, internal_error.NewInternalServerError("Error decoding mailAccounts")
+	}
+
+	mailAccountsEntity := make([]*mail_account_entity.MailAccount, len(mailAccountsMongo))
+	for i, mailAccount := range mailAccountsMongo {
+		mailAccountsEntity[i] = toMailAccountEntity(mailAccount)
+	}
+
+	return mailAccountsEntity, nil
+}
+
+func toMailAccountEntityMongo(mailAccountEntity *mail_account_entity.MailAccount) *MailAccountEntityMongo {
+	return &MailAccountEntityMongo{
+		Id:        mailAccountEntity.Id,
+		UserId:    mailAccountEntity.UserId,
+		Provider:  mailAccountEntity.Provider,
+		Address:   mailAccountEntity.Address,
+		Status:    mailAccountEntity.Status,
+		CreatedAt: mailAccountEntity.CreatedAt.Unix(),
+		UpdatedAt: mailAccountEntity.UpdatedAt.Unix(),
+	}
+}
+
+func toMailAccountEntity(mailAccountEntityMongo MailAccountEntityMongo) *mail_account_entity.MailAccount {
+	return &mail_account_entity.MailAccount{
+		Id:        mailAccountEntityMongo.Id,
+		UserId:    mailAccountEntityMongo.UserId,
+		Provider:  mailAccountEntityMongo.Provider,
+		Address:   mailAccountEntityMongo.Address,
+		Status:    mailAccountEntityMongo.Status,
+		CreatedAt: time.Unix(mailAccountEntityMongo.CreatedAt, 0),
+		UpdatedAt: time.Unix(mailAccountEntityMongo.UpdatedAt, 0),
+	}
+}
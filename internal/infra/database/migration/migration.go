@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	namespaceNotFoundCode = 26
+	indexNotFoundCode     = 27
+)
+
+// DropIndex drops an index replaced by a newer one. An index already dropped, or a collection not
+// created yet, is not an error.
+func DropIndex(ctx context.Context, coll *mongo.Collection, name string) error {
+	_, err := coll.Indexes().DropOne(ctx, name)
+
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) &&
+		(commandErr.Code == indexNotFoundCode || commandErr.Code == namespaceNotFoundCode) {
+		return nil
+	}
+
+	return err
+}
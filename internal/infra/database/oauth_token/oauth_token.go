@@ -0,0 +1,171 @@
+package oauth_token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/oauth_token_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/secret_service"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OAuthTokenEntityMongo struct {
+	Id           string                                `bson:"_id"`
+	HouseholdId  string                                `bson:"household_id,omitempty"`
+	Provider     oauth_token_entity.OAuthTokenProvider `bson:"provider"`
+	AccessToken  string                                `bson:"access_token"`
+	TokenType    string                                `bson:"token_type"`
+	RefreshToken string                                `bson:"refresh_token"`
+	Expiry       int64                                 `bson:"expiry"`
+	CreatedAt    int64                                 `bson:"created_at"`
+	UpdatedAt    int64                                 `bson:"updated_at"`
+}
+
+// Access and refresh tokens are stored encrypted.
+type OAuthTokenRepository struct {
+	Collection    *mongo.Collection
+	secretService secret_service.SecretServiceInterface
+}
+
+func NewOAuthTokenRepository(ctx context.Context, database *mongo.Database, secretService secret_service.SecretServiceInterface) *OAuthTokenRepository {
+	coll := database.Collection("oauthTokens")
+
+	return &OAuthTokenRepository{
+		Collection:    coll,
+		secretService: secretService,
+	}
+}
+
+func (ur *OAuthTokenRepository) SaveOAuthToken(
+	ctx context.Context,
+	oauthTokenEntity *oauth_token_entity.OAuthToken) *internal_error.InternalError {
+
+	filter := bson.M{"_id": oauthTokenEntity.Id}
+
+	accessToken, err := ur.secretService.Encrypt(oauthTokenEntity.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	refreshToken, err := ur.secretService.Encrypt(oauthTokenEntity.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	// tokens are renewed outside of requests too, so the household is optional here
+	scope, _ := household_entity.ScopeFromContext(ctx)
+
+	OAuthTokenEntityMongo := &OAuthTokenEntityMongo{
+		Id:           oauthTokenEntity.Id,
+		HouseholdId:  scope.HouseholdId,
+		Provider:     oauthTokenEntity.Provider,
+		AccessToken:  accessToken,
+		TokenType:    oauthTokenEntity.TokenType,
+		RefreshToken: refreshToken,
+		Expiry:       oauthTokenEntity.Expiry.Unix(),
+		CreatedAt:    oauthTokenEntity.CreatedAt.Unix(),
+		UpdatedAt:    oauthTokenEntity.UpdatedAt.Unix(),
+	}
+
+	// $set keeps the household of the token when it is renewed outside of a request
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": OAuthTokenEntityMongo}, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to save oauthToken", err)
+		return internal_error.NewInternalServerError("Error trying to save oauthToken")
+	}
+
+	return nil
+}
+
+func (ur *OAuthTokenRepository) FindOAuthTokenById(
+	ctx context.Context, oauthTokenId string) (*oauth_token_entity.OAuthToken, *internal_error.InternalError) {
+	filter := bson.M{"_id": oauthTokenId}
+
+	var oauthTokenEntityMongo OAuthTokenEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&oauthTokenEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("OAuthToken not found with this id = %s", oauthTokenId))
+		}
+
+		logger.Error("Error trying to find oauthToken by oauthTokenId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find oauthToken by oauthTokenId")
+	}
+
+	accessToken, e := ur.secretService.Decrypt(oauthTokenEntityMongo.AccessToken)
+	if e != nil {
+		return nil, e
+	}
+
+	refreshToken, e := ur.secretService.Decrypt(oauthTokenEntityMongo.RefreshToken)
+	if e != nil {
+		return nil, e
+	}
+
+	oauthTokenEntity := &oauth_token_entity.OAuthToken{
+		Id:           oauthTokenEntityMongo.Id,
+		Provider:     oauthTokenEntityMongo.Provider,
+		AccessToken:  accessToken,
+		TokenType:    oauthTokenEntityMongo.TokenType,
+		RefreshToken: refreshToken,
+		Expiry:       time.Unix(oauthTokenEntityMongo.Expiry, 0),
+		CreatedAt:    time.Unix(oauthTokenEntityMongo.CreatedAt, 0),
+		UpdatedAt:    time.Unix(oauthTokenEntityMongo.UpdatedAt, 0),
+	}
+
+	return oauthTokenEntity, nil
+}
+
+func (ur *OAuthTokenRepository) DeleteOAuthToken(
+	ctx context.Context, oauthTokenId string) *internal_error.InternalError {
+	filter := bson.M{"_id": oauthTokenId}
+
+	if _, err := ur.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete oauthToken", err)
+		return internal_error.NewInternalServerError("Error trying to delete oauthToken")
+	}
+
+	return nil
+}
+
+func (ur *OAuthTokenRepository) ReencryptSecrets(ctx context.Context) (uint, *internal_error.InternalError) {
+	cursor, err := ur.Collection.Find(ctx, household.ScopeFilter(ctx, bson.M{}))
+	if err != nil {
+		logger.Error("Error finding oauthTokens", err)
+		return 0, internal_error.NewInternalServerError("Error finding oauthTokens")
+	}
+	defer cursor.Close(ctx)
+
+	var oauthTokensMongo []OAuthTokenEntityMongo
+	if err := cursor.All(ctx, &oauthTokensMongo); err != nil {
+		logger.Error("Error decoding oauthTokens", err)
+		return 0, internal_error.NewInternalServerError("Error decoding oauthTokens")
+	}
+
+	var count uint
+	for _, oauthToken := range oauthTokensMongo {
+		if ur.secretService.IsCurrent(oauthToken.AccessToken) && ur.secretService.IsCurrent(oauthToken.RefreshToken) {
+			continue
+		}
+
+		oauthTokenEntity, err := ur.FindOAuthTokenById(ctx, oauthToken.Id)
+		if err != nil {
+			return count, err
+		}
+
+		if err := ur.SaveOAuthToken(ctx, oauthTokenEntity); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,120 @@
+package password_setup_token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/auth_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordSetupTokenEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	TokenHash string `bson:"token_hash"`
+	ExpiresAt int64  `bson:"expires_at"`
+	UsedAt    int64  `bson:"used_at,omitempty"`
+	CreatedAt int64  `bson:"created_at"`
+}
+
+type PasswordSetupTokenRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPasswordSetupTokenRepository(ctx context.Context, database *mongo.Database) *PasswordSetupTokenRepository {
+	coll := database.Collection("passwordSetupTokens")
+
+	createPasswordSetupTokenIndexes(ctx, coll)
+
+	return &PasswordSetupTokenRepository{
+		Collection: coll,
+	}
+}
+
+func createPasswordSetupTokenIndexes(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		logger.Error("Error creating password setup token indexes", err)
+	}
+}
+
+func (repo *PasswordSetupTokenRepository) CreatePasswordSetupToken(
+	ctx context.Context,
+	setupTokenEntity *auth_entity.PasswordSetupToken) *internal_error.InternalError {
+
+	setupTokenEntityMongo := &PasswordSetupTokenEntityMongo{
+		Id:        setupTokenEntity.Id,
+		UserId:    setupTokenEntity.UserId,
+		TokenHash: setupTokenEntity.TokenHash,
+		ExpiresAt: setupTokenEntity.ExpiresAt.Unix(),
+		CreatedAt: setupTokenEntity.CreatedAt.Unix(),
+	}
+
+	if _, err := repo.Collection.InsertOne(ctx, setupTokenEntityMongo); err != nil {
+		logger.Error("Error trying to insert password setup token", err)
+		return internal_error.NewInternalServerError("Error trying to insert password setup token")
+	}
+
+	return nil
+}
+
+func (repo *PasswordSetupTokenRepository) HasActivePasswordSetupToken(
+	ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+
+	filter := bson.M{
+		"user_id":    userId,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now().Unix()},
+	}
+
+	count, err := repo.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to count password setup tokens", err)
+		return false, internal_error.NewInternalServerError("Error trying to count password setup tokens")
+	}
+
+	return count > 0, nil
+}
+
+func (repo *PasswordSetupTokenRepository) UsePasswordSetupToken(
+	ctx context.Context, tokenHash string) (*auth_entity.PasswordSetupToken, *internal_error.InternalError) {
+
+	now := time.Now().Unix()
+	filter := bson.M{
+		"token_hash": tokenHash,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"used_at": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var setupTokenEntityMongo PasswordSetupTokenEntityMongo
+	if err := repo.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&setupTokenEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("Password setup token not found")
+		}
+
+		logger.Error("Error trying to use password setup token", err)
+		return nil, internal_error.NewInternalServerError("Error trying to use password setup token")
+	}
+
+	return &auth_entity.PasswordSetupToken{
+		Id:        setupTokenEntityMongo.Id,
+		UserId:    setupTokenEntityMongo.UserId,
+		TokenHash: setupTokenEntityMongo.TokenHash,
+		ExpiresAt: time.Unix(setupTokenEntityMongo.ExpiresAt, 0),
+		UsedAt:    time.Unix(setupTokenEntityMongo.UsedAt, 0),
+		CreatedAt: time.Unix(setupTokenEntityMongo.CreatedAt, 0),
+	}, nil
+}
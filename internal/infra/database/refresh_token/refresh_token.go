@@ -0,0 +1,142 @@
+package refresh_token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/auth_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefreshTokenEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	TokenHash string `bson:"token_hash"`
+	ExpiresAt int64  `bson:"expires_at"`
+	RevokedAt int64  `bson:"revoked_at,omitempty"`
+	CreatedAt int64  `bson:"created_at"`
+}
+
+type RefreshTokenRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(ctx context.Context, database *mongo.Database) *RefreshTokenRepository {
+	coll := database.Collection("refreshTokens")
+
+	createRefreshTokenIndexes(ctx, coll)
+
+	return &RefreshTokenRepository{
+		Collection: coll,
+	}
+}
+
+func createRefreshTokenIndexes(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		logger.Error("Error creating refresh token indexes", err)
+	}
+}
+
+func (repo *RefreshTokenRepository) CreateRefreshToken(
+	ctx context.Context,
+	refreshTokenEntity *auth_entity.RefreshToken) *internal_error.InternalError {
+
+	if _, err := repo.Collection.InsertOne(ctx, toRefreshTokenEntityMongo(refreshTokenEntity)); err != nil {
+		logger.Error("Error trying to insert refresh token", err)
+		return internal_error.NewInternalServerError("Error trying to insert refresh token")
+	}
+
+	return nil
+}
+
+// RevokeRefreshToken revokes the token in a single operation, so it is only found once when
+// revoked by concurrent requests. It returns the token as it was before being revoked.
+func (repo *RefreshTokenRepository) RevokeRefreshToken(
+	ctx context.Context, tokenHash string) (*auth_entity.RefreshToken, *internal_error.InternalError) {
+
+	filter := bson.M{"token_hash": tokenHash, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}}
+
+	var refreshTokenEntityMongo RefreshTokenEntityMongo
+	if err := repo.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&refreshTokenEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("Refresh token not found")
+		}
+
+		logger.Error("Error trying to revoke refresh token", err)
+		return nil, internal_error.NewInternalServerError("Error trying to revoke refresh token")
+	}
+
+	return toRefreshTokenEntity(&refreshTokenEntityMongo), nil
+}
+
+func (repo *RefreshTokenRepository) FindRefreshTokenByHash(
+	ctx context.Context, tokenHash string) (*auth_entity.RefreshToken, *internal_error.InternalError) {
+	filter := bson.M{"token_hash": tokenHash}
+
+	var refreshTokenEntityMongo RefreshTokenEntityMongo
+	if err := repo.Collection.FindOne(ctx, filter).Decode(&refreshTokenEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("Refresh token not found")
+		}
+
+		logger.Error("Error trying to find refresh token", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find refresh token")
+	}
+
+	return toRefreshTokenEntity(&refreshTokenEntityMongo), nil
+}
+
+func (repo *RefreshTokenRepository) RevokeUserRefreshTokens(
+	ctx context.Context, userId string) *internal_error.InternalError {
+	filter := bson.M{"user_id": userId, "revoked_at": bson.M{"$exists": false}}
+
+	if _, err := repo.Collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}}); err != nil {
+		logger.Error("Error trying to revoke refresh tokens", err)
+		return internal_error.NewInternalServerError("Error trying to revoke refresh tokens")
+	}
+
+	return nil
+}
+
+func toRefreshTokenEntityMongo(refreshTokenEntity *auth_entity.RefreshToken) *RefreshTokenEntityMongo {
+	refreshTokenEntityMongo := &RefreshTokenEntityMongo{
+		Id:        refreshTokenEntity.Id,
+		UserId:    refreshTokenEntity.UserId,
+		TokenHash: refreshTokenEntity.TokenHash,
+		ExpiresAt: refreshTokenEntity.ExpiresAt.Unix(),
+		CreatedAt: refreshTokenEntity.CreatedAt.Unix(),
+	}
+	if refreshTokenEntity.IsRevoked() {
+		refreshTokenEntityMongo.RevokedAt = refreshTokenEntity.RevokedAt.Unix()
+	}
+
+	return refreshTokenEntityMongo
+}
+
+func toRefreshTokenEntity(refreshTokenEntityMongo *RefreshTokenEntityMongo) *auth_entity.RefreshToken {
+	refreshTokenEntity := &auth_entity.RefreshToken{
+		Id:        refreshTokenEntityMongo.Id,
+		UserId:    refreshTokenEntityMongo.UserId,
+		TokenHash: refreshTokenEntityMongo.TokenHash,
+		ExpiresAt: time.Unix(refreshTokenEntityMongo.ExpiresAt, 0),
+		CreatedAt: time.Unix(refreshTokenEntityMongo.CreatedAt, 0),
+	}
+	if refreshTokenEntityMongo.RevokedAt != 0 {
+		refreshTokenEntity.RevokedAt = time.Unix(refreshTokenEntityMongo.RevokedAt, 0)
+	}
+
+	return refreshTokenEntity
+}
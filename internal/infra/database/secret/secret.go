@@ -0,0 +1,230 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/secret_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/secret_service"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SecretEntityMongo struct {
+	Id          string `bson:"_id"`
+	HouseholdId string `bson:"household_id,omitempty"`
+	OwnerId     string `bson:"owner_id"`
+	Name        string `bson:"name"`
+	Value       string `bson:"value"`
+	CreatedAt   int64  `bson:"created_at"`
+	UpdatedAt   int64  `bson:"updated_at"`
+}
+
+type SecretRepository struct {
+	Collection    *mongo.Collection
+	secretService secret_service.SecretServiceInterface
+}
+
+func NewSecretRepository(ctx context.Context, database *mongo.Database, secretService secret_service.SecretServiceInterface) *SecretRepository {
+	coll := database.Collection("secrets")
+
+	createSecretOwnerNameUniqueIndex(ctx, coll)
+
+	return &SecretRepository{
+		Collection:    coll,
+		secretService: secretService,
+	}
+}
+
+func createSecretOwnerNameUniqueIndex(ctx context.Context, coll *mongo.Collection) {
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("Error creating secret owner and name unique index", err)
+	}
+}
+
+func (ur *SecretRepository) CreateSecret(
+	ctx context.Context,
+	secretEntity *secret_entity.Secret) *internal_error.InternalError {
+
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	value, err := ur.secretService.Encrypt(secretEntity.Value)
+	if err != nil {
+		return err
+	}
+
+	SecretEntityMongo := &SecretEntityMongo{
+		Id:          secretEntity.Id,
+		HouseholdId: householdId,
+		OwnerId:     secretEntity.OwnerId,
+		Name:        secretEntity.Name,
+		Value:       value,
+		CreatedAt:   secretEntity.CreatedAt.Unix(),
+		UpdatedAt:   secretEntity.UpdatedAt.Unix(),
+	}
+
+	if _, err := ur.Collection.InsertOne(ctx, SecretEntityMongo); err != nil {
+		logger.Error("Error trying to insert secret", err)
+		return internal_error.NewInternalServerError("Error trying to insert secret")
+	}
+
+	return nil
+}
+
+func (ur *SecretRepository) UpdateSecret(
+	ctx context.Context,
+	secretEntity *secret_entity.Secret) *internal_error.InternalError {
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": secretEntity.Id})
+
+	value, err := ur.secretService.Encrypt(secretEntity.Value)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"value":      value,
+		"updated_at": secretEntity.UpdatedAt.Unix(),
+	}}
+
+	if _, err := ur.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error trying to update secret", err)
+		return internal_error.NewInternalServerError("Error trying to update secret")
+	}
+
+	return nil
+}
+
+func (ur *SecretRepository) FindSecretById(
+	ctx context.Context, secretId string) (*secret_entity.Secret, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": secretId})
+
+	var secretEntityMongo SecretEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&secretEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Secret not found with this id = %s", secretId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Secret not found with this id = %s", secretId))
+		}
+
+		logger.Error("Error trying to find secret by secretId", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find secret by secretId")
+	}
+
+	return ur.toSecretEntity(secretEntityMongo)
+}
+
+func (repo *SecretRepository) FindSecrets(
+	ctx context.Context,
+	ownerId string) ([]*secret_entity.Secret, *internal_error.InternalError) {
+	filter := household.ScopeFilter(ctx, bson.M{})
+
+	if ownerId != "" {
+		filter["owner_id"] = ownerId
+	}
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding secrets", err)
+		return nil, internal_error.NewInternalServerError("Error finding secrets")
+	}
+	defer cursor.Close(ctx)
+
+	var secretsMongo []SecretEntityMongo
+	if err := cursor.All(ctx, &secretsMongo); err != nil {
+		logger.Error("Error decoding secrets", err)
+		return nil, internal_error.NewInternalServerError("Error decoding secrets")
+	}
+
+	secretsEntity := make([]*secret_entity.Secret, len(secretsMongo))
+	for i, secret := range secretsMongo {
+		secretEntity, err := repo.toSecretEntity(secret)
+		if err != nil {
+			return nil, err
+		}
+		secretsEntity[i] = secretEntity
+	}
+
+	return secretsEntity, nil
+}
+
+func (repo *SecretRepository) DeleteSecret(
+	ctx context.Context, secretId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": secretId})
+
+	if _, err := repo.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete secret", err)
+		return internal_error.NewInternalServerError("Error trying to delete secret")
+	}
+
+	return nil
+}
+
+func (repo *SecretRepository) ReencryptSecrets(ctx context.Context) (uint, *internal_error.InternalError) {
+	cursor, err := repo.Collection.Find(ctx, household.ScopeFilter(ctx, bson.M{}))
+	if err != nil {
+		logger.Error("Error finding secrets", err)
+		return 0, internal_error.NewInternalServerError("Error finding secrets")
+	}
+	defer cursor.Close(ctx)
+
+	var secretsMongo []SecretEntityMongo
+	if err := cursor.All(ctx, &secretsMongo); err != nil {
+		logger.Error("Error decoding secrets", err)
+		return 0, internal_error.NewInternalServerError("Error decoding secrets")
+	}
+
+	var count uint
+	for _, secret := range secretsMongo {
+		if repo.secretService.IsCurrent(secret.Value) {
+			continue
+		}
+
+		value, err := repo.secretService.Decrypt(secret.Value)
+		if err != nil {
+			return count, err
+		}
+		if value, err = repo.secretService.Encrypt(value); err != nil {
+			return count, err
+		}
+
+		filter := bson.M{"_id": secret.Id}
+		if _, err := repo.Collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"value": value}}); err != nil {
+			logger.Error("Error trying to reencrypt secret", err)
+			return count, internal_error.NewInternalServerError("Error trying to reencrypt secret")
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (repo *SecretRepository) toSecretEntity(secretEntityMongo SecretEntityMongo) (*secret_entity.Secret, *internal_error.InternalError) {
+	value, err := repo.secretService.Decrypt(secretEntityMongo.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret_entity.Secret{
+		Id:        secretEntityMongo.Id,
+		OwnerId:   secretEntityMongo.OwnerId,
+		Name:      secretEntityMongo.Name,
+		Value:     value,
+		CreatedAt: time.Unix(secretEntityMongo.CreatedAt, 0),
+		UpdatedAt: time.Unix(secretEntityMongo.UpdatedAt, 0),
+	}, nil
+}
@@ -8,7 +8,10 @@ import (
 
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/table_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/household"
+	"github.com/regismartiny/lembrador-contas-go/internal/infra/database/migration"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,12 +19,13 @@ import (
 )
 
 type TableValueSourceEntityMongo struct {
-	Id        string                                           `bson:"_id"`
-	Name      string                                           `bson:"name"`
-	Data      []table_value_source_entity.TableValueSourceData `bson:"company"`
-	Status    table_value_source_entity.TableValueSourceStatus `bson:"status"`
-	CreatedAt int64                                            `bson:"created_at"`
-	UpdatedAt int64                                            `bson:"updated_at"`
+	Id          string                                           `bson:"_id"`
+	HouseholdId string                                           `bson:"household_id,omitempty"`
+	Name        string                                           `bson:"name"`
+	Data        []table_value_source_entity.TableValueSourceData `bson:"company"`
+	Status      table_value_source_entity.TableValueSourceStatus `bson:"status"`
+	CreatedAt   int64                                            `bson:"created_at"`
+	UpdatedAt   int64                                            `bson:"updated_at"`
 }
 
 type TableValueSourceRepository struct {
@@ -31,6 +35,7 @@ type TableValueSourceRepository struct {
 func NewTableValueSourceRepository(ctx context.Context, database *mongo.Database) *TableValueSourceRepository {
 	coll := database.Collection("tableValueSources")
 
+	migrateTableValueSourceAmounts(ctx, coll)
 	createTableValueSourceNameUniqueIndex(ctx, coll)
 
 	return &TableValueSourceRepository{
@@ -38,9 +43,51 @@ func NewTableValueSourceRepository(ctx context.Context, database *mongo.Database
 	}
 }
 
+// migrateTableValueSourceAmounts converts the amounts stored in reais, as doubles, to integer cents.
+func migrateTableValueSourceAmounts(ctx context.Context, coll *mongo.Collection) {
+	cursor, err := coll.Find(ctx, bson.M{"company.amount": bson.M{"$type": "double"}})
+	if err != nil {
+		logger.Error("Error finding tableValueSources with amounts in reais", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var documents []struct {
+		Id   string   `bson:"_id"`
+		Data []bson.M `bson:"company"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		logger.Error("Error decoding tableValueSources with amounts in reais", err)
+		return
+	}
+
+	for _, document := range documents {
+		for _, data := range document.Data {
+			if amount, ok := data["amount"].(float64); ok {
+				data["amount"] = money.FromFloat(amount).Cents()
+			}
+		}
+
+		if _, err := coll.UpdateByID(ctx, document.Id, bson.M{"$set": bson.M{"company": document.Data}}); err != nil {
+			logger.Error("Error converting tableValueSource amounts to cents", err)
+			return
+		}
+	}
+
+	if len(documents) > 0 {
+		logger.Info(fmt.Sprintf("TableValueSource amounts migrated to cents: %d", len(documents)))
+	}
+}
+
+// createTableValueSourceNameUniqueIndex makes the tableValueSource names unique within a household.
 func createTableValueSourceNameUniqueIndex(ctx context.Context, coll *mongo.Collection) {
+	// the names used to be unique across all the tableValueSources
+	if err := migration.DropIndex(ctx, coll, "name_1"); err != nil {
+		logger.Error("Error dropping tableValueSource name index", err)
+	}
+
 	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.M{"name": 1},
+		Keys:    bson.D{{Key: "household_id", Value: 1}, {Key: "name", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	})
 	if err != nil {
@@ -52,13 +99,19 @@ func (ur *TableValueSourceRepository) CreateTableValueSource(
 	ctx context.Context,
 	tableValueSourceEntity *table_value_source_entity.TableValueSource) *internal_error.InternalError {
 
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
 	TableValueSourceEntityMongo := &TableValueSourceEntityMongo{
-		Id:        tableValueSourceEntity.Id,
-		Name:      tableValueSourceEntity.Name,
-		Data:      tableValueSourceEntity.Data,
-		Status:    tableValueSourceEntity.Status,
-		CreatedAt: tableValueSourceEntity.CreatedAt.Unix(),
-		UpdatedAt: tableValueSourceEntity.UpdatedAt.Unix(),
+		Id:          tableValueSourceEntity.Id,
+		HouseholdId: householdId,
+		Name:        tableValueSourceEntity.Name,
+		Data:        tableValueSourceEntity.Data,
+		Status:      tableValueSourceEntity.Status,
+		CreatedAt:   tableValueSourceEntity.CreatedAt.Unix(),
+		UpdatedAt:   tableValueSourceEntity.UpdatedAt.Unix(),
 	}
 
 	if _, err := ur.Collection.InsertOne(ctx, TableValueSourceEntityMongo); err != nil {
@@ -73,19 +126,24 @@ func (ur *TableValueSourceRepository) UpdateTableValueSource(
 	ctx context.Context,
 	tableValueSourceEntity *table_value_source_entity.TableValueSource) *internal_error.InternalError {
 
-	filter := bson.M{"_id": tableValueSourceEntity.Id}
+	householdId, err := household.ScopeId(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := household.ScopeFilter(ctx, bson.M{"_id": tableValueSourceEntity.Id})
 
 	TableValueSourceEntityMongo := &TableValueSourceEntityMongo{
-		Id:        tableValueSourceEntity.Id,
-		Name:      tableValueSourceEntity.Name,
-		Data:      tableValueSourceEntity.Data,
-		Status:    tableValueSourceEntity.Status,
-		CreatedAt: tableValueSourceEntity.CreatedAt.Unix(),
-		UpdatedAt: tableValueSourceEntity.UpdatedAt.Unix(),
+		Id:          tableValueSourceEntity.Id,
+		HouseholdId: householdId,
+		Name:        tableValueSourceEntity.Name,
+		Data:        tableValueSourceEntity.Data,
+		Status:      tableValueSourceEntity.Status,
+		CreatedAt:   tableValueSourceEntity.CreatedAt.Unix(),
+		UpdatedAt:   tableValueSourceEntity.UpdatedAt.Unix(),
 	}
 
-	_, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": TableValueSourceEntityMongo})
-	if err != nil {
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": TableValueSourceEntityMongo}); err != nil {
 		logger.Error("Error trying to update tableValueSource", err)
 		return internal_error.NewInternalServerError("Error trying to update tableValueSource")
 	}
@@ -95,7 +153,7 @@ func (ur *TableValueSourceRepository) UpdateTableValueSource(
 
 func (ur *TableValueSourceRepository) FindTableValueSourceById(
 	ctx context.Context, tableValueSourceId string) (*table_value_source_entity.TableValueSource, *internal_error.InternalError) {
-	filter := bson.M{"_id": tableValueSourceId}
+	filter := household.ScopeFilter(ctx, bson.M{"_id": tableValueSourceId})
 
 	var tableValueSourceEntityMongo TableValueSourceEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&tableValueSourceEntityMongo)
@@ -126,7 +184,7 @@ func (repo *TableValueSourceRepository) FindTableValueSources(
 	ctx context.Context,
 	status table_value_source_entity.TableValueSourceStatus,
 	name string) ([]*table_value_source_entity.TableValueSource, *internal_error.InternalError) {
-	filter := bson.M{}
+	filter := household.ScopeFilter(ctx, bson.M{})
 
 	if status != 0 {
 		filter["status"] = status
@@ -163,3 +221,15 @@ func (repo *TableValueSourceRepository) FindTableValueSources(
 
 	return tableValueSourcesEntity, nil
 }
+
+func (ur *TableValueSourceRepository) DeleteTableValueSource(
+	ctx context.Context, tableValueSourceId string) *internal_error.InternalError {
+	filter := household.ScopeFilter(ctx, bson.M{"_id": tableValueSourceId})
+
+	if _, err := ur.Collection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to delete tableValueSource", err)
+		return internal_error.NewInternalServerError("Error trying to delete tableValueSource")
+	}
+
+	return nil
+}
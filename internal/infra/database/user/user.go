@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,12 +17,13 @@ import (
 )
 
 type UserEntityMongo struct {
-	Id        string                 `bson:"_id"`
-	Name      string                 `bson:"name"`
-	Email     string                 `bson:"email"`
-	Status    user_entity.UserStatus `bson:"status"`
-	CreatedAt int64                  `bson:"created_at"`
-	UpdatedAt int64                  `bson:"updated_at"`
+	Id           string                 `bson:"_id"`
+	Name         string                 `bson:"name"`
+	Email        string                 `bson:"email"`
+	Status       user_entity.UserStatus `bson:"status"`
+	PasswordHash string                 `bson:"password_hash,omitempty"`
+	CreatedAt    int64                  `bson:"created_at"`
+	UpdatedAt    int64                  `bson:"updated_at"`
 }
 
 type UserRepository struct {
@@ -52,16 +54,11 @@ func (ur *UserRepository) CreateUser(
 	ctx context.Context,
 	userEntity *user_entity.User) *internal_error.InternalError {
 
-	userEntityMongo := &UserEntityMongo{
-		Id:        userEntity.Id,
-		Name:      userEntity.Name,
-		Email:     userEntity.Email,
-		Status:    userEntity.Status,
-		CreatedAt: userEntity.CreatedAt.Unix(),
-		UpdatedAt: userEntity.UpdatedAt.Unix(),
-	}
-
-	if _, err := ur.Collection.InsertOne(ctx, userEntityMongo); err != nil {
+	if _, err := ur.Collection.InsertOne(ctx, toUserEntityMongo(userEntity)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError("There is already a user with this email",
+				internal_error.Cause{Field: "email", Message: "email already registered"})
+		}
 		logger.Error("Error trying to insert user", err)
 		return internal_error.NewInternalServerError("Error trying to insert user")
 	}
@@ -69,6 +66,20 @@ func (ur *UserRepository) CreateUser(
 	return nil
 }
 
+func (ur *UserRepository) UpdateUser(
+	ctx context.Context,
+	userEntity *user_entity.User) *internal_error.InternalError {
+
+	filter := bson.M{"_id": userEntity.Id}
+
+	if _, err := ur.Collection.UpdateOne(ctx, filter, bson.M{"$set": toUserEntityMongo(userEntity)}); err != nil {
+		logger.Error("Error trying to update user", err)
+		return internal_error.NewInternalServerError("Error trying to update user")
+	}
+
+	return nil
+}
+
 func (ur *UserRepository) FindUserById(
 	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
 	filter := bson.M{"_id": userId}
@@ -86,16 +97,51 @@ func (ur *UserRepository) FindUserById(
 		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
 	}
 
-	userEntity := &user_entity.User{
-		Id:        userEntityMongo.Id,
-		Name:      userEntityMongo.Name,
-		Email:     userEntityMongo.Email,
-		Status:    userEntityMongo.Status,
-		CreatedAt: time.Unix(userEntityMongo.CreatedAt, 0),
-		UpdatedAt: time.Unix(userEntityMongo.UpdatedAt, 0),
+	return toUserEntity(&userEntityMongo), nil
+}
+
+func (ur *UserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	filter := bson.M{"email": email}
+
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("User not found with this email = %s", email))
+		}
+
+		logger.Error("Error trying to find user by email", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find user by email")
 	}
 
-	return userEntity, nil
+	return toUserEntity(&userEntityMongo), nil
+}
+
+func (repo *UserRepository) FindUsersByIds(
+	ctx context.Context, userIds []string) ([]*user_entity.User, *internal_error.InternalError) {
+	filter := bson.M{"_id": bson.M{"$in": userIds}}
+
+	cursor, err := repo.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding users by ids", err)
+		return nil, internal_error.NewInternalServerError("Error finding users by ids")
+	}
+	defer cursor.Close(ctx)
+
+	var usersMongo []UserEntityMongo
+	if err := cursor.All(ctx, &usersMongo); err != nil {
+		logger.Error("Error decoding users", err)
+		return nil, internal_error.NewInternalServerError("Error decoding users")
+	}
+
+	usersEntity := make([]*user_entity.User, len(usersMongo))
+	for i := range usersMongo {
+		usersEntity[i] = toUserEntity(&usersMongo[i])
+	}
+
+	return usersEntity, nil
 }
 
 func (repo *UserRepository) FindUsers(
@@ -105,6 +151,13 @@ func (repo *UserRepository) FindUsers(
 	email string) ([]*user_entity.User, *internal_error.InternalError) {
 	filter := bson.M{}
 
+	// requests bound to a household only see its members, and other requests see none
+	if scope, found := household_entity.ScopeFromContext(ctx); found {
+		filter["_id"] = bson.M{"$in": scope.MemberIds}
+	} else if !household_entity.IsUnscoped(ctx) {
+		filter["_id"] = bson.M{"$in": bson.A{}}
+	}
+
 	if status != 0 {
 		filter["status"] = status
 	}
@@ -131,16 +184,33 @@ func (repo *UserRepository) FindUsers(
 	}
 
 	usersEntity := make([]*user_entity.User, len(usersMongo))
-	for i, user := range usersMongo {
-		usersEntity[i] = &user_entity.User{
-			Id:        user.Id,
-			Name:      user.Name,
-			Email:     user.Email,
-			Status:    user.Status,
-			CreatedAt: time.Unix(user.CreatedAt, 0),
-			UpdatedAt: time.Unix(user.UpdatedAt, 0),
-		}
+	for i := range usersMongo {
+		usersEntity[i] = toUserEntity(&usersMongo[i])
 	}
 
 	return usersEntity, nil
 }
+
+func toUserEntityMongo(userEntity *user_entity.User) *UserEntityMongo {
+	return &UserEntityMongo{
+		Id:           userEntity.Id,
+		Name:         userEntity.Name,
+		Email:        userEntity.Email,
+		Status:       userEntity.Status,
+		PasswordHash: userEntity.PasswordHash,
+		CreatedAt:    userEntity.CreatedAt.Unix(),
+		UpdatedAt:    userEntity.UpdatedAt.Unix(),
+	}
+}
+
+func toUserEntity(userEntityMongo *UserEntityMongo) *user_entity.User {
+	return &user_entity.User{
+		Id:           userEntityMongo.Id,
+		Name:         userEntityMongo.Name,
+		Email:        userEntityMongo.Email,
+		Status:       userEntityMongo.Status,
+		PasswordHash: userEntityMongo.PasswordHash,
+		CreatedAt:    time.Unix(userEntityMongo.CreatedAt, 0),
+		UpdatedAt:    time.Unix(userEntityMongo.UpdatedAt, 0),
+	}
+}
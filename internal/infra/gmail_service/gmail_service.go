@@ -3,94 +3,241 @@ package gmail_service
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"sync"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/oauth_token_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/option"
 )
 
-// Retrieve a token, saves the token, then returns the generated client.
-func getClient(config *oauth2.Config) *http.Client {
-	// The file token.json stores the user's access and refresh tokens, and is
-	// created automatically when the authorization flow completes for the first
-	// time.
-	tokFile := "configuration/gmail_service/token.json"
-	tok, err := tokenFromFile(tokFile)
+const (
+	GMAIL_CREDENTIALS_FILE   = "GMAIL_CREDENTIALS_FILE"
+	GMAIL_OAUTH_REDIRECT_URL = "GMAIL_OAUTH_REDIRECT_URL"
+	GMAIL_LEGACY_TOKEN_FILE  = "GMAIL_LEGACY_TOKEN_FILE"
+
+	DEFAULT_CREDENTIALS_FILE   = "configuration/gmail_service/credentials.json"
+	DEFAULT_OAUTH_REDIRECT_URL = "http://localhost:8080/auth/gmail/callback"
+	DEFAULT_LEGACY_TOKEN_FILE  = "configuration/gmail_service/token.json"
+
+	// token of the mailbox connected before mail accounts existed
+	DEFAULT_TOKEN_ID = "gmail"
+)
+
+// GmailService creates the gmail clients of every connected mailbox. Tokens are stored
+// under the mail account id, or under DEFAULT_TOKEN_ID for the legacy mailbox.
+type GmailService struct {
+	config          *oauth2.Config
+	tokenRepository oauth_token_entity.OAuthTokenRepositoryInterface
+	mutex           sync.Mutex
+	services        map[string]*gmail.Service
+}
+
+func NewGmailService(ctx context.Context, tokenRepository oauth_token_entity.OAuthTokenRepositoryInterface) (*GmailService, error) {
+	b, err := os.ReadFile(getEnv(GMAIL_CREDENTIALS_FILE, DEFAULT_CREDENTIALS_FILE))
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(tokFile, tok)
+		log.Printf("Unable to read client secret file: %v", err)
+		return nil, err
 	}
-	return config.Client(context.Background(), tok)
+
+	// If modifying these scopes, the mailbox must be connected again.
+	config, err := google.ConfigFromJSON(b, gmail.GmailReadonlyScope)
+	if err != nil {
+		log.Printf("Unable to parse client secret file to config: %v", err)
+		return nil, err
+	}
+	config.RedirectURL = getEnv(GMAIL_OAUTH_REDIRECT_URL, DEFAULT_OAUTH_REDIRECT_URL)
+
+	gmailService := &GmailService{
+		config:          config,
+		tokenRepository: tokenRepository,
+		services:        make(map[string]*gmail.Service),
+	}
+
+	gmailService.importLegacyTokenFile(ctx)
+
+	return gmailService, nil
+}
+
+// AuthCodeURL returns the consent page url the user must visit to connect the mailbox.
+func (s *GmailService) AuthCodeURL(state string) string {
+	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
 }
 
-// Request a token from the web, then returns the retrieved token.
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the "+
-		"authorization code: \n%v\n", authURL)
+// Connect exchanges the authorization code received on the oauth callback, stores the token
+// for the account and returns the address of the authorized mailbox.
+func (s *GmailService) Connect(ctx context.Context, accountId string, code string) (string, *internal_error.InternalError) {
+	tok, err := s.config.Exchange(ctx, code)
+	if err != nil {
+		log.Printf("Unable to retrieve token from web: %v", err)
+		return "", internal_error.NewBadRequestError("Unable to exchange authorization code")
+	}
 
-	var authCode string
-	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("Unable to read authorization code: %v", err)
+	if err := s.saveToken(ctx, tokenId(accountId), tok); err != nil {
+		return "", err
 	}
 
-	tok, err := config.Exchange(context.TODO(), authCode)
+	s.mutex.Lock()
+	delete(s.services, tokenId(accountId))
+	s.mutex.Unlock()
+
+	srv, e := s.GetGmailService(ctx, accountId)
+	if e != nil {
+		return "", e
+	}
+
+	profile, err := srv.Users.GetProfile("me").Do()
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web: %v", err)
+		log.Printf("Unable to get Gmail profile: %v", err)
+		return "", internal_error.NewInternalServerError("Unable to get Gmail profile")
 	}
-	return tok
+
+	log.Println("Gmail account connected:", profile.EmailAddress)
+
+	return profile.EmailAddress, nil
 }
 
-// Retrieves a token from a local file.
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
+func (s *GmailService) IsConnected(ctx context.Context, accountId string) bool {
+	_, err := s.tokenRepository.FindOAuthTokenById(ctx, tokenId(accountId))
+	return err == nil
+}
+
+func (s *GmailService) GetGmailService(ctx context.Context, accountId string) (*gmail.Service, *internal_error.InternalError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := tokenId(accountId)
+
+	if srv, found := s.services[id]; found {
+		return srv, nil
+	}
+
+	tokenEntity, err := s.tokenRepository.FindOAuthTokenById(ctx, id)
 	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("Gmail account not connected")
+		}
 		return nil, err
 	}
-	defer f.Close()
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
+
+	tok := &oauth2.Token{
+		AccessToken:  tokenEntity.AccessToken,
+		TokenType:    tokenEntity.TokenType,
+		RefreshToken: tokenEntity.RefreshToken,
+		Expiry:       tokenEntity.Expiry,
+	}
+
+	tokenSource := &persistingTokenSource{
+		source:    s.config.TokenSource(context.Background(), tok),
+		service:   s,
+		tokenId:   id,
+		lastToken: tok,
+	}
+
+	client := oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(tok, tokenSource))
+
+	srv, e := gmail.NewService(ctx, option.WithHTTPClient(client))
+	if e != nil {
+		log.Printf("Unable to retrieve Gmail client: %v", e)
+		return nil, internal_error.NewInternalServerError("Unable to retrieve Gmail client")
+	}
+
+	s.services[id] = srv
+
+	return srv, nil
 }
 
-// Saves a token to a file path.
-func saveToken(path string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+func (s *GmailService) saveToken(ctx context.Context, id string, tok *oauth2.Token) *internal_error.InternalError {
+	tokenEntity, err := s.tokenRepository.FindOAuthTokenById(ctx, id)
 	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
+		if err.Err != "not_found" {
+			return err
+		}
+
+		tokenEntity, err = oauth_token_entity.CreateOAuthToken(id, oauth_token_entity.Gmail,
+			tok.AccessToken, tok.TokenType, tok.RefreshToken, tok.Expiry)
+		if err != nil {
+			return err
+		}
+	} else if err := tokenEntity.Update(tok.AccessToken, tok.TokenType, tok.RefreshToken, tok.Expiry); err != nil {
+		return err
 	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
+
+	return s.tokenRepository.SaveOAuthToken(ctx, tokenEntity)
 }
 
-func NewGmailService() (*gmail.Service, error) {
-	ctx := context.Background()
-	b, err := os.ReadFile("configuration/gmail_service/credentials.json")
-	if err != nil {
-		log.Fatalf("Unable to read client secret file: %v", err)
-		return nil, err
+// Imports the token.json written by previous versions, so an already connected mailbox keeps working.
+func (s *GmailService) importLegacyTokenFile(ctx context.Context) {
+	if s.IsConnected(ctx, "") {
+		return
 	}
 
-	// If modifying these scopes, delete your previously saved token.json.
-	config, err := google.ConfigFromJSON(b, gmail.GmailReadonlyScope)
+	tokenFile := getEnv(GMAIL_LEGACY_TOKEN_FILE, DEFAULT_LEGACY_TOKEN_FILE)
+
+	content, err := os.ReadFile(tokenFile)
 	if err != nil {
-		log.Fatalf("Unable to parse client secret file to config: %v", err)
-		return nil, err
+		return
+	}
+
+	tok := &oauth2.Token{}
+	if err := json.Unmarshal(content, tok); err != nil {
+		log.Printf("Unable to read legacy token file: %v", err)
+		return
 	}
 
-	client := getClient(config)
+	if err := s.saveToken(ctx, DEFAULT_TOKEN_ID, tok); err != nil {
+		log.Printf("Unable to import legacy token file: %v", err)
+		return
+	}
 
-	srv, err := gmail.NewService(ctx, option.WithHTTPClient(client))
+	// the token is kept in Mongo from now on, so the plaintext copy must not stay around
+	importedFile := tokenFile + ".imported"
+	if err := os.Rename(tokenFile, importedFile); err != nil {
+		log.Printf("Legacy Gmail token file imported, but unable to rename it: %v", err)
+		return
+	}
+
+	log.Printf("Legacy Gmail token file imported and renamed to %s", importedFile)
+}
+
+// persistingTokenSource stores the token again every time it is refreshed.
+type persistingTokenSource struct {
+	source    oauth2.TokenSource
+	service   *GmailService
+	tokenId   string
+	lastToken *oauth2.Token
+}
+
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := p.source.Token()
 	if err != nil {
-		log.Fatalf("Unable to retrieve Gmail client: %v", err)
 		return nil, err
 	}
 
-	return srv, nil
+	if tok.AccessToken != p.lastToken.AccessToken {
+		if err := p.service.saveToken(context.Background(), p.tokenId, tok); err != nil {
+			log.Printf("Unable to save refreshed token: %v", err)
+		}
+		p.lastToken = tok
+	}
+
+	return tok, nil
+}
+
+func tokenId(accountId string) string {
+	if accountId == "" {
+		return DEFAULT_TOKEN_ID
+	}
+	return accountId
+}
+
+func getEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
 }
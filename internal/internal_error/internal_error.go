@@ -3,6 +3,13 @@ package internal_error
 type InternalError struct {
 	Message string
 	Err     string
+	Causes  []Cause
+}
+
+// Cause is an invalid field of the input, named as in the API (e.g. "dueDay", "data[0].period").
+type Cause struct {
+	Field   string
+	Message string
 }
 
 func (ie *InternalError) Error() string {
@@ -23,9 +30,24 @@ func NewInternalServerError(message string) *InternalError {
 	}
 }
 
-func NewBadRequestError(message string) *InternalError {
+func NewBadRequestError(message string, causes ...Cause) *InternalError {
 	return &InternalError{
 		Message: message,
 		Err:     "bad_request",
+		Causes:  causes,
+	}
+}
+
+func NewForbiddenError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "forbidden",
+	}
+}
+
+func NewUnauthorizedError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "unauthorized",
 	}
 }
@@ -0,0 +1,205 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in BRL stored as integer cents, so sums and comparisons are exact.
+// It is stored in MongoDB as an int64 and serialized to JSON as a decimal number (60.50).
+type Money int64
+
+// FromCents returns the amount of the given cents.
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// FromFloat converts an amount in reais, rounding to the nearest cent.
+func FromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Percentage returns percentage% of the amount, rounded to the nearest cent.
+func (m Money) Percentage(percentage float64) Money {
+	return Money(math.Round(float64(m) * percentage / 100))
+}
+
+// Allocate splits the amount proportionally to the weights. The cents left by rounding go to
+// the first parts, so the parts always add up to the amount. With no positive weight the amount
+// is split equally. Negative amounts are split as their absolute value.
+func (m Money) Allocate(weights []float64) []Money {
+	if m < 0 {
+		parts := (-m).Allocate(weights)
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+		return parts
+	}
+
+	parts := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	var allocated Money
+	for i, weight := range weights {
+		if total > 0 {
+			parts[i] = Money(math.Round(float64(m) * weight / total))
+		} else {
+			parts[i] = Money(math.Round(float64(m) / float64(len(weights))))
+		}
+		allocated += parts[i]
+	}
+
+	// the cents missing are added from the first part on and those rounded up beyond the amount
+	// are taken from the last part back, so the first parts keep the larger values either way
+	for i := 0; allocated < m; i = (i + 1) % len(parts) {
+		parts[i]++
+		allocated++
+	}
+	for i := len(parts) - 1; allocated > m; i = (i + len(parts) - 1) % len(parts) {
+		parts[i]--
+		allocated--
+	}
+
+	return parts
+}
+
+// String formats the amount as BRL, e.g. "R$ 1.234,56".
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+
+	cents := m.Abs().Cents()
+	reais := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range reais {
+		if i > 0 && (len(reais)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
+
+// decimal formats the amount with a dot and two decimal places, e.g. "1234.56".
+func (m Money) decimal() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+	cents := m.Abs().Cents()
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.decimal()), nil
+}
+
+// UnmarshalJSON accepts a number (60.5) or a string in either format ("60.50", "R$ 60,50").
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if !strings.HasPrefix(string(data), `"`) {
+		amount, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %s", data)
+		}
+		*m = FromFloat(amount)
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Parse reads amounts as written in Brazil ("1.234,56", "R$ 60,50") or with a decimal point
+// ("1234.56"). A single dot followed by three digits is a thousands separator ("1.234").
+func Parse(value string) (Money, error) {
+	original := value
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimSpace(strings.TrimPrefix(value, "-"))
+	value = strings.TrimSpace(strings.TrimPrefix(value, "R$"))
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = strings.TrimSpace(strings.TrimPrefix(value, "-"))
+	}
+	value = strings.ReplaceAll(value, " ", "")
+
+	integer, fraction := value, ""
+	if index := strings.LastIndex(value, ","); index >= 0 {
+		integer, fraction = value[:index], value[index+1:]
+		integer = strings.ReplaceAll(integer, ".", "")
+	} else if index := strings.LastIndex(value, "."); index >= 0 &&
+		!(strings.Count(value, ".") > 1 || len(value)-index-1 == 3) {
+		integer, fraction = value[:index], value[index+1:]
+	} else {
+		integer = strings.ReplaceAll(integer, ".", "")
+	}
+
+	if integer == "" {
+		integer = "0"
+	}
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: more than two decimal places", original)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	reais, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", original)
+	}
+	cents, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || strings.ContainsAny(fraction, "+-") {
+		return 0, fmt.Errorf("invalid amount %q", original)
+	}
+
+	amount := Money(reais*100 + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,86 @@
+package money
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{value: "1.234,56", want: 123456},
+		{value: "R$ 60,50", want: 6050},
+		{value: "R$60,5", want: 6050},
+		{value: " 0,01 ", want: 1},
+		{value: ",50", want: 50},
+		{value: "1234.56", want: 123456},
+		{value: "1.5", want: 150},
+		{value: "1.234", want: 123400},
+		{value: "1.234.567", want: 123456700},
+		{value: "1 234,56", want: 123456},
+		{value: "-R$ 10,00", want: -1000},
+		{value: "R$ -10,00", want: -1000},
+		{value: "-1.234,56", want: -123456},
+		{value: "", want: 0},
+		{value: "1,234", wantErr: true},
+		{value: "1,+5", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "R$ 10,0a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %d, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %s", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []float64
+		want    []Money
+	}{
+		{name: "proportional", amount: 1000, weights: []float64{70, 30}, want: []Money{700, 300}},
+		{name: "remainder to the first part", amount: 100, weights: []float64{1, 1, 1}, want: []Money{34, 33, 33}},
+		{name: "rounded parts", amount: 10001, weights: []float64{1, 2}, want: []Money{3334, 6667}},
+		{name: "odd cent to the first part", amount: 10001, weights: []float64{1, 1}, want: []Money{5001, 5000}},
+		{name: "rounded up beyond the amount", amount: 1, weights: []float64{1, 1}, want: []Money{1, 0}},
+		{name: "negative amount", amount: -100, weights: []float64{1, 1, 1}, want: []Money{-34, -33, -33}},
+		{name: "no positive weight", amount: 1000, weights: []float64{0, 0}, want: []Money{500, 500}},
+		{name: "no weights", amount: 1000, weights: []float64{}, want: []Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Allocate(tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate(%v) = %v, want %v", tt.weights, got, tt.want)
+			}
+
+			var sum Money
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Allocate(%v) = %v, want %v", tt.weights, got, tt.want)
+				}
+				sum += got[i]
+			}
+			if len(got) > 0 && sum != tt.amount {
+				t.Errorf("Allocate(%v) parts add up to %d, want %d", tt.weights, sum, tt.amount)
+			}
+		})
+	}
+}
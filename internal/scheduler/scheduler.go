@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	DAILY_JOBS_HOUR = "DAILY_JOBS_HOUR"
+
+	DEFAULT_DAILY_JOBS_HOUR = 6
+)
+
+// HouseholdJob runs for one household, with the context bound to it.
+type HouseholdJob func(ctx context.Context) *internal_error.InternalError
+
+type HouseholdScopesProviderInterface interface {
+	HouseholdScopes(ctx context.Context) ([]household_entity.Scope, *internal_error.InternalError)
+}
+
+// Scheduler runs the daily jobs of every household: once at startup and then every day at
+// DAILY_JOBS_HOUR (0 to 23, 6 by default), local time.
+type Scheduler struct {
+	householdScopesProvider HouseholdScopesProviderInterface
+	hour                    int
+}
+
+func NewScheduler(householdScopesProvider HouseholdScopesProviderInterface) *Scheduler {
+	hour := DEFAULT_DAILY_JOBS_HOUR
+	if value, err := strconv.Atoi(os.Getenv(DAILY_JOBS_HOUR)); err == nil && value >= 0 && value < 24 {
+		hour = value
+	}
+
+	return &Scheduler{
+		householdScopesProvider: householdScopesProvider,
+		hour:                    hour,
+	}
+}
+
+// Daily starts running the job in background until the context is done.
+func (s *Scheduler) Daily(ctx context.Context, name string, job HouseholdJob) {
+	go func() {
+		for {
+			s.runForEachHousehold(ctx, name, job)
+
+			timer := time.NewTimer(time.Until(s.nextRun(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+}
+
+// runForEachHousehold binds the context to each household, as the repositories only save the
+// household of records changed within a household context.
+func (s *Scheduler) runForEachHousehold(ctx context.Context, name string, job HouseholdJob) {
+	scopes, err := s.householdScopesProvider.HouseholdScopes(ctx)
+	if err != nil {
+		log.Printf("Error trying to find the households to run %s: %v", name, err)
+		return
+	}
+
+	for _, scope := range scopes {
+		if err := job(household_entity.WithScope(ctx, scope)); err != nil {
+			log.Printf("Error running %s for household %s: %v", name, scope.HouseholdId, err)
+		}
+	}
+}
+
+func (s *Scheduler) nextRun(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,198 @@
+package secret_service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	SECRETS_MASTER_KEY      = "SECRETS_MASTER_KEY"
+	SECRETS_MASTER_KEY_FILE = "SECRETS_MASTER_KEY_FILE"
+	SECRETS_PREVIOUS_KEYS   = "SECRETS_PREVIOUS_KEYS"
+
+	DEFAULT_MASTER_KEY_FILE = "configuration/secrets/master.key"
+
+	// encrypted values are stored as enc:v1:<key id>:<base64(nonce + ciphertext)>
+	CIPHERTEXT_PREFIX = "enc:v1:"
+	KEY_SIZE          = 32
+)
+
+type SecretServiceInterface interface {
+	Encrypt(plaintext string) (string, *internal_error.InternalError)
+	Decrypt(ciphertext string) (string, *internal_error.InternalError)
+	// IsCurrent reports whether the value is encrypted under the current master key
+	IsCurrent(ciphertext string) bool
+}
+
+type SecretService struct {
+	currentKeyId string
+	keys         map[string]cipher.AEAD
+}
+
+// NewSecretService loads the master key from SECRETS_MASTER_KEY or from the key file
+// (generating it on first use) and the keys being rotated out from SECRETS_PREVIOUS_KEYS.
+func NewSecretService() (*SecretService, error) {
+	masterKey, err := loadMasterKey()
+	if err != nil {
+		return nil, err
+	}
+
+	previousKeys := make([][]byte, 0)
+	for _, encoded := range strings.Split(os.Getenv(SECRETS_PREVIOUS_KEYS), ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		previousKeys = append(previousKeys, key)
+	}
+
+	return NewSecretServiceFromKeys(masterKey, previousKeys...)
+}
+
+func NewSecretServiceFromKeys(masterKey []byte, previousKeys ...[]byte) (*SecretService, error) {
+	secretService := &SecretService{
+		keys: make(map[string]cipher.AEAD),
+	}
+
+	for _, key := range append(previousKeys, masterKey) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		secretService.keys[keyId(key)] = aead
+	}
+
+	secretService.currentKeyId = keyId(masterKey)
+
+	return secretService, nil
+}
+
+func (s *SecretService) Encrypt(plaintext string) (string, *internal_error.InternalError) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := s.keys[s.currentKeyId]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		log.Printf("Error generating nonce: %v", err)
+		return "", internal_error.NewInternalServerError("Error trying to encrypt secret")
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return CIPHERTEXT_PREFIX + s.currentKeyId + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns values stored before encryption was enabled unchanged.
+func (s *SecretService) Decrypt(ciphertext string) (string, *internal_error.InternalError) {
+	if !strings.HasPrefix(ciphertext, CIPHERTEXT_PREFIX) {
+		return ciphertext, nil
+	}
+
+	id, encoded, found := strings.Cut(strings.TrimPrefix(ciphertext, CIPHERTEXT_PREFIX), ":")
+	if !found {
+		return "", internal_error.NewInternalServerError("Invalid encrypted secret")
+	}
+
+	aead, found := s.keys[id]
+	if !found {
+		log.Printf("Secret encrypted with unknown key %s", id)
+		return "", internal_error.NewInternalServerError("Secret encrypted with unknown key")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", internal_error.NewInternalServerError("Invalid encrypted secret")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		log.Printf("Error decrypting secret: %v", err)
+		return "", internal_error.NewInternalServerError("Error trying to decrypt secret")
+	}
+
+	return string(plaintext), nil
+}
+
+func (s *SecretService) IsCurrent(ciphertext string) bool {
+	if ciphertext == "" {
+		return true
+	}
+	return strings.HasPrefix(ciphertext, CIPHERTEXT_PREFIX+s.currentKeyId+":")
+}
+
+func loadMasterKey() ([]byte, error) {
+	if encoded := os.Getenv(SECRETS_MASTER_KEY); encoded != "" {
+		return decodeKey(encoded)
+	}
+
+	keyFile := os.Getenv(SECRETS_MASTER_KEY_FILE)
+	if keyFile == "" {
+		keyFile = DEFAULT_MASTER_KEY_FILE
+	}
+
+	content, err := os.ReadFile(keyFile)
+	if err == nil {
+		return decodeKey(strings.TrimSpace(string(content)))
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log.Printf("Master key file %s not found. Generating a new master key", keyFile)
+
+	key := make([]byte, KEY_SIZE)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// decodeKey accepts 32 byte keys encoded in base64 or hex.
+func decodeKey(encoded string) ([]byte, error) {
+	if key, err := base64.StdEncoding.DecodeString(encoded); err == nil && len(key) == KEY_SIZE {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(encoded); err == nil && len(key) == KEY_SIZE {
+		return key, nil
+	}
+	return nil, errors.New("invalid secrets key: expected 32 bytes encoded in base64 or hex")
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func keyId(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
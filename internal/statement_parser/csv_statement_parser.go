@@ -0,0 +1,144 @@
+package statement_parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// CsvStatementParser reads the CSV statements exported by Brazilian banks: ";" or ","
+// separated, dates as dd/mm/yyyy and amounts as 1.234,56. Columns are found by the header
+// names (data, descrição/histórico/lançamento, valor).
+type CsvStatementParser struct{}
+
+var (
+	csvDateColumns        = []string{"data", "data lancamento", "data do lancamento", "data movimento"}
+	csvDescriptionColumns = []string{"descricao", "historico", "lancamento", "estabelecimento", "memo"}
+	csvAmountColumns      = []string{"valor", "valor (r$)", "valor r$", "montante"}
+	csvIdColumns          = []string{"id", "identificador", "documento", "n documento", "numero documento"}
+)
+
+func NewCsvStatementParser() *CsvStatementParser {
+	return &CsvStatementParser{}
+}
+
+func (p *CsvStatementParser) Parse(content string) ([]*StatementTransaction, *internal_error.InternalError) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = detectSeparator(content)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, internal_error.NewBadRequestError("Invalid CSV statement")
+	}
+
+	// banks add account information lines before the header
+	headerIndex := -1
+	var dateColumn, descriptionColumn, amountColumn, idColumn int
+	for i, record := range records {
+		dateColumn = findColumn(record, csvDateColumns)
+		descriptionColumn = findColumn(record, csvDescriptionColumns)
+		amountColumn = findColumn(record, csvAmountColumns)
+		idColumn = findColumn(record, csvIdColumns)
+		if dateColumn >= 0 && descriptionColumn >= 0 && amountColumn >= 0 {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, internal_error.NewBadRequestError("Invalid CSV statement. Columns data, descrição and valor not found")
+	}
+
+	transactions := make([]*StatementTransaction, 0)
+	occurrences := make(map[string]int)
+
+	for i, record := range records[headerIndex+1:] {
+		if len(record) <= max(dateColumn, descriptionColumn, amountColumn) {
+			continue
+		}
+
+		date, e := time.Parse("02/01/2006", strings.TrimSpace(record[dateColumn]))
+		if e != nil || strings.TrimSpace(record[amountColumn]) == "" {
+			continue // balance and summary lines
+		}
+
+		amount, e := parseBrazilianAmount(record[amountColumn])
+		if e != nil {
+			return nil, internal_error.NewBadRequestError(fmt.Sprintf("Invalid CSV amount on line %d: %s", headerIndex+i+2, record[amountColumn]))
+		}
+
+		id := ""
+		if idColumn >= 0 && idColumn < len(record) {
+			id = strings.TrimSpace(record[idColumn])
+		}
+		description := strings.TrimSpace(record[descriptionColumn])
+		if id == "" {
+			// identical debits on the same day (e.g. two equal Pix) are told apart by their
+			// occurrence, which stays the same when the statement is imported again
+			id = fmt.Sprintf("%s|%.2f|%s", date.Format("2006-01-02"), amount.Float(), description)
+			if occurrences[id]++; occurrences[id] > 1 {
+				id = fmt.Sprintf("%s|%d", id, occurrences[id])
+			}
+		}
+
+		transactions = append(transactions, &StatementTransaction{
+			Id:          id,
+			Date:        date,
+			Amount:      amount,
+			Description: description,
+		})
+	}
+
+	return transactions, nil
+}
+
+func detectSeparator(content string) rune {
+	firstLines := content
+	if len(firstLines) > 2048 {
+		firstLines = firstLines[:2048]
+	}
+	if strings.Count(firstLines, ";") >= strings.Count(firstLines, ",") {
+		return ';'
+	}
+	return ','
+}
+
+func findColumn(record []string, names []string) int {
+	for i, column := range record {
+		normalized := NormalizeText(column)
+		for _, name := range names {
+			if normalized == NormalizeText(name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseBrazilianAmount reads amounts like "-1.234,56", "R$ 60,50" or "60,50 D" (debit).
+func parseBrazilianAmount(value string) (money.Money, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSpace(strings.TrimPrefix(value, "R$"))
+
+	negative := false
+	if strings.HasSuffix(value, "D") {
+		negative = true
+		value = strings.TrimSpace(strings.TrimSuffix(value, "D"))
+	} else if strings.HasSuffix(value, "C") {
+		value = strings.TrimSpace(strings.TrimSuffix(value, "C"))
+	}
+
+	amount, err := money.Parse(value)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,46 @@
+package statement_parser
+
+import (
+	"testing"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+func TestParseBrazilianAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    money.Money
+		wantErr bool
+	}{
+		{value: "60,50", want: 6050},
+		{value: "R$ 60,50", want: 6050},
+		{value: "r$ 60,5", want: 6050},
+		{value: "-1.234,56", want: -123456},
+		{value: "R$ -1.234,56", want: -123456},
+		{value: "1.234.567,89", want: 123456789},
+		{value: "60,50 D", want: -6050},
+		{value: "60,50D", want: -6050},
+		{value: "60,50 C", want: 6050},
+		{value: " 0,01 ", want: 1},
+		{value: "1,234", wantErr: true},
+		{value: "sessenta", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseBrazilianAmount(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseBrazilianAmount(%q) = %d, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBrazilianAmount(%q) returned error: %s", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseBrazilianAmount(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package statement_parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// OfxStatementParser reads the STMTTRN entries of OFX 1.x (SGML, closing tags optional)
+// and OFX 2.x (XML) statements.
+type OfxStatementParser struct{}
+
+var ofxTagRegex = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+func NewOfxStatementParser() *OfxStatementParser {
+	return &OfxStatementParser{}
+}
+
+func (p *OfxStatementParser) Parse(content string) ([]*StatementTransaction, *internal_error.InternalError) {
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, internal_error.NewBadRequestError("Invalid OFX statement")
+	}
+
+	transactions := make([]*StatementTransaction, 0)
+
+	var current map[string]string
+
+	for _, match := range ofxTagRegex.FindAllStringSubmatch(content, -1) {
+		closing := match[1] == "/"
+		tag := strings.ToUpper(match[2])
+		value := strings.TrimSpace(match[3])
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			current = make(map[string]string)
+		case tag == "STMTTRN" && closing:
+			if current != nil {
+				transaction, err := toStatementTransaction(current)
+				if err != nil {
+					return nil, err
+				}
+				transactions = append(transactions, transaction)
+			}
+			current = nil
+		case current != nil && !closing && value != "":
+			current[tag] = value
+		}
+	}
+
+	return transactions, nil
+}
+
+func toStatementTransaction(fields map[string]string) (*StatementTransaction, *internal_error.InternalError) {
+	date, err := parseOfxDate(fields["DTPOSTED"])
+	if err != nil {
+		return nil, internal_error.NewBadRequestError("Invalid OFX transaction date: " + fields["DTPOSTED"])
+	}
+
+	value, e := strconv.ParseFloat(strings.ReplaceAll(fields["TRNAMT"], ",", "."), 64)
+	if e != nil {
+		return nil, internal_error.NewBadRequestError("Invalid OFX transaction amount: " + fields["TRNAMT"])
+	}
+	amount := money.FromFloat(value)
+
+	description := strings.TrimSpace(fields["NAME"] + " " + fields["MEMO"])
+
+	return &StatementTransaction{
+		Id:          fields["FITID"],
+		Type:        fields["TRNTYPE"],
+		Date:        date,
+		Amount:      amount,
+		Description: description,
+	}, nil
+}
+
+// parseOfxDate reads dates like 20241010, 20241010120000 or 20241010120000[-3:BRT].
+func parseOfxDate(value string) (time.Time, error) {
+	if len(value) > 8 {
+		value = value[:8]
+	}
+	return time.Parse("20060102", value)
+}
@@ -0,0 +1,145 @@
+package statement_parser
+
+import (
+	"testing"
+	"time"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<BANKTRANLIST>
+<DTSTART>20241001
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20241010120000[-3:BRT]
+<TRNAMT>-150.75
+<FITID>2024101001
+<NAME>CORSAN
+<MEMO>PAGTO CONTA AGUA
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DIRECTDEBIT
+<DTPOSTED>20241015
+<TRNAMT>-0,29
+<FITID>2024101502
+<MEMO>DEB AUT CPFL
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+    <STMTTRN>
+      <TRNTYPE>CREDIT</TRNTYPE>
+      <DTPOSTED>20240229</DTPOSTED>
+      <TRNAMT>1200.10</TRNAMT>
+      <FITID>abc-1</FITID>
+      <NAME>SALARIO</NAME>
+      <MEMO></MEMO>
+    </STMTTRN>
+  </BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+func TestOfxStatementParserParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []StatementTransaction
+		wantErr bool
+	}{
+		{
+			name:    "SGML with unclosed tags",
+			content: sgmlStatement,
+			want: []StatementTransaction{
+				{
+					Id:          "2024101001",
+					Type:        "DEBIT",
+					Date:        time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC),
+					Amount:      -15075,
+					Description: "CORSAN PAGTO CONTA AGUA",
+				},
+				{
+					Id:          "2024101502",
+					Type:        "DIRECTDEBIT",
+					Date:        time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC),
+					Amount:      -29,
+					Description: "DEB AUT CPFL",
+				},
+			},
+		},
+		{
+			name:    "XML",
+			content: xmlStatement,
+			want: []StatementTransaction{
+				{
+					Id:          "abc-1",
+					Type:        "CREDIT",
+					Date:        time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+					Amount:      120010,
+					Description: "SALARIO",
+				},
+			},
+		},
+		{
+			name:    "lowercase tags",
+			content: "<ofx><stmttrn><trntype>DEBIT<dtposted>20241010<trnamt>-10<fitid>1</stmttrn></ofx>",
+			want: []StatementTransaction{
+				{Id: "1", Type: "DEBIT", Date: time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC), Amount: -1000},
+			},
+		},
+		{
+			name:    "no transactions",
+			content: "<OFX><BANKTRANLIST></BANKTRANLIST></OFX>",
+			want:    []StatementTransaction{},
+		},
+		{
+			name:    "not an OFX statement",
+			content: "data;descricao;valor",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			content: "<OFX><STMTTRN><DTPOSTED>10/10/2024<TRNAMT>-10</STMTTRN></OFX>",
+			wantErr: true,
+		},
+		{
+			name:    "invalid amount",
+			content: "<OFX><STMTTRN><DTPOSTED>20241010<TRNAMT>dez</STMTTRN></OFX>",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOfxStatementParser().Parse(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() returned error: %s", err.Message)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() returned %d transactions, want %d", len(got), len(tt.want))
+			}
+			for i, transaction := range got {
+				want := tt.want[i]
+				if transaction.Id != want.Id || transaction.Type != want.Type || !transaction.Date.Equal(want.Date) ||
+					transaction.Amount != want.Amount || transaction.Description != want.Description {
+					t.Errorf("transaction %d = %+v, want %+v", i, *transaction, want)
+				}
+			}
+		})
+	}
+}
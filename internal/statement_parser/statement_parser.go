@@ -0,0 +1,83 @@
+package statement_parser
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// StatementTransaction is a bank statement entry. Debits have negative amounts.
+type StatementTransaction struct {
+	Id          string
+	Type        string
+	Date        time.Time
+	Amount      money.Money
+	Description string
+}
+
+type StatementFormat uint8
+
+const (
+	OFX StatementFormat = iota + 1
+	CSV
+)
+
+func (f StatementFormat) Name() string {
+	return statementFormatNames[f]
+}
+
+var statementFormatNames = []string{
+	"",
+	"ofx",
+	"csv",
+}
+
+func GetStatementFormatByName(name string) (StatementFormat, *internal_error.InternalError) {
+	for k, v := range statementFormatNames {
+		if v == name {
+			return StatementFormat(k), nil
+		}
+	}
+
+	return StatementFormat(0), internal_error.NewBadRequestError("invalid statement format name")
+}
+
+type StatementParserInterface interface {
+	Parse(content string) ([]*StatementTransaction, *internal_error.InternalError)
+}
+
+func NewStatementParser(format StatementFormat) StatementParserInterface {
+	switch format {
+	case OFX:
+		return NewOfxStatementParser()
+	case CSV:
+		return NewCsvStatementParser()
+	default:
+		return nil
+	}
+}
+
+// ReadStatement reads the statement content, converting it from Windows-1252, the usual charset
+// of Brazilian bank exports, when it is not valid UTF-8. Windows-1252 is a superset of the
+// printable ISO-8859-1 characters, so it reads both.
+func ReadStatement(reader io.Reader) (string, *internal_error.InternalError) {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return "", internal_error.NewBadRequestError("Error trying to read statement")
+	}
+
+	if utf8.Valid(b) {
+		return strings.TrimPrefix(string(b), "\uFEFF"), nil
+	}
+
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(b)
+	if err != nil {
+		return "", internal_error.NewBadRequestError("Error trying to decode statement")
+	}
+	return string(decoded), nil
+}
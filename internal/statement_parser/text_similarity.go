@@ -0,0 +1,29 @@
+package statement_parser
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText lowercases the text and removes accents and punctuation, so
+// "Companhia Riograndense de Saneamento" and "CIA RIOGRANDENSE SANEAMENTO" can be compared.
+func NormalizeText(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+
+	result = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, result)
+
+	return strings.Join(strings.Fields(result), " ")
+}
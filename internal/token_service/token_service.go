@@ -0,0 +1,146 @@
+package token_service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	AUTH_TOKEN_KEY             = "AUTH_TOKEN_KEY"
+	AUTH_TOKEN_KEY_FILE        = "AUTH_TOKEN_KEY_FILE"
+	AUTH_ACCESS_TOKEN_DURATION = "AUTH_ACCESS_TOKEN_DURATION"
+
+	DEFAULT_TOKEN_KEY_FILE        = "configuration/secrets/auth_token.key"
+	DEFAULT_ACCESS_TOKEN_DURATION = 15 * time.Minute
+	KEY_SIZE                      = 32
+)
+
+type TokenServiceInterface interface {
+	// CreateAccessToken signs a JWT identifying the user, valid until the returned time
+	CreateAccessToken(userId string) (string, time.Time, *internal_error.InternalError)
+	// ValidateAccessToken returns the user identified by a JWT signed by the service and not expired
+	ValidateAccessToken(token string) (string, *internal_error.InternalError)
+}
+
+type TokenService struct {
+	key      []byte
+	duration time.Duration
+}
+
+// NewTokenService loads the signing key from AUTH_TOKEN_KEY or from the key file (generating it on
+// first use). The access tokens last AUTH_ACCESS_TOKEN_DURATION, 15 minutes by default.
+func NewTokenService() (*TokenService, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	duration := DEFAULT_ACCESS_TOKEN_DURATION
+	if value, err := time.ParseDuration(os.Getenv(AUTH_ACCESS_TOKEN_DURATION)); err == nil && value > 0 {
+		duration = value
+	}
+
+	return NewTokenServiceFromKey(key, duration), nil
+}
+
+func NewTokenServiceFromKey(key []byte, duration time.Duration) *TokenService {
+	return &TokenService{
+		key:      key,
+		duration: duration,
+	}
+}
+
+func (s *TokenService) CreateAccessToken(userId string) (string, time.Time, *internal_error.InternalError) {
+	now := time.Now()
+	expiresAt := now.Add(s.duration)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userId,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		ID:        uuid.New().String(),
+	}).SignedString(s.key)
+	if err != nil {
+		log.Printf("Error signing access token: %v", err)
+		return "", time.Time{}, internal_error.NewInternalServerError("Error trying to create access token")
+	}
+
+	return token, expiresAt, nil
+}
+
+// ValidateAccessToken only accepts tokens signed with HS256, so a token with another algorithm
+// (such as "none") is rejected before its signature is checked.
+func (s *TokenService) ValidateAccessToken(token string) (string, *internal_error.InternalError) {
+	var tokenClaims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &tokenClaims,
+		func(*jwt.Token) (interface{}, error) { return s.key, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return "", internal_error.NewUnauthorizedError("expired access token")
+		}
+		return "", internal_error.NewUnauthorizedError("invalid access token")
+	}
+
+	if tokenClaims.Subject == "" {
+		return "", internal_error.NewUnauthorizedError("invalid access token")
+	}
+
+	return tokenClaims.Subject, nil
+}
+
+func loadKey() ([]byte, error) {
+	if encoded := os.Getenv(AUTH_TOKEN_KEY); encoded != "" {
+		return decodeKey(encoded)
+	}
+
+	keyFile := os.Getenv(AUTH_TOKEN_KEY_FILE)
+	if keyFile == "" {
+		keyFile = DEFAULT_TOKEN_KEY_FILE
+	}
+
+	content, err := os.ReadFile(keyFile)
+	if err == nil {
+		return decodeKey(strings.TrimSpace(string(content)))
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log.Printf("Token key file %s not found. Generating a new token key", keyFile)
+
+	key := make([]byte, KEY_SIZE)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) < KEY_SIZE {
+		return nil, errors.New("invalid token key: expected at least 32 bytes encoded in base64")
+	}
+	return key, nil
+}
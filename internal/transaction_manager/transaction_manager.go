@@ -0,0 +1,85 @@
+package transaction_manager
+
+import (
+	"context"
+	"errors"
+
+	"github.com/regismartiny/lembrador-contas-go/configuration/logger"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TransactionManagerInterface runs a group of repository operations atomically. The repositories
+// join the transaction through the context passed to fn, so fn must use it in every call.
+type TransactionManagerInterface interface {
+	WithTransaction(
+		ctx context.Context,
+		fn func(ctx context.Context) *internal_error.InternalError) *internal_error.InternalError
+}
+
+type MongoTransactionManager struct {
+	client    *mongo.Client
+	supported bool
+}
+
+// NewTransactionManager returns a transaction manager for the database. Transactions need a
+// replica set (or a sharded cluster); on a standalone server the operations run without them.
+func NewTransactionManager(ctx context.Context, database *mongo.Database) *MongoTransactionManager {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	supported := false
+	if err := database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		logger.Error("Error trying to verify mongodb transaction support", err)
+	} else {
+		supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	}
+
+	if !supported {
+		logger.Info("MongoDB is not running as a replica set. Writes will not be transactional")
+	}
+
+	return &MongoTransactionManager{
+		client:    database.Client(),
+		supported: supported,
+	}
+}
+
+// WithTransaction commits the operations of fn when it succeeds and aborts them otherwise.
+// Transient errors (e.g. write conflicts) make the whole of fn run again.
+func (m *MongoTransactionManager) WithTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) *internal_error.InternalError) *internal_error.InternalError {
+
+	if !m.supported {
+		return fn(ctx)
+	}
+
+	session, e := m.client.StartSession()
+	if e != nil {
+		logger.Error("Error trying to start mongodb session", e)
+		return internal_error.NewInternalServerError("Error trying to start transaction")
+	}
+	defer session.EndSession(ctx)
+
+	_, e = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		if err := fn(sessionCtx); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if e != nil {
+		var internalErr *internal_error.InternalError
+		if errors.As(e, &internalErr) {
+			return internalErr
+		}
+
+		logger.Error("Error trying to commit transaction", e)
+		return internal_error.NewInternalServerError("Error trying to commit transaction")
+	}
+
+	return nil
+}
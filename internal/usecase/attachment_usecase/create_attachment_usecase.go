@@ -0,0 +1,169 @@
+package attachment_usecase
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/blob_storage"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/attachment_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	ATTACHMENT_MAX_SIZE = "ATTACHMENT_MAX_SIZE"
+
+	DEFAULT_ATTACHMENT_MAX_SIZE = 10 << 20 // 10 MiB
+)
+
+// content types accepted, as detected from the content itself (the client informed type is ignored)
+var allowedContentTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+}
+
+type CreateAttachmentInputDTO struct {
+	InvoiceId string
+	Kind      string
+	FileName  string
+	Content   io.Reader
+}
+
+type AttachmentUseCaseInterface interface {
+	CreateAttachment(
+		ctx context.Context,
+		attachmentInput CreateAttachmentInputDTO) (*AttachmentOutputDTO, *internal_error.InternalError)
+	FindAttachments(
+		ctx context.Context,
+		invoiceId string) ([]*AttachmentOutputDTO, *internal_error.InternalError)
+	DownloadAttachment(
+		ctx context.Context,
+		invoiceId string,
+		attachmentId string) (*AttachmentOutputDTO, io.ReadCloser, *internal_error.InternalError)
+	DeleteAttachment(
+		ctx context.Context,
+		invoiceId string,
+		attachmentId string) *internal_error.InternalError
+	MaxSize() int64
+}
+
+type AttachmentUseCase struct {
+	attachmentRepository attachment_entity.AttachmentRepositoryInterface
+	invoiceRepository    invoice_entity.InvoiceRepositoryInterface
+	blobStorage          blob_storage.BlobStorageInterface
+	maxSize              int64
+}
+
+func NewAttachmentUseCase(
+	attachmentRepository attachment_entity.AttachmentRepositoryInterface,
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	blobStorage blob_storage.BlobStorageInterface) AttachmentUseCaseInterface {
+
+	maxSize := int64(DEFAULT_ATTACHMENT_MAX_SIZE)
+	if value, err := strconv.ParseInt(os.Getenv(ATTACHMENT_MAX_SIZE), 10, 64); err == nil && value > 0 {
+		maxSize = value
+	}
+
+	return &AttachmentUseCase{
+		attachmentRepository: attachmentRepository,
+		invoiceRepository:    invoiceRepository,
+		blobStorage:          blobStorage,
+		maxSize:              maxSize,
+	}
+}
+
+func (u *AttachmentUseCase) MaxSize() int64 {
+	return u.maxSize
+}
+
+func (u *AttachmentUseCase) CreateAttachment(
+	ctx context.Context,
+	attachmentInput CreateAttachmentInputDTO) (*AttachmentOutputDTO, *internal_error.InternalError) {
+
+	if _, err := u.invoiceRepository.FindInvoiceById(ctx, attachmentInput.InvoiceId); err != nil {
+		return nil, err
+	}
+
+	if attachmentInput.Kind != "" {
+		if _, err := attachment_entity.GetAttachmentKindByName(attachmentInput.Kind); err != nil {
+			return nil, err
+		}
+	}
+
+	content := bufio.NewReader(attachmentInput.Content)
+
+	head, _ := content.Peek(512)
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(allowedContentTypes, contentType) {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("Unsupported attachment content type: %s", contentType))
+	}
+
+	reader := &checksumReader{
+		reader:  content,
+		hash:    sha256.New(),
+		maxSize: u.maxSize,
+	}
+
+	// the storage key does not depend on the content, so the entity is created after the upload
+	attachmentId, storageKey := attachment_entity.NewStorageKey(attachmentInput.InvoiceId)
+
+	if err := u.blobStorage.Put(ctx, storageKey, reader); err != nil {
+		if reader.exceeded {
+			return nil, internal_error.NewBadRequestError(
+				fmt.Sprintf("Attachment too large. Maximum size is %d bytes", u.maxSize))
+		}
+		return nil, err
+	}
+
+	attachment, err := attachment_entity.CreateAttachment(attachmentId, attachmentInput.InvoiceId, attachmentInput.Kind,
+		attachmentInput.FileName, contentType, reader.size, hex.EncodeToString(reader.hash.Sum(nil)))
+	if err == nil {
+		err = u.attachmentRepository.CreateAttachment(ctx, attachment)
+	}
+	if err != nil {
+		if e := u.blobStorage.Delete(ctx, storageKey); e != nil {
+			log.Println("Error trying to delete orphan attachment blob", e)
+		}
+		return nil, err
+	}
+
+	log.Printf("Attachment %s stored for invoice %s (%d bytes, %s)", attachment.Id, attachment.InvoiceId,
+		attachment.Size, attachment.ContentType)
+
+	return toAttachmentOutputDTO(attachment), nil
+}
+
+// checksumReader hashes and counts the content while it is stored, failing once it
+// grows beyond maxSize.
+type checksumReader struct {
+	reader   io.Reader
+	hash     hash.Hash
+	size     int64
+	maxSize  int64
+	exceeded bool
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.size += int64(n)
+	r.hash.Write(p[:n])
+
+	if r.size > r.maxSize {
+		r.exceeded = true
+		return n, fmt.Errorf("content exceeds %d bytes", r.maxSize)
+	}
+
+	return n, err
+}
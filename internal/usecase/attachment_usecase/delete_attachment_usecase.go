@@ -0,0 +1,31 @@
+package attachment_usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+func (u *AttachmentUseCase) DeleteAttachment(
+	ctx context.Context,
+	invoiceId string,
+	attachmentId string) *internal_error.InternalError {
+
+	attachment, err := u.findInvoiceAttachment(ctx, invoiceId, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	if err := u.attachmentRepository.DeleteAttachment(ctx, attachment.Id); err != nil {
+		return err
+	}
+
+	// the file is removed only after the record is gone, so a failed deletion leaves no
+	// attachment pointing to a missing file
+	if err := u.blobStorage.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Printf("Error deleting attachment file %s: %s", attachment.StorageKey, err.Message)
+	}
+
+	return nil
+}
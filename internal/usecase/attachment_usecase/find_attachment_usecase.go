@@ -0,0 +1,91 @@
+package attachment_usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/attachment_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type AttachmentOutputDTO struct {
+	Id          string    `json:"id"`
+	InvoiceId   string    `json:"invoiceId"`
+	Kind        string    `json:"kind"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u *AttachmentUseCase) FindAttachments(
+	ctx context.Context,
+	invoiceId string) ([]*AttachmentOutputDTO, *internal_error.InternalError) {
+
+	if _, err := u.invoiceRepository.FindInvoiceById(ctx, invoiceId); err != nil {
+		return nil, err
+	}
+
+	attachmentEntities, err := u.attachmentRepository.FindAttachments(ctx, invoiceId)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentOutputs := make([]*AttachmentOutputDTO, len(attachmentEntities))
+	for i, value := range attachmentEntities {
+		attachmentOutputs[i] = toAttachmentOutputDTO(value)
+	}
+
+	return attachmentOutputs, nil
+}
+
+// DownloadAttachment returns the attachment metadata and its content, which the caller must close.
+func (u *AttachmentUseCase) DownloadAttachment(
+	ctx context.Context,
+	invoiceId string,
+	attachmentId string) (*AttachmentOutputDTO, io.ReadCloser, *internal_error.InternalError) {
+
+	attachment, err := u.findInvoiceAttachment(ctx, invoiceId, attachmentId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := u.blobStorage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toAttachmentOutputDTO(attachment), content, nil
+}
+
+func (u *AttachmentUseCase) findInvoiceAttachment(
+	ctx context.Context,
+	invoiceId string,
+	attachmentId string) (*attachment_entity.Attachment, *internal_error.InternalError) {
+
+	attachment, err := u.attachmentRepository.FindAttachmentById(ctx, attachmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachment.InvoiceId != invoiceId {
+		return nil, internal_error.NewNotFoundError("Attachment not found for this invoice")
+	}
+
+	return attachment, nil
+}
+
+func toAttachmentOutputDTO(attachment *attachment_entity.Attachment) *AttachmentOutputDTO {
+	return &AttachmentOutputDTO{
+		Id:          attachment.Id,
+		InvoiceId:   attachment.InvoiceId,
+		Kind:        attachment.Kind.Name(),
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Checksum:    attachment.Checksum,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
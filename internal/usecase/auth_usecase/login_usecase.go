@@ -0,0 +1,140 @@
+package auth_usecase
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/auth_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/token_service"
+)
+
+const (
+	AUTH_REFRESH_TOKEN_DURATION        = "AUTH_REFRESH_TOKEN_DURATION"
+	AUTH_PASSWORD_SETUP_TOKEN_DURATION = "AUTH_PASSWORD_SETUP_TOKEN_DURATION"
+
+	DEFAULT_REFRESH_TOKEN_DURATION        = 30 * 24 * time.Hour
+	DEFAULT_PASSWORD_SETUP_TOKEN_DURATION = 24 * time.Hour
+)
+
+type LoginInputDTO struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenOutputDTO struct {
+	AccessToken           string    `json:"accessToken"`
+	TokenType             string    `json:"tokenType"`
+	ExpiresAt             time.Time `json:"expiresAt" time_format:"2006-01-02 15:04:05"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt" time_format:"2006-01-02 15:04:05"`
+}
+
+type AuthUseCaseInterface interface {
+	Login(
+		ctx context.Context,
+		loginInput LoginInputDTO) (*TokenOutputDTO, *internal_error.InternalError)
+	Refresh(
+		ctx context.Context,
+		refreshInput RefreshInputDTO) (*TokenOutputDTO, *internal_error.InternalError)
+	Logout(
+		ctx context.Context,
+		refreshInput RefreshInputDTO) *internal_error.InternalError
+	SetupPassword(
+		ctx context.Context,
+		setupInput PasswordSetupInputDTO) *internal_error.InternalError
+	// IssuePasswordSetupToken creates a password setup token for the active user without a password
+	IssuePasswordSetupToken(
+		ctx context.Context,
+		email string) (*PasswordSetupTokenOutputDTO, *internal_error.InternalError)
+	// Authenticate returns the user identified by the access token
+	Authenticate(accessToken string) (string, *internal_error.InternalError)
+}
+
+type AuthUseCase struct {
+	userRepository               user_entity.UserRepositoryInterface
+	refreshTokenRepository       auth_entity.RefreshTokenRepositoryInterface
+	passwordSetupTokenRepository auth_entity.PasswordSetupTokenRepositoryInterface
+	tokenService                 token_service.TokenServiceInterface
+	refreshTokenDuration         time.Duration
+	passwordSetupTokenDuration   time.Duration
+}
+
+func NewAuthUseCase(
+	userRepository user_entity.UserRepositoryInterface,
+	refreshTokenRepository auth_entity.RefreshTokenRepositoryInterface,
+	passwordSetupTokenRepository auth_entity.PasswordSetupTokenRepositoryInterface,
+	tokenService token_service.TokenServiceInterface) AuthUseCaseInterface {
+
+	refreshTokenDuration := DEFAULT_REFRESH_TOKEN_DURATION
+	if value, err := time.ParseDuration(os.Getenv(AUTH_REFRESH_TOKEN_DURATION)); err == nil && value > 0 {
+		refreshTokenDuration = value
+	}
+
+	passwordSetupTokenDuration := DEFAULT_PASSWORD_SETUP_TOKEN_DURATION
+	if value, err := time.ParseDuration(os.Getenv(AUTH_PASSWORD_SETUP_TOKEN_DURATION)); err == nil && value > 0 {
+		passwordSetupTokenDuration = value
+	}
+
+	return &AuthUseCase{
+		userRepository:               userRepository,
+		refreshTokenRepository:       refreshTokenRepository,
+		passwordSetupTokenRepository: passwordSetupTokenRepository,
+		tokenService:                 tokenService,
+		refreshTokenDuration:         refreshTokenDuration,
+		passwordSetupTokenDuration:   passwordSetupTokenDuration,
+	}
+}
+
+func (u *AuthUseCase) Login(
+	ctx context.Context,
+	loginInput LoginInputDTO) (*TokenOutputDTO, *internal_error.InternalError) {
+
+	// the same error for unknown emails and wrong passwords, not revealing which emails are registered
+	invalidCredentials := internal_error.NewUnauthorizedError("invalid email or password")
+
+	user, err := u.userRepository.FindUserByEmail(ctx, loginInput.Email)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, invalidCredentials
+		}
+		return nil, err
+	}
+
+	if user.Status != user_entity.Active || !user.CheckPassword(loginInput.Password) {
+		return nil, invalidCredentials
+	}
+
+	return u.issueTokens(ctx, user.Id)
+}
+
+func (u *AuthUseCase) Authenticate(accessToken string) (string, *internal_error.InternalError) {
+	return u.tokenService.ValidateAccessToken(accessToken)
+}
+
+// issueTokens starts a session of the user, returning a new access token and refresh token.
+func (u *AuthUseCase) issueTokens(ctx context.Context, userId string) (*TokenOutputDTO, *internal_error.InternalError) {
+	accessToken, expiresAt, err := u.tokenService.CreateAccessToken(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshTokenValue, err := auth_entity.CreateRefreshToken(userId, u.refreshTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.refreshTokenRepository.CreateRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &TokenOutputDTO{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshTokenValue,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, nil
+}
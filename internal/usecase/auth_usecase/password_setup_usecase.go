@@ -0,0 +1,100 @@
+package auth_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/auth_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type PasswordSetupInputDTO struct {
+	SetupToken string `json:"setupToken" binding:"required"`
+	Password   string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type PasswordSetupTokenOutputDTO struct {
+	SetupToken string    `json:"setupToken"`
+	ExpiresAt  time.Time `json:"expiresAt" time_format:"2006-01-02 15:04:05"`
+}
+
+// SetupPassword defines the first password of a user created before authentication existed, using
+// the password setup token handed to the user.
+func (u *AuthUseCase) SetupPassword(
+	ctx context.Context,
+	setupInput PasswordSetupInputDTO) *internal_error.InternalError {
+
+	invalidToken := internal_error.NewUnauthorizedError("invalid password setup token")
+
+	setupToken, err := u.passwordSetupTokenRepository.UsePasswordSetupToken(ctx, auth_entity.HashRefreshToken(setupInput.SetupToken))
+	if err != nil {
+		if err.Err == "not_found" {
+			return invalidToken
+		}
+		return err
+	}
+
+	user, err := u.userRepository.FindUserById(ctx, setupToken.UserId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return invalidToken
+		}
+		return err
+	}
+
+	// the user already defined a password with another token
+	if user.PasswordHash != "" || user.Status != user_entity.Active {
+		return invalidToken
+	}
+
+	if err := user.SetPassword(setupInput.Password); err != nil {
+		return err
+	}
+
+	return u.userRepository.UpdateUser(ctx, user)
+}
+
+// IssuePasswordSetupToken creates a password setup token for the active user without a password
+// identified by the email. The token value is returned only to the operator that requested it and a
+// new one is not issued while the user still has an unused token that has not expired.
+func (u *AuthUseCase) IssuePasswordSetupToken(
+	ctx context.Context,
+	email string) (*PasswordSetupTokenOutputDTO, *internal_error.InternalError) {
+
+	user, err := u.userRepository.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != user_entity.Active {
+		return nil, internal_error.NewBadRequestError("User is not active")
+	}
+
+	if user.PasswordHash != "" {
+		return nil, internal_error.NewBadRequestError("User already has a password")
+	}
+
+	hasActiveToken, err := u.passwordSetupTokenRepository.HasActivePasswordSetupToken(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasActiveToken {
+		return nil, internal_error.NewBadRequestError("User already has an unexpired password setup token")
+	}
+
+	setupToken, value, err := auth_entity.CreatePasswordSetupToken(user.Id, u.passwordSetupTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.passwordSetupTokenRepository.CreatePasswordSetupToken(ctx, setupToken); err != nil {
+		return nil, err
+	}
+
+	return &PasswordSetupTokenOutputDTO{
+		SetupToken: value,
+		ExpiresAt:  setupToken.ExpiresAt,
+	}, nil
+}
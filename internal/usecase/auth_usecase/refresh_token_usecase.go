@@ -0,0 +1,90 @@
+package auth_usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/auth_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type RefreshInputDTO struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// Refresh exchanges a refresh token for new tokens. Each refresh token is used only once: it is
+// revoked before the new tokens are issued, so concurrent refreshes with it do not both succeed,
+// and reusing it, which happens when it was stolen, ends every session of the user.
+func (u *AuthUseCase) Refresh(
+	ctx context.Context,
+	refreshInput RefreshInputDTO) (*TokenOutputDTO, *internal_error.InternalError) {
+
+	refreshToken, err := u.revokeRefreshToken(ctx, refreshInput.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshToken.IsRevoked() {
+		log.Printf("Revoked refresh token %s reused. Ending the sessions of user %s", refreshToken.Id, refreshToken.UserId)
+		if err := u.refreshTokenRepository.RevokeUserRefreshTokens(ctx, refreshToken.UserId); err != nil {
+			return nil, err
+		}
+		return nil, internal_error.NewUnauthorizedError("invalid refresh token")
+	}
+
+	if !refreshToken.IsActive(time.Now()) {
+		return nil, internal_error.NewUnauthorizedError("expired refresh token")
+	}
+
+	user, err := u.userRepository.FindUserById(ctx, refreshToken.UserId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewUnauthorizedError("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if user.Status != user_entity.Active {
+		return nil, internal_error.NewUnauthorizedError("inactive user")
+	}
+
+	return u.issueTokens(ctx, user.Id)
+}
+
+// Logout ends the session of the refresh token.
+func (u *AuthUseCase) Logout(
+	ctx context.Context,
+	refreshInput RefreshInputDTO) *internal_error.InternalError {
+
+	_, err := u.revokeRefreshToken(ctx, refreshInput.RefreshToken)
+
+	return err
+}
+
+// revokeRefreshToken revokes the refresh token and returns it as it was before, which is already
+// revoked when it was used before.
+func (u *AuthUseCase) revokeRefreshToken(
+	ctx context.Context, value string) (*auth_entity.RefreshToken, *internal_error.InternalError) {
+
+	tokenHash := auth_entity.HashRefreshToken(value)
+
+	refreshToken, err := u.refreshTokenRepository.RevokeRefreshToken(ctx, tokenHash)
+	if err == nil {
+		return refreshToken, nil
+	}
+	if err.Err != "not_found" {
+		return nil, err
+	}
+
+	refreshToken, err = u.refreshTokenRepository.FindRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewUnauthorizedError("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	return refreshToken, nil
+}
@@ -2,12 +2,33 @@ package bill_processing_usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type GetBillProcessingStatusOutputDTO struct {
-	Status string `json:"status"`
+	Status string                 `json:"status"`
+	Period string                 `json:"period,omitempty"`
+	Bills  []*BillResultOutputDTO `json:"bills"`
+	// BudgetAlerts lists the categories over budget in the period when the processing finished
+	BudgetAlerts []*BudgetAlertOutputDTO `json:"budgetAlerts"`
+}
+
+type BillResultOutputDTO struct {
+	BillId      string    `json:"billId"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	ProcessedAt time.Time `json:"processedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+type BudgetAlertOutputDTO struct {
+	CategoryId    string      `json:"categoryId"`
+	Spent         money.Money `json:"spent"`
+	MonthlyBudget money.Money `json:"monthlyBudget"`
+	Message       string      `json:"message"`
 }
 
 func (u *BillProcessingUseCase) GetBillProcessingStatus(
@@ -20,6 +41,31 @@ func (u *BillProcessingUseCase) GetBillProcessingStatus(
 		return GetBillProcessingStatusOutputDTO{}, err
 	}
 
-	return GetBillProcessingStatusOutputDTO{
-		Status: billProcessing.Status.Name()}, nil
+	output := GetBillProcessingStatusOutputDTO{
+		Status:       billProcessing.Status.Name(),
+		Period:       billProcessing.Period,
+		Bills:        make([]*BillResultOutputDTO, len(billProcessing.BillResults)),
+		BudgetAlerts: make([]*BudgetAlertOutputDTO, len(billProcessing.BudgetAlerts)),
+	}
+
+	for i, billResult := range billProcessing.BillResults {
+		output.Bills[i] = &BillResultOutputDTO{
+			BillId:      billResult.BillId,
+			Status:      billResult.Status.Name(),
+			Error:       billResult.Error,
+			Reason:      billResult.Reason,
+			ProcessedAt: billResult.ProcessedAt,
+		}
+	}
+
+	for i, budgetAlert := range billProcessing.BudgetAlerts {
+		output.BudgetAlerts[i] = &BudgetAlertOutputDTO{
+			CategoryId:    budgetAlert.CategoryId,
+			Spent:         budgetAlert.Spent,
+			MonthlyBudget: budgetAlert.MonthlyBudget,
+			Message:       budgetAlert.Message,
+		}
+	}
+
+	return output, nil
 }
@@ -0,0 +1,73 @@
+package bill_processing_usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// SettleAutomaticPayments takes as paid the outstanding balance of the unpaid and partially paid
+// invoices of bills charged by automatic debit or credit card that are due up to today. The payments stay unconfirmed until a statement shows
+// the charge, so instead of a reminder to pay there is a notice to check that it happened. It
+// runs daily for every household and at the end of each processing.
+func (u *BillProcessingUseCase) SettleAutomaticPayments(ctx context.Context, today time.Time) *internal_error.InternalError {
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		return err
+	}
+
+	automaticBills := make(map[string]*bill_entity.Bill)
+	billIds := make([]string, 0)
+	for _, bill := range bills {
+		if bill.PaymentMode.IsAutomatic() {
+			automaticBills[bill.Id] = bill
+			billIds = append(billIds, bill.Id)
+		}
+	}
+
+	if len(billIds) == 0 {
+		return nil
+	}
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{BillIds: billIds})
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if invoice.Status != invoice_entity.Unpaid && invoice.Status != invoice_entity.PartiallyPaid {
+			continue
+		}
+		if invoice.DueDate > today.Format("2006-01-02") {
+			continue
+		}
+
+		bill := automaticBills[invoice.BillId]
+
+		if _, err := invoice.AddAutomaticPayment(automaticPaymentMethod(bill.PaymentMode)); err != nil {
+			log.Println("Error trying to register automatic payment", err)
+			continue
+		}
+
+		if err := u.invoiceRepository.UpdateInvoice(ctx, invoice); err != nil {
+			log.Println("Error trying to update invoice", err)
+			continue
+		}
+
+		log.Printf("Invoice %s of bill %s paid by %s on %s. Check that the charge happened",
+			invoice.Id, bill.Name, bill.PaymentMode.Name(), invoice.DueDate)
+	}
+
+	return nil
+}
+
+func automaticPaymentMethod(paymentMode bill_entity.PaymentMode) invoice_entity.PaymentMethod {
+	if paymentMode == bill_entity.CreditCard {
+		return invoice_entity.Cartao
+	}
+	return invoice_entity.DebitoAutomatico
+}
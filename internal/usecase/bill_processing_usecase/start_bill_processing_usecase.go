@@ -5,17 +5,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/calendar"
 	"github.com/regismartiny/lembrador-contas-go/internal/data_extractor/email_data_extractor"
 	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_processing_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/table_value_source_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+	"github.com/regismartiny/lembrador-contas-go/internal/transaction_manager"
 )
 
 const (
@@ -36,32 +41,44 @@ type BillProcessingUseCaseInterface interface {
 	FindBillProcessings(
 		ctx context.Context,
 		status bill_processing_entity.BillProcessingStatus) ([]*FindBillProcessingOutputDTO, *internal_error.InternalError)
+	SettleAutomaticPayments(
+		ctx context.Context,
+		today time.Time) *internal_error.InternalError
 }
 
 type BillProcessingUseCase struct {
 	billProcessingRepository   bill_processing_entity.BillProcessingRepositoryInterface
 	billRepository             bill_entity.BillRepositoryInterface
+	categoryRepository         category_entity.CategoryRepositoryInterface
 	tableValueSourceRepository table_value_source_entity.TableValueSourceRepositoryInterface
 	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface
 	invoiceRepository          invoice_entity.InvoiceRepositoryInterface
-	emailService               email_service.EmailServiceInterface
+	emailServiceResolver       email_service.EmailServiceResolverInterface
+	transactionManager         transaction_manager.TransactionManagerInterface
+	calendar                   calendar.CalendarInterface
 }
 
 func NewBillProcessingUseCase(
 	billProcessingRepository bill_processing_entity.BillProcessingRepositoryInterface,
 	billRepository bill_entity.BillRepositoryInterface,
+	categoryRepository category_entity.CategoryRepositoryInterface,
 	tableValueSourceRepository table_value_source_entity.TableValueSourceRepositoryInterface,
 	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface,
 	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
-	emailService email_service.EmailServiceInterface) BillProcessingUseCaseInterface {
+	emailServiceResolver email_service.EmailServiceResolverInterface,
+	transactionManager transaction_manager.TransactionManagerInterface,
+	calendar calendar.CalendarInterface) BillProcessingUseCaseInterface {
 
 	return &BillProcessingUseCase{
 		billProcessingRepository:   billProcessingRepository,
 		billRepository:             billRepository,
+		categoryRepository:         categoryRepository,
 		tableValueSourceRepository: tableValueSourceRepository,
 		emailValueSourceRepository: emailValueSourceRepository,
 		invoiceRepository:          invoiceRepository,
-		emailService:               emailService,
+		emailServiceResolver:       emailServiceResolver,
+		transactionManager:         transactionManager,
+		calendar:                   calendar,
 	}
 }
 
@@ -69,22 +86,33 @@ func (u *BillProcessingUseCase) StartBillProcessing(
 	ctx context.Context,
 	period string) (StartBillProcessingOutputDTO, *internal_error.InternalError) {
 
-	if err := u.verifyNoProcessingInProgress(ctx); err != nil {
-		log.Println("Error trying to start bill processing", err)
-		return StartBillProcessingOutputDTO{}, err
-	}
-
 	billProcessing, err := bill_processing_entity.CreateBillProcessing("", period)
 	if err != nil {
 		return StartBillProcessingOutputDTO{}, err
 	}
 
-	if err := u.billProcessingRepository.CreateBillProcessing(ctx, billProcessing); err != nil {
+	// the lock is also taken when deleting bills, so this transaction and a concurrent one (or
+	// another processing being started) conflict and one of them runs again after the other
+	err = u.transactionManager.WithTransaction(ctx, func(ctx context.Context) *internal_error.InternalError {
+		if err := u.billProcessingRepository.LockProcessings(ctx); err != nil {
+			return err
+		}
+
+		if err := u.verifyNoProcessingInProgress(ctx); err != nil {
+			return err
+		}
+
+		return u.billProcessingRepository.CreateBillProcessing(ctx, billProcessing)
+	})
+	if err != nil {
+		log.Println("Error trying to start bill processing", err)
 		return StartBillProcessingOutputDTO{}, err
 	}
 
-	go u.startProcessing(ctx, billProcessing)
-	go u.manageProcessingTimeot(ctx, billProcessing)
+	// the processing outlives the request, but keeps its household
+	processingCtx := context.WithoutCancel(ctx)
+	go u.startProcessing(processingCtx, billProcessing)
+	go u.manageProcessingTimeot(processingCtx, billProcessing)
 
 	return StartBillProcessingOutputDTO{
 		BillProcessingId: billProcessing.Id}, nil
@@ -126,16 +154,20 @@ func (u *BillProcessingUseCase) startProcessing(ctx context.Context, billProcess
 	log.Println("Bill processing started. Period:", billProcessing.Period)
 
 	//Find all active bills
-	activeBills, err := u.billRepository.FindBills(ctx, bill_entity.Active, "", "", "")
+	activeBills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{Status: bill_entity.Active})
 	if err != nil {
 		log.Println("Error trying to find active bills", err)
 		return
 	}
 
+	activeBills = filterBillsDueInPeriod(activeBills, billProcessing.Period)
+	activeBills = u.skipSuspendedBills(ctx, activeBills, billProcessing)
+
 	wg := sync.WaitGroup{}
 	wg.Add(len(activeBills))
 
 	billProcessingErrors := make([]string, 0)
+	mutex := sync.Mutex{}
 
 	for _, bill := range activeBills {
 
@@ -145,7 +177,10 @@ func (u *BillProcessingUseCase) startProcessing(ctx context.Context, billProcess
 			if err := u.processBill(ctx, bill, billProcessing); err != nil {
 				errString := fmt.Sprintf("Error trying to process bill %v: %v", bill.Id, err)
 				log.Println(errString)
+
+				mutex.Lock()
 				billProcessingErrors = append(billProcessingErrors, errString)
+				mutex.Unlock()
 			}
 		}()
 	}
@@ -160,13 +195,168 @@ func (u *BillProcessingUseCase) startProcessing(ctx context.Context, billProcess
 		billProcessing.Status = bill_processing_entity.Success
 	}
 
+	billProcessing.BudgetAlerts = u.checkCategoryBudgets(ctx, billProcessing.Period)
+
 	u.billProcessingRepository.UpdateBillProcessing(ctx, billProcessing)
+
+	if err := u.SettleAutomaticPayments(ctx, time.Now()); err != nil {
+		log.Println("Error trying to settle automatic payments", err)
+	}
 }
 
+// checkCategoryBudgets returns the alerts of the categories whose invoices of the period exceed
+// the monthly budget, saved with the result of the processing.
+func (u *BillProcessingUseCase) checkCategoryBudgets(
+	ctx context.Context, period string) []*bill_processing_entity.BudgetAlert {
+
+	categories, err := u.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		log.Println("Error trying to find categories", err)
+		return nil
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		log.Println("Error trying to find bills", err)
+		return nil
+	}
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{FromPeriod: period, ToPeriod: period})
+	if err != nil {
+		log.Println("Error trying to find invoices", err)
+		return nil
+	}
+
+	budgetAlerts := make([]*bill_processing_entity.BudgetAlert, 0)
+	for _, budget := range category_entity.CalculateBudgets(categories, period, bills, invoices) {
+		if budget.Exceeded() {
+			budgetAlerts = append(budgetAlerts, &bill_processing_entity.BudgetAlert{
+				CategoryId:    budget.Category.Id,
+				Spent:         budget.Spent,
+				MonthlyBudget: budget.Category.MonthlyBudget,
+				Message:       budget.Alert(),
+			})
+		}
+	}
+
+	return budgetAlerts
+}
+
+// filterBillsDueInPeriod returns the bills whose recurrence has a due date for the invoice of
+// the period, e.g. a yearly bill is processed only in the period before its due month.
+func filterBillsDueInPeriod(bills []*bill_entity.Bill, period string) []*bill_entity.Bill {
+	processingPeriod, e := time.ParseInLocation("2006-01", period, time.Local)
+	if e != nil {
+		// processBillInvoice reports the invalid period on each bill
+		return bills
+	}
+
+	dueBills := make([]*bill_entity.Bill, 0, len(bills))
+	for _, bill := range bills {
+		if _, due := bill.DueDate(processingPeriod); !due {
+			log.Printf("Bill %s (%s) is not due in period %s", bill.Name, bill.Recurrence.Type.Name(), period)
+			continue
+		}
+		dueBills = append(dueBills, bill)
+	}
+
+	return dueBills
+}
+
+// skipSuspendedBills records the bills suspended in the period of the processing as skipped and
+// returns the others. A bill whose suspension ended in the previous period is processed again.
+func (u *BillProcessingUseCase) skipSuspendedBills(ctx context.Context, bills []*bill_entity.Bill,
+	billProcessing *bill_processing_entity.BillProcessing) []*bill_entity.Bill {
+
+	processingPeriod, e := time.ParseInLocation("2006-01", billProcessing.Period, time.Local)
+	if e != nil {
+		return bills
+	}
+	previousPeriod := processingPeriod.AddDate(0, -1, 0).Format("2006-01")
+
+	billsToProcess := make([]*bill_entity.Bill, 0, len(bills))
+	for _, bill := range bills {
+		suspension := bill.SuspensionIn(billProcessing.Period)
+		if suspension == nil {
+			if previous := bill.SuspensionIn(previousPeriod); previous != nil {
+				log.Printf("Bill %s reactivated: suspension from %s to %s ended", bill.Name, previous.FromPeriod, previous.ToPeriod)
+			}
+			billsToProcess = append(billsToProcess, bill)
+			continue
+		}
+
+		reason := fmt.Sprintf("Bill suspended from %s to %s", suspension.FromPeriod, suspension.ToPeriod)
+		if suspension.Reason != "" {
+			reason += ": " + suspension.Reason
+		}
+		log.Printf("Skipping bill %s. %s", bill.Name, reason)
+
+		if err := u.billProcessingRepository.AddBillResult(ctx, billProcessing.Id,
+			bill_processing_entity.NewSkippedBillResult(bill.Id, reason)); err != nil {
+			log.Println("Error trying to save bill result", err)
+		}
+	}
+
+	return billsToProcess
+}
+
+// processBill reads the invoice of the bill from its value source and saves it, together with
+// the bill result, in a single transaction: the bill is either fully processed or left untouched.
 func (u *BillProcessingUseCase) processBill(ctx context.Context, bill *bill_entity.Bill, billProcessing *bill_processing_entity.BillProcessing) *internal_error.InternalError {
 	log.Printf("Processing bill: %v", bill)
 
-	u.deleteUnpaidInvoices(ctx, billProcessing, bill)
+	err := u.processBillInvoice(ctx, bill, billProcessing)
+	if err != nil {
+		if err := u.billProcessingRepository.AddBillResult(ctx, billProcessing.Id,
+			bill_processing_entity.NewBillResult(bill.Id, err)); err != nil {
+			log.Println("Error trying to save bill result", err)
+		}
+	}
+
+	return err
+}
+
+func (u *BillProcessingUseCase) processBillInvoice(ctx context.Context, bill *bill_entity.Bill, billProcessing *bill_processing_entity.BillProcessing) *internal_error.InternalError {
+	processingPeriod, e := time.ParseInLocation("2006-01", billProcessing.Period, time.Local)
+	if e != nil {
+		return internal_error.NewBadRequestError("invalid processing period. expected format YYYY-MM")
+	}
+
+	// the invoice of a period is due in the following month, on a business day when the bill says so
+	dueDate, _ := bill.DueDate(processingPeriod)
+	dueDate, err := u.calendar.AdjustDueDate(ctx, dueDate, bill.DueDateRule)
+	if err != nil {
+		return err
+	}
+
+	// the value source is read before the transaction, which must not wait on the mailbox
+	extracted, err := u.extractInvoice(ctx, bill, processingPeriod)
+	if err != nil {
+		return err
+	}
+
+	return u.transactionManager.WithTransaction(ctx, func(ctx context.Context) *internal_error.InternalError {
+		if extracted != nil {
+			if err := u.saveInvoice(ctx, bill, billProcessing.Period, dueDate, extracted); err != nil {
+				return err
+			}
+		}
+
+		return u.billProcessingRepository.AddBillResult(ctx, billProcessing.Id,
+			bill_processing_entity.NewBillResult(bill.Id, nil))
+	})
+}
+
+// extractedInvoice is the invoice data read from the value source of a bill.
+type extractedInvoice struct {
+	amount      money.Money
+	barcode     string
+	emailSource *invoice_entity.EmailSource
+}
+
+// extractInvoice returns the invoice data of the period, or nil when the value source has none.
+func (u *BillProcessingUseCase) extractInvoice(ctx context.Context, bill *bill_entity.Bill,
+	processingPeriod time.Time) (*extractedInvoice, *internal_error.InternalError) {
 
 	valueSourceId := bill.ValueSourceId
 	valueSourceType := bill.ValueSourceType
@@ -176,91 +366,144 @@ func (u *BillProcessingUseCase) processBill(ctx context.Context, bill *bill_enti
 		tableValueSource, err := u.tableValueSourceRepository.FindTableValueSourceById(ctx, valueSourceId)
 		if err != nil {
 			log.Println("Error trying to find table value source:", err)
-			return err
+			return nil, err
 		}
-		return u.processTableValueSource(ctx, bill, tableValueSource)
+		return u.extractTableValueSource(bill, processingPeriod, tableValueSource), nil
 	case bill_entity.Email:
 		emailValueSource, err := u.emailValueSourceRepository.FindEmailValueSourceById(ctx, valueSourceId)
 		if err != nil {
 			log.Println("Error trying to find email value source:", err)
-			return err
+			return nil, err
 		}
-		return u.processEmailValueSource(ctx, bill, emailValueSource)
+		return u.extractEmailValueSource(ctx, bill, processingPeriod, emailValueSource)
 	case bill_entity.API:
-		return internal_error.NewInternalServerError("valueSourceType API not implemented yet")
+		return nil, internal_error.NewInternalServerError("valueSourceType API not implemented yet")
 	}
 
-	return nil
+	return nil, nil
 }
 
-func (u *BillProcessingUseCase) deleteUnpaidInvoices(ctx context.Context, billProcessing *bill_processing_entity.BillProcessing, bill *bill_entity.Bill) {
-	log.Printf("Deleting unpaid invoices for period: %v", billProcessing.Period)
-
-	processingPeriod, _ := time.Parse("2006-01", billProcessing.Period)
-	billDueDate := time.Date(processingPeriod.Year(), processingPeriod.Month()+1, int(bill.DueDay), 0, 0, 0, 0, time.Local).Format("2006-01-02")
-
-	log.Printf("Deleting invoices with due date: %v", billDueDate)
-	invoicesDeleted, _ := u.invoiceRepository.DeleteInvoices(ctx, bill.Id, invoice_entity.Unpaid, billDueDate)
-
-	log.Printf("Invoices deleted: %v", invoicesDeleted)
-}
+func (u *BillProcessingUseCase) extractTableValueSource(bill *bill_entity.Bill, processingPeriod time.Time,
+	tableValueSource *table_value_source_entity.TableValueSource) *extractedInvoice {
 
-func (u *BillProcessingUseCase) processTableValueSource(ctx context.Context, bill *bill_entity.Bill,
-	tableValueSource *table_value_source_entity.TableValueSource) *internal_error.InternalError {
+	log.Println("Processing Bill", bill.Name, "for period", processingPeriod.Format("01/2006"))
 
-	now := time.Now()
-	dueDate := time.Date(now.Year(), now.Month(), int(bill.DueDay), 0, 0, 0, 0, time.Local)
-	processingDate := dueDate.AddDate(0, -1, 0) // always process previous month
-
-	log.Println("Today is", now.Format("02/01/2006"))
-	log.Println("Processing Bill", bill.Name, "for period", processingDate.Month(), "/", now.Year())
-
-	amount := 0.0
+	var amount money.Money
 
 	for _, v := range tableValueSource.Data {
-		if v.Period.Month == uint8(processingDate.Month()) && v.Period.Year == uint16(processingDate.Year()) {
-			log.Printf("Found data for current period. Value: %2.f\n", v.Amount)
+		if v.Period.Month == uint8(processingPeriod.Month()) && v.Period.Year == uint16(processingPeriod.Year()) {
+			log.Println("Found data for current period. Value:", v.Amount)
 			amount = v.Amount
 			break
 		}
 	}
 
-	if amount == 0.0 {
+	if amount == 0 {
 		log.Println("No invoice found for current period")
 		return nil
 	}
 
-	return u.createInvoice(ctx, bill, dueDate, amount)
+	return &extractedInvoice{amount: amount}
 }
 
-func (u *BillProcessingUseCase) createInvoice(ctx context.Context, bill *bill_entity.Bill, dueDate time.Time, amount float64) *internal_error.InternalError {
-	log.Println("Creating invoice")
+// saveInvoice creates the invoice of the bill for the period, or updates the existing one, so
+// processing a period again keeps the invoice id, its attachments and its payments. The amount
+// of an existing invoice is only replaced when it changed, and never after it received payments.
+func (u *BillProcessingUseCase) saveInvoice(ctx context.Context, bill *bill_entity.Bill, period string, dueDate time.Time,
+	extracted *extractedInvoice) *internal_error.InternalError {
 
-	invoice, err := invoice_entity.CreateInvoice(
-		bill.Id,
-		dueDate.Format("2006-01-02"),
-		amount,
-		"",
-	)
-	if err != nil {
+	amount, emailSource := extracted.amount, extracted.emailSource
+
+	invoice, err := u.invoiceRepository.FindInvoiceByBillAndPeriod(ctx, bill.Id, period)
+	if err != nil && err.Err != "not_found" {
 		return err
 	}
 
-	return u.invoiceRepository.CreateInvoice(ctx, invoice)
+	if invoice == nil {
+		log.Println("Creating invoice")
+
+		invoice, err = invoice_entity.CreateInvoice(
+			bill.Id,
+			period,
+			dueDate.Format("2006-01-02"),
+			amount,
+			"",
+		)
+		if err != nil {
+			return err
+		}
+
+		invoice.EmailSource = emailSource
+		invoice.Barcode = extracted.barcode
+		invoice.Shares = bill.InvoiceShares(amount)
+
+		return u.invoiceRepository.UpsertInvoice(ctx, invoice)
+	}
+
+	if invoice.Status != invoice_entity.Unpaid || len(invoice.Payments) > 0 {
+		log.Println("Invoice already paid. Keeping invoice", invoice.Id)
+		return nil
+	}
+
+	changed := false
+
+	if previousAmount := invoice.Amount; invoice.UpdateAmount(amount) {
+		log.Printf("Invoice %s amount changed from %s to %s", invoice.Id, previousAmount, amount)
+		changed = true
+	}
+
+	if formattedDueDate := dueDate.Format("2006-01-02"); invoice.DueDate != formattedDueDate {
+		log.Printf("Invoice %s due date changed from %s to %s", invoice.Id, invoice.DueDate, formattedDueDate)
+		invoice.DueDate = formattedDueDate
+		invoice.UpdatedAt = time.Now()
+		changed = true
+	}
+
+	// the amount or the split rule of the bill may have changed
+	if shares := bill.InvoiceShares(invoice.Amount); !slices.Equal(invoice.Shares, shares) {
+		invoice.Shares = shares
+		invoice.UpdatedAt = time.Now()
+		changed = true
+	}
+
+	if emailSource != nil && (invoice.EmailSource == nil || *invoice.EmailSource != *emailSource) {
+		invoice.EmailSource = emailSource
+		invoice.UpdatedAt = time.Now()
+		changed = true
+	}
+
+	if extracted.barcode != "" && invoice.Barcode != extracted.barcode {
+		invoice.Barcode = extracted.barcode
+		invoice.UpdatedAt = time.Now()
+		changed = true
+	}
+
+	if !changed {
+		log.Println("Invoice unchanged. Keeping invoice", invoice.Id)
+		return nil
+	}
+
+	return u.invoiceRepository.UpsertInvoice(ctx, invoice)
 }
 
-func (u *BillProcessingUseCase) processEmailValueSource(ctx context.Context, bill *bill_entity.Bill,
-	emailValueSource *email_value_source_entity.EmailValueSource) *internal_error.InternalError {
+func (u *BillProcessingUseCase) extractEmailValueSource(ctx context.Context, bill *bill_entity.Bill,
+	processingPeriod time.Time,
+	emailValueSource *email_value_source_entity.EmailValueSource) (*extractedInvoice, *internal_error.InternalError) {
 
 	log.Println("Processing email value source. Address:", emailValueSource.Address, "Subject:", emailValueSource.Subject)
 
-	dataExtractor := email_data_extractor.NewEmailDataExtractor(u.emailService, emailValueSource.DataExtractor)
+	emailService, err := u.emailServiceResolver.ResolveEmailService(ctx, emailValueSource.MailAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !emailService.IsConnected() {
+		return nil, internal_error.NewBadRequestError("Email value source unavailable: email account not connected")
+	}
 
-	now := time.Now()
-	dueDate := time.Date(now.Year(), now.Month(), int(bill.DueDay), 0, 0, 0, 0, time.Local)
-	processingDate := dueDate.AddDate(0, -1, 0) // previous month
+	dataExtractor := email_data_extractor.NewEmailDataExtractor(emailService, emailValueSource.DataExtractor)
 
-	startDate := time.Date(processingDate.Year(), processingDate.Month(), 1, 0, 0, 0, 0, time.Local)
+	startDate := processingPeriod
 	endDate := startDate.AddDate(0, 1, -1) //last day of month
 
 	dataExtractorResponse, err := dataExtractor.Extract(email_data_extractor.EmailDataExtractorRequest{
@@ -268,12 +511,62 @@ func (u *BillProcessingUseCase) processEmailValueSource(ctx context.Context, bil
 		Address:   emailValueSource.Address,
 		StartDate: startDate,
 		EndDate:   endDate,
+		Labels:    emailValueSource.Labels,
+		Exclude:   emailValueSource.Exclude,
+		Selection: email_data_extractor.NewestMessage,
+		SkipMessage: func(messageId string) bool {
+			consumedBy, err := u.findInvoicesConsumingEmail(ctx, bill, processingPeriod.Format("2006-01"), messageId, "")
+			if err != nil {
+				log.Println("Error trying to verify if message was consumed:", err)
+				return false
+			}
+			return len(consumedBy) > 0
+		},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	u.createInvoice(ctx, bill, dueDate, dataExtractorResponse.Amount)
+	message := dataExtractorResponse.Message
 
-	return nil
+	emailSource := &invoice_entity.EmailSource{
+		MessageId:   message.Id,
+		ThreadId:    message.ThreadId,
+		Sender:      message.Sender,
+		ReceivedAt:  message.ReceivedAt,
+		ContentHash: message.ContentHash,
+	}
+
+	duplicates, err := u.findInvoicesConsumingEmail(ctx, bill, processingPeriod.Format("2006-01"), "", message.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicates) > 0 {
+		log.Printf("Message %s has the same content as the email of invoice %s. Flagging as duplicate", message.Id, duplicates[0].Id)
+		emailSource.DuplicateOf = duplicates[0].Id
+	}
+
+	return &extractedInvoice{amount: dataExtractorResponse.Amount, barcode: dataExtractorResponse.Barcode,
+		emailSource: emailSource}, nil
+}
+
+// findInvoicesConsumingEmail returns the invoices created from the given email, ignoring the
+// invoice of the same bill and period, which is the one being reprocessed.
+func (u *BillProcessingUseCase) findInvoicesConsumingEmail(ctx context.Context, bill *bill_entity.Bill, period string,
+	messageId string, contentHash string) ([]*invoice_entity.Invoice, *internal_error.InternalError) {
+
+	invoices, err := u.invoiceRepository.FindInvoicesByEmailSource(ctx, messageId, contentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]*invoice_entity.Invoice, 0)
+	for _, invoice := range invoices {
+		if invoice.BillId == bill.Id && invoice.Period == period {
+			continue
+		}
+		others = append(others, invoice)
+	}
+
+	return others, nil
 }
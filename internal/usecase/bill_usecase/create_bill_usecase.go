@@ -2,33 +2,98 @@ package bill_usecase
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/blob_storage"
+	"github.com/regismartiny/lembrador-contas-go/internal/calendar"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/attachment_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_processing_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/table_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+	"github.com/regismartiny/lembrador-contas-go/internal/transaction_manager"
 )
 
 type BillInputDTO struct {
-	UserId          string `json:"userId" binding:"required"`
-	Name            string `json:"name" binding:"required,min=3"`
-	Company         string `json:"company" binding:"required,min=3"`
-	ValueSourceType string `json:"valueSourceType" binding:"required"`
-	ValueSourceId   string `json:"valueSourceId" binding:"required"`
-	DueDay          uint8  `json:"dueDay" binding:"required"`
-	Status          string `json:"status"`
+	UserId          string             `json:"userId" binding:"required"`
+	Name            string             `json:"name" binding:"required,min=3"`
+	Company         string             `json:"company" binding:"required,min=3"`
+	ValueSourceType string             `json:"valueSourceType" binding:"required,oneof=table email api"`
+	ValueSourceId   string             `json:"valueSourceId" binding:"required"`
+	CategoryId      string             `json:"categoryId"`
+	Tags            []string           `json:"tags"`
+	DueDay          uint8              `json:"dueDay" binding:"required,min=1,max=31"`
+	Recurrence      *RecurrenceDTO     `json:"recurrence"`
+	DueDateRule     DueDateRuleDTO     `json:"dueDateRule"`
+	LatePaymentRule LatePaymentRuleDTO `json:"latePaymentRule"`
+	PaymentMode     string             `json:"paymentMode" binding:"omitempty,oneof=manual automatic_debit credit_card"`
+	Split           *SplitRuleDTO      `json:"split"`
+	Status          string             `json:"status"`
+}
+
+// RecurrenceDTO tells in which months the bill is due: monthly (the default), every_n_months
+// (interval and startMonth), yearly (month), specific_months (months) or one_off (startMonth).
+type RecurrenceDTO struct {
+	Type       string       `json:"type"`
+	Interval   uint8        `json:"interval,omitempty"`
+	Month      time.Month   `json:"month,omitempty"`
+	Months     []time.Month `json:"months,omitempty"`
+	StartMonth string       `json:"startMonth,omitempty"`
+}
+
+// DueDateRuleDTO moves due dates that are not business days to the next_business_day or to
+// the previous_business_day, considering the holidays of the state and city. The default is none.
+type DueDateRuleDTO struct {
+	Adjustment string `json:"adjustment"`
+	State      string `json:"state"`
+	City       string `json:"city"`
+}
+
+// LatePaymentRuleDTO configures the multa (one-off percentage) and the juros de mora
+// (percentage per month, charged pro rata die) of late invoices.
+type LatePaymentRuleDTO struct {
+	FinePercentage            float64 `json:"finePercentage"`
+	MonthlyInterestPercentage float64 `json:"monthlyInterestPercentage"`
+}
+
+// SplitRuleDTO shares the bill among users: equal parts, a percentage of each user (adding up to
+// 100) or a fixed amount of each user, the owner paying what is left.
+type SplitRuleDTO struct {
+	Type   string     `json:"type"`
+	Shares []ShareDTO `json:"shares"`
+}
+
+type ShareDTO struct {
+	UserId     string      `json:"userId"`
+	Percentage float64     `json:"percentage,omitempty"`
+	Amount     money.Money `json:"amount,omitempty"`
 }
 
 type CreateBillOutputDTO struct {
-	Id              string    `json:"id"`
-	UserId          string    `json:"userId"`
-	Name            string    `json:"name"`
-	Company         string    `json:"company"`
-	ValueSourceType string    `json:"valueSourceType"`
-	ValueSourceId   string    `json:"valueSourceId"`
-	DueDay          uint8     `json:"dueDay"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
-	UpdatedAt       time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+	Id              string             `json:"id"`
+	UserId          string             `json:"userId"`
+	Name            string             `json:"name"`
+	Company         string             `json:"company"`
+	ValueSourceType string             `json:"valueSourceType"`
+	ValueSourceId   string             `json:"valueSourceId"`
+	CategoryId      string             `json:"categoryId,omitempty"`
+	Tags            []string           `json:"tags,omitempty"`
+	DueDay          uint8              `json:"dueDay"`
+	Recurrence      RecurrenceDTO      `json:"recurrence"`
+	DueDateRule     DueDateRuleDTO     `json:"dueDateRule"`
+	LatePaymentRule LatePaymentRuleDTO `json:"latePaymentRule"`
+	PaymentMode     string             `json:"paymentMode"`
+	Split           *SplitRuleDTO      `json:"split,omitempty"`
+	Status          string             `json:"status"`
+	CreatedAt       time.Time          `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt       time.Time          `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
 }
 
 type BillUseCaseInterface interface {
@@ -37,22 +102,71 @@ type BillUseCaseInterface interface {
 		billInput BillInputDTO) *internal_error.InternalError
 	FindBillById(
 		ctx context.Context,
-		id string) (*BillOutputDTO, *internal_error.InternalError)
+		id string,
+		period string) (*BillOutputDTO, *internal_error.InternalError)
 	FindBills(
 		ctx context.Context,
-		status bill_entity.BillStatus,
-		userId string,
-		name, email string) ([]*BillOutputDTO, *internal_error.InternalError)
+		findBillsInput FindBillsInputDTO) ([]*BillOutputDTO, *internal_error.InternalError)
+	UpdateBill(
+		ctx context.Context,
+		id string,
+		billInput UpdateBillInputDTO) *internal_error.InternalError
+	UpdateBillStatus(
+		ctx context.Context,
+		id string,
+		statusInput UpdateBillStatusInputDTO) *internal_error.InternalError
+	DeleteBill(
+		ctx context.Context,
+		id string,
+		valueSource string) (*DeleteBillOutputDTO, *internal_error.InternalError)
+	AddBillSuspension(
+		ctx context.Context,
+		id string,
+		suspensionInput SuspensionInputDTO) (*SuspensionOutputDTO, *internal_error.InternalError)
+	DeleteBillSuspension(
+		ctx context.Context,
+		id string,
+		suspensionId string) *internal_error.InternalError
 }
 
 type BillUseCase struct {
-	billRepository bill_entity.BillRepositoryInterface
+	billRepository             bill_entity.BillRepositoryInterface
+	userRepository             user_entity.UserRepositoryInterface
+	categoryRepository         category_entity.CategoryRepositoryInterface
+	invoiceRepository          invoice_entity.InvoiceRepositoryInterface
+	attachmentRepository       attachment_entity.AttachmentRepositoryInterface
+	billProcessingRepository   bill_processing_entity.BillProcessingRepositoryInterface
+	tableValueSourceRepository table_value_source_entity.TableValueSourceRepositoryInterface
+	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface
+	blobStorage                blob_storage.BlobStorageInterface
+	transactionManager         transaction_manager.TransactionManagerInterface
+	calendar                   calendar.CalendarInterface
 }
 
 func NewBillUseCase(
-	billRepository bill_entity.BillRepositoryInterface) BillUseCaseInterface {
+	billRepository bill_entity.BillRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface,
+	categoryRepository category_entity.CategoryRepositoryInterface,
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	attachmentRepository attachment_entity.AttachmentRepositoryInterface,
+	billProcessingRepository bill_processing_entity.BillProcessingRepositoryInterface,
+	tableValueSourceRepository table_value_source_entity.TableValueSourceRepositoryInterface,
+	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface,
+	blobStorage blob_storage.BlobStorageInterface,
+	transactionManager transaction_manager.TransactionManagerInterface,
+	calendar calendar.CalendarInterface) BillUseCaseInterface {
 	return &BillUseCase{
-		billRepository: billRepository,
+		billRepository:             billRepository,
+		userRepository:             userRepository,
+		categoryRepository:         categoryRepository,
+		invoiceRepository:          invoiceRepository,
+		attachmentRepository:       attachmentRepository,
+		billProcessingRepository:   billProcessingRepository,
+		tableValueSourceRepository: tableValueSourceRepository,
+		emailValueSourceRepository: emailValueSourceRepository,
+		blobStorage:                blobStorage,
+		transactionManager:         transactionManager,
+		calendar:                   calendar,
 	}
 }
 
@@ -60,14 +174,173 @@ func (u *BillUseCase) CreateBill(
 	ctx context.Context,
 	billInput BillInputDTO) *internal_error.InternalError {
 
-	bill, err := bill_entity.CreateBill(billInput.UserId, billInput.Name, billInput.Company, billInput.ValueSourceType, billInput.ValueSourceId, billInput.DueDay, billInput.Status)
+	recurrence, err := toRecurrence(billInput.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	dueDateRule, err := bill_entity.CreateDueDateRule(billInput.DueDateRule.Adjustment, billInput.DueDateRule.State, billInput.DueDateRule.City)
+	if err != nil {
+		return err
+	}
+
+	split, err := toSplitRule(billInput.Split)
+	if err != nil {
+		return err
+	}
+
+	bill, err := bill_entity.CreateBill(billInput.UserId, billInput.Name, billInput.Company, billInput.ValueSourceType, billInput.ValueSourceId,
+		billInput.CategoryId, billInput.Tags, billInput.DueDay,
+		recurrence, dueDateRule, bill_entity.LatePaymentRule(billInput.LatePaymentRule), billInput.PaymentMode, split, billInput.Status)
 	if err != nil {
 		return err
 	}
 
+	if err := u.verifyReferences(ctx, bill); err != nil {
+		return err
+	}
+
 	if err := u.billRepository.CreateBill(ctx, bill); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// toRecurrence returns the recurrence of the input, monthly when it is not informed.
+func toRecurrence(recurrenceInput *RecurrenceDTO) (bill_entity.Recurrence, *internal_error.InternalError) {
+	if recurrenceInput == nil {
+		return bill_entity.Recurrence{Type: bill_entity.Monthly}, nil
+	}
+
+	return bill_entity.CreateRecurrence(recurrenceInput.Type, recurrenceInput.Interval, recurrenceInput.Month,
+		recurrenceInput.Months, recurrenceInput.StartMonth)
+}
+
+// toSplitRule returns the split rule of the input, none when it is not informed.
+func toSplitRule(splitInput *SplitRuleDTO) (bill_entity.SplitRule, *internal_error.InternalError) {
+	if splitInput == nil {
+		return bill_entity.SplitRule{}, nil
+	}
+
+	shares := make([]bill_entity.Share, len(splitInput.Shares))
+	for i, share := range splitInput.Shares {
+		shares[i] = bill_entity.Share(share)
+	}
+
+	return bill_entity.CreateSplitRule(splitInput.Type, shares)
+}
+
+// verifyReferences fails when the users, the value source or the category of the bill do not
+// exist, when the owner or a user of the split is not a member of the household, or when the
+// value source is not of the bill's value source type.
+func (u *BillUseCase) verifyReferences(ctx context.Context, bill *bill_entity.Bill) *internal_error.InternalError {
+	causes := make([]internal_error.Cause, 0)
+
+	cause, err := u.verifyUser(ctx, "userId", bill.UserId)
+	if err != nil {
+		return err
+	}
+	if cause != nil {
+		causes = append(causes, *cause)
+	}
+
+	for i, share := range bill.Split.Shares {
+		cause, err := u.verifyUser(ctx, fmt.Sprintf("split.shares[%d].userId", i), share.UserId)
+		if err != nil {
+			return err
+		}
+		if cause != nil {
+			causes = append(causes, *cause)
+		}
+	}
+
+	cause, err = u.verifyValueSource(ctx, bill.ValueSourceType, bill.ValueSourceId)
+	if err != nil {
+		return err
+	}
+	if cause != nil {
+		causes = append(causes, *cause)
+	}
+
+	if bill.CategoryId != "" {
+		if _, err := u.categoryRepository.FindCategoryById(ctx, bill.CategoryId); err != nil {
+			if err.Err != "not_found" {
+				return err
+			}
+			causes = append(causes, internal_error.Cause{Field: "categoryId", Message: "category not found"})
+		}
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewBadRequestError("invalid bill object. referenced records not found", causes...)
+	}
+
+	return nil
+}
+
+// verifyUser returns the cause when the user does not exist or, for requests bound to a household,
+// did not join it.
+func (u *BillUseCase) verifyUser(
+	ctx context.Context,
+	field string,
+	userId string) (*internal_error.Cause, *internal_error.InternalError) {
+
+	if _, err := u.userRepository.FindUserById(ctx, userId); err != nil {
+		if err.Err != "not_found" {
+			return nil, err
+		}
+		return &internal_error.Cause{Field: field, Message: "user not found"}, nil
+	}
+
+	if scope, found := household_entity.ScopeFromContext(ctx); !found || !scope.HasMember(userId) {
+		return &internal_error.Cause{Field: field, Message: "user is not a member of the household"}, nil
+	}
+
+	return nil, nil
+}
+
+// verifyValueSource returns the cause when the value source is not found among the value sources
+// of its type, telling when it is a value source of the other type.
+func (u *BillUseCase) verifyValueSource(
+	ctx context.Context,
+	valueSourceType bill_entity.ValueSourceType,
+	valueSourceId string) (*internal_error.Cause, *internal_error.InternalError) {
+
+	findTable := func() *internal_error.InternalError {
+		_, err := u.tableValueSourceRepository.FindTableValueSourceById(ctx, valueSourceId)
+		return err
+	}
+	findEmail := func() *internal_error.InternalError {
+		_, err := u.emailValueSourceRepository.FindEmailValueSourceById(ctx, valueSourceId)
+		return err
+	}
+
+	var find, findOther func() *internal_error.InternalError
+	switch valueSourceType {
+	case bill_entity.Table:
+		find, findOther = findTable, findEmail
+	case bill_entity.Email:
+		find, findOther = findEmail, findTable
+	default:
+		// API value sources are not registered
+		return nil, nil
+	}
+
+	err := find()
+	if err == nil {
+		return nil, nil
+	}
+	if err.Err != "not_found" {
+		return nil, err
+	}
+
+	message := valueSourceType.Name() + " value source not found"
+	if err := findOther(); err == nil {
+		message = "value source is not a " + valueSourceType.Name() + " value source"
+	} else if err.Err != "not_found" {
+		return nil, err
+	}
+
+	return &internal_error.Cause{Field: "valueSourceId", Message: message}, nil
+}
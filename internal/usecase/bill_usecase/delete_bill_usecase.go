@@ -0,0 +1,161 @@
+package bill_usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/attachment_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	// the value source is kept, even when no other bill uses it
+	KEEP_VALUE_SOURCE = "keep"
+	// the value source is deleted with the bill. Only allowed when no other bill uses it
+	DELETE_VALUE_SOURCE = "delete"
+)
+
+type DeleteBillOutputDTO struct {
+	DeletedInvoices    int  `json:"deletedInvoices"`
+	KeptInvoices       int  `json:"keptInvoices"`
+	ValueSourceDeleted bool `json:"valueSourceDeleted"`
+}
+
+// DeleteBill removes the bill and its unpaid invoices not yet due, with their attachments.
+// Invoices with payments and past ones are kept as history. The value source is kept unless
+// valueSource is "delete". Bills cannot be deleted while a processing is in progress, since it
+// may be creating invoices for them.
+func (u *BillUseCase) DeleteBill(
+	ctx context.Context,
+	id string,
+	valueSource string) (*DeleteBillOutputDTO, *internal_error.InternalError) {
+
+	if valueSource == "" {
+		valueSource = KEEP_VALUE_SOURCE
+	}
+	if valueSource != KEEP_VALUE_SOURCE && valueSource != DELETE_VALUE_SOURCE {
+		return nil, internal_error.NewBadRequestError("invalid valueSource. expected keep or delete")
+	}
+
+	bill, err := u.billRepository.FindBillById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteValueSource := valueSource == DELETE_VALUE_SOURCE
+	if deleteValueSource {
+		if err := u.verifyDedicatedValueSource(ctx, bill); err != nil {
+			return nil, err
+		}
+	}
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{BillIds: []string{bill.Id}})
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	output := &DeleteBillOutputDTO{}
+	removedAttachments := make([]*attachment_entity.Attachment, 0)
+
+	err = u.transactionManager.WithTransaction(ctx, func(ctx context.Context) *internal_error.InternalError {
+		output.DeletedInvoices, output.KeptInvoices = 0, 0
+		removedAttachments = removedAttachments[:0]
+
+		// starting a processing takes the same lock, so a processing started concurrently makes
+		// this transaction run again and find it in progress
+		if err := u.billProcessingRepository.LockProcessings(ctx); err != nil {
+			return err
+		}
+
+		count, err := u.billProcessingRepository.GetProcessingsInProgressCount(ctx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return internal_error.NewBadRequestError("Bills cannot be deleted while a bill processing is in progress")
+		}
+
+		for _, invoice := range invoices {
+			if invoice.Status != invoice_entity.Unpaid || len(invoice.Payments) > 0 || invoice.DueDate < today {
+				output.KeptInvoices++
+				continue
+			}
+
+			attachments, err := u.attachmentRepository.FindAttachments(ctx, invoice.Id)
+			if err != nil {
+				return err
+			}
+			for _, attachment := range attachments {
+				if err := u.attachmentRepository.DeleteAttachment(ctx, attachment.Id); err != nil {
+					return err
+				}
+			}
+
+			if err := u.invoiceRepository.DeleteInvoice(ctx, invoice.Id); err != nil {
+				return err
+			}
+
+			removedAttachments = append(removedAttachments, attachments...)
+			output.DeletedInvoices++
+		}
+
+		if deleteValueSource {
+			if err := u.deleteValueSource(ctx, bill); err != nil {
+				return err
+			}
+			output.ValueSourceDeleted = true
+		}
+
+		return u.billRepository.DeleteBill(ctx, bill.Id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the files are removed only after the records are gone, so a failed deletion leaves no
+	// attachment pointing to a missing file
+	for _, attachment := range removedAttachments {
+		if err := u.blobStorage.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Printf("Error deleting attachment file %s: %s", attachment.StorageKey, err.Message)
+		}
+	}
+
+	log.Printf("Bill %s deleted. Invoices deleted: %d, kept: %d", bill.Id, output.DeletedInvoices, output.KeptInvoices)
+
+	return output, nil
+}
+
+// verifyDedicatedValueSource fails when another bill uses the value source of the bill.
+func (u *BillUseCase) verifyDedicatedValueSource(ctx context.Context, bill *bill_entity.Bill) *internal_error.InternalError {
+	if bill.ValueSourceType != bill_entity.Table && bill.ValueSourceType != bill_entity.Email {
+		return internal_error.NewBadRequestError("The value source of the bill cannot be deleted")
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, other := range bills {
+		if other.Id != bill.Id && other.ValueSourceType == bill.ValueSourceType && other.ValueSourceId == bill.ValueSourceId {
+			return internal_error.NewBadRequestError("The value source is used by other bills and cannot be deleted")
+		}
+	}
+
+	return nil
+}
+
+func (u *BillUseCase) deleteValueSource(ctx context.Context, bill *bill_entity.Bill) *internal_error.InternalError {
+	switch bill.ValueSourceType {
+	case bill_entity.Table:
+		return u.tableValueSourceRepository.DeleteTableValueSource(ctx, bill.ValueSourceId)
+	case bill_entity.Email:
+		return u.emailValueSourceRepository.DeleteEmailValueSource(ctx, bill.ValueSourceId)
+	}
+
+	return nil
+}
@@ -4,76 +4,224 @@ import (
 	"context"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/calendar"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 )
 
 func FindBillUseCase(billRepository bill_entity.BillRepositoryInterface) BillUseCaseInterface {
 	return &BillUseCase{
-		billRepository,
+		billRepository: billRepository,
 	}
 }
 
+// FindBillsInputDTO filters the bills. CategoryId selects the bills of the category and of its
+// subcategories. Period (YYYY-MM, the current month by default) is the competence period whose
+// suspension is reported in SuspendedUntil.
+type FindBillsInputDTO struct {
+	Status     bill_entity.BillStatus
+	UserId     string
+	Name       string
+	Company    string
+	CategoryId string
+	Tag        string
+	Period     string
+}
+
 type BillOutputDTO struct {
-	Id              string    `json:"id"`
-	UserId          string    `json:"userId"`
-	Name            string    `json:"name"`
-	Company         string    `json:"company"`
-	ValueSourceType string    `json:"valueSourceType"`
-	ValueSourceId   string    `json:"valueSourceId"`
-	DueDay          uint8     `json:"dueDay"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
-	UpdatedAt       time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+	Id              string                 `json:"id"`
+	UserId          string                 `json:"userId"`
+	Name            string                 `json:"name"`
+	Company         string                 `json:"company"`
+	ValueSourceType string                 `json:"valueSourceType"`
+	ValueSourceId   string                 `json:"valueSourceId"`
+	CategoryId      string                 `json:"categoryId,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
+	DueDay          uint8                  `json:"dueDay"`
+	Recurrence      RecurrenceDTO          `json:"recurrence"`
+	NextDueDate     string                 `json:"nextDueDate,omitempty"`
+	Reminder        string                 `json:"reminder,omitempty"`
+	DueDateRule     DueDateRuleDTO         `json:"dueDateRule"`
+	LatePaymentRule LatePaymentRuleDTO     `json:"latePaymentRule"`
+	PaymentMode     string                 `json:"paymentMode"`
+	Split           *SplitRuleDTO          `json:"split,omitempty"`
+	Suspensions     []*SuspensionOutputDTO `json:"suspensions,omitempty"`
+	SuspendedUntil  string                 `json:"suspendedUntil,omitempty"`
+	Status          string                 `json:"status"`
+	CreatedAt       time.Time              `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt       time.Time              `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
 }
 
+// FindBillById returns the bill, reporting in SuspendedUntil its suspension in the period
+// (YYYY-MM, the current month by default).
 func (u *BillUseCase) FindBillById(
-	ctx context.Context, id string) (*BillOutputDTO, *internal_error.InternalError) {
+	ctx context.Context, id string, period string) (*BillOutputDTO, *internal_error.InternalError) {
+	period, err := suspensionPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
 	billEntity, err := u.billRepository.FindBillById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BillOutputDTO{
+	businessDays, err := u.calendar.BusinessDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBillOutputDTO(billEntity, nextDueDate(billEntity, businessDays), period), nil
+}
+
+func (u *BillUseCase) FindBills(
+	ctx context.Context,
+	findBillsInput FindBillsInputDTO) ([]*BillOutputDTO, *internal_error.InternalError) {
+
+	period, err := suspensionPeriod(findBillsInput.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bill_entity.BillFilter{
+		Status:  findBillsInput.Status,
+		UserId:  findBillsInput.UserId,
+		Name:    findBillsInput.Name,
+		Company: findBillsInput.Company,
+		Tag:     findBillsInput.Tag,
+	}
+
+	if findBillsInput.CategoryId != "" {
+		categories, err := u.categoryRepository.FindCategories(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filter.CategoryIds = category_entity.Subtree(categories, findBillsInput.CategoryId)
+	}
+
+	billEntities, err := u.billRepository.FindBills(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// the holidays are loaded once for all the bills
+	businessDays, err := u.calendar.BusinessDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	billOutputs := make([]*BillOutputDTO, len(billEntities))
+	for i, value := range billEntities {
+		billOutputs[i] = toBillOutputDTO(value, nextDueDate(value, businessDays), period)
+	}
+
+	return billOutputs, nil
+}
+
+// nextDueDate returns the next due date of the bill, adjusted to the business days of its city,
+// or an empty string when the bill is not due anymore.
+func nextDueDate(bill *bill_entity.Bill, businessDays *calendar.BusinessDays) string {
+	today := time.Now()
+
+	for from := today; ; {
+		dueDate, found := bill.NextDueDate(from)
+		if !found {
+			return ""
+		}
+
+		adjusted := businessDays.AdjustDueDate(dueDate, bill.DueDateRule)
+
+		// moved to a previous business day that already passed
+		if adjusted.Format("2006-01-02") < today.Format("2006-01-02") {
+			from = dueDate.AddDate(0, 0, 1)
+			continue
+		}
+
+		return adjusted.Format("2006-01-02")
+	}
+}
+
+// suspensionPeriod returns the period, or the current one when empty.
+func suspensionPeriod(period string) (string, *internal_error.InternalError) {
+	if period == "" {
+		return time.Now().Format("2006-01"), nil
+	}
+
+	if _, e := time.Parse("2006-01", period); e != nil {
+		return "", internal_error.NewBadRequestError("invalid period. expected format YYYY-MM",
+			internal_error.Cause{Field: "period", Message: "period must be in the format YYYY-MM"})
+	}
+
+	return period, nil
+}
+
+func toBillOutputDTO(billEntity *bill_entity.Bill, nextDueDate string, period string) *BillOutputDTO {
+	output := &BillOutputDTO{
 		Id:              billEntity.Id,
 		UserId:          billEntity.UserId,
 		Name:            billEntity.Name,
 		Company:         billEntity.Company,
 		ValueSourceType: bill_entity.ValueSourceType(billEntity.ValueSourceType).Name(),
 		ValueSourceId:   billEntity.ValueSourceId,
+		CategoryId:      billEntity.CategoryId,
+		Tags:            billEntity.Tags,
 		DueDay:          billEntity.DueDay,
+		Recurrence: RecurrenceDTO{
+			Type:       billEntity.Recurrence.Type.Name(),
+			Interval:   billEntity.Recurrence.Interval,
+			Month:      billEntity.Recurrence.Month,
+			Months:     billEntity.Recurrence.Months,
+			StartMonth: billEntity.Recurrence.StartMonth,
+		},
+		NextDueDate: nextDueDate,
+		DueDateRule: DueDateRuleDTO{
+			Adjustment: billEntity.DueDateRule.Adjustment.Name(),
+			State:      billEntity.DueDateRule.State,
+			City:       billEntity.DueDateRule.City,
+		},
+		LatePaymentRule: LatePaymentRuleDTO(billEntity.LatePaymentRule),
+		PaymentMode:     billEntity.PaymentMode.Name(),
 		Status:          bill_entity.BillStatus(billEntity.Status).Name(),
 		CreatedAt:       billEntity.CreatedAt,
 		UpdatedAt:       billEntity.UpdatedAt,
-	}, nil
-}
+	}
 
-func (u *BillUseCase) FindBills(
-	ctx context.Context,
-	status bill_entity.BillStatus,
-	userId string,
-	name, company string) ([]*BillOutputDTO, *internal_error.InternalError) {
+	if billEntity.Split.IsShared() {
+		output.Split = &SplitRuleDTO{
+			Type:   billEntity.Split.Type.Name(),
+			Shares: make([]ShareDTO, len(billEntity.Split.Shares)),
+		}
+		for i, share := range billEntity.Split.Shares {
+			output.Split.Shares[i] = ShareDTO(share)
+		}
+	}
 
-	billEntities, err := u.billRepository.FindBills(ctx, status, userId, name, company)
-	if err != nil {
-		return nil, err
+	if nextDueDate != "" {
+		output.Reminder = reminder(billEntity.PaymentMode)
 	}
 
-	billOutputs := make([]*BillOutputDTO, len(billEntities))
-	for i, value := range billEntities {
-		billOutputs[i] = &BillOutputDTO{
-			Id:              value.Id,
-			UserId:          value.UserId,
-			Name:            value.Name,
-			Company:         value.Company,
-			ValueSourceType: bill_entity.ValueSourceType(value.ValueSourceType).Name(),
-			ValueSourceId:   value.ValueSourceId,
-			DueDay:          value.DueDay,
-			Status:          bill_entity.BillStatus(value.Status).Name(),
-			CreatedAt:       value.CreatedAt,
-			UpdatedAt:       value.UpdatedAt,
-		}
+	for _, suspension := range billEntity.Suspensions {
+		output.Suspensions = append(output.Suspensions, toSuspensionOutputDTO(&suspension))
 	}
 
-	return billOutputs, nil
+	// the bill is active again, with no action, once the window of the period ends
+	if suspension := billEntity.SuspensionIn(period); suspension != nil {
+		output.SuspendedUntil = suspension.ToPeriod
+	}
+
+	return output
+}
+
+// reminder tells what is expected on the next due date: to pay the invoice, or only to check
+// that the automatic debit or the card charge happened.
+func reminder(mode bill_entity.PaymentMode) string {
+	switch mode {
+	case bill_entity.AutomaticDebit:
+		return "check_automatic_debit"
+	case bill_entity.CreditCard:
+		return "check_card_charge"
+	default:
+		return "pay"
+	}
 }
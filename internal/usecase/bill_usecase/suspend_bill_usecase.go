@@ -0,0 +1,76 @@
+package bill_usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// SuspensionInputDTO suspends the bill in the competence periods (YYYY-MM) from From to To.
+type SuspensionInputDTO struct {
+	From   string `json:"from" binding:"required"`
+	To     string `json:"to" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type SuspensionOutputDTO struct {
+	Id        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u *BillUseCase) AddBillSuspension(
+	ctx context.Context,
+	id string,
+	suspensionInput SuspensionInputDTO) (*SuspensionOutputDTO, *internal_error.InternalError) {
+
+	bill, err := u.billRepository.FindBillById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	suspension, err := bill.AddSuspension(suspensionInput.From, suspensionInput.To, suspensionInput.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// the overlap is checked again when saving, against the suspensions added meanwhile
+	if err := u.billRepository.AddBillSuspension(ctx, bill.Id, *suspension); err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("invalid bill suspension",
+				internal_error.Cause{Field: "from", Message: "the bill is already suspended in the periods"})
+		}
+		return nil, err
+	}
+
+	log.Printf("Bill %s suspended from %s to %s", bill.Id, suspension.FromPeriod, suspension.ToPeriod)
+
+	return toSuspensionOutputDTO(suspension), nil
+}
+
+func (u *BillUseCase) DeleteBillSuspension(
+	ctx context.Context,
+	id string,
+	suspensionId string) *internal_error.InternalError {
+
+	if _, err := u.billRepository.FindBillById(ctx, id); err != nil {
+		return err
+	}
+
+	return u.billRepository.RemoveBillSuspension(ctx, id, suspensionId)
+}
+
+func toSuspensionOutputDTO(suspension *bill_entity.Suspension) *SuspensionOutputDTO {
+	return &SuspensionOutputDTO{
+		Id:        suspension.Id,
+		From:      suspension.FromPeriod,
+		To:        suspension.ToPeriod,
+		Reason:    suspension.Reason,
+		CreatedAt: suspension.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package bill_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type UpdateBillInputDTO struct {
+	Name            string              `json:"name" binding:"omitempty,min=3"`
+	Company         string              `json:"company" binding:"omitempty,min=3"`
+	ValueSourceType string              `json:"valueSourceType" binding:"omitempty,oneof=table email api"`
+	ValueSourceId   string              `json:"valueSourceId"`
+	CategoryId      *string             `json:"categoryId"`
+	Tags            []string            `json:"tags"`
+	DueDay          uint8               `json:"dueDay" binding:"omitempty,min=1,max=31"`
+	Recurrence      *RecurrenceDTO      `json:"recurrence"`
+	DueDateRule     *DueDateRuleDTO     `json:"dueDateRule"`
+	LatePaymentRule *LatePaymentRuleDTO `json:"latePaymentRule"`
+	PaymentMode     string              `json:"paymentMode" binding:"omitempty,oneof=manual automatic_debit credit_card"`
+	Split           *SplitRuleDTO       `json:"split"` // an empty split makes the bill not shared
+}
+
+type UpdateBillStatusInputDTO struct {
+	Status string `json:"status" binding:"required,oneof=active inactive"`
+}
+
+func (u *BillUseCase) UpdateBill(
+	ctx context.Context,
+	id string,
+	billInput UpdateBillInputDTO) *internal_error.InternalError {
+
+	bill, err := u.billRepository.FindBillById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var recurrence *bill_entity.Recurrence
+	if billInput.Recurrence != nil {
+		billRecurrence, err := toRecurrence(billInput.Recurrence)
+		if err != nil {
+			return err
+		}
+		recurrence = &billRecurrence
+	}
+
+	var dueDateRule *bill_entity.DueDateRule
+	if billInput.DueDateRule != nil {
+		billDueDateRule, err := bill_entity.CreateDueDateRule(billInput.DueDateRule.Adjustment,
+			billInput.DueDateRule.State, billInput.DueDateRule.City)
+		if err != nil {
+			return err
+		}
+		dueDateRule = &billDueDateRule
+	}
+
+	var latePaymentRule *bill_entity.LatePaymentRule
+	if billInput.LatePaymentRule != nil {
+		rule := bill_entity.LatePaymentRule(*billInput.LatePaymentRule)
+		latePaymentRule = &rule
+	}
+
+	var split *bill_entity.SplitRule
+	if billInput.Split != nil {
+		billSplit, err := toSplitRule(billInput.Split)
+		if err != nil {
+			return err
+		}
+		split = &billSplit
+	}
+
+	if err := bill.Update(billInput.Name, billInput.Company, billInput.ValueSourceType, billInput.ValueSourceId,
+		billInput.CategoryId, billInput.Tags, billInput.DueDay, recurrence, dueDateRule, latePaymentRule,
+		billInput.PaymentMode, split); err != nil {
+		return err
+	}
+
+	if err := u.verifyReferences(ctx, bill); err != nil {
+		return err
+	}
+
+	return u.billRepository.UpdateBill(ctx, bill)
+}
+
+func (u *BillUseCase) UpdateBillStatus(
+	ctx context.Context,
+	id string,
+	statusInput UpdateBillStatusInputDTO) *internal_error.InternalError {
+
+	bill, err := u.billRepository.FindBillById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := bill.UpdateStatus(statusInput.Status); err != nil {
+		return err
+	}
+
+	return u.billRepository.UpdateBill(ctx, bill)
+}
@@ -0,0 +1,96 @@
+package category_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+type CategoryInputDTO struct {
+	Name          string      `json:"name" binding:"required,min=2"`
+	ParentId      string      `json:"parentId"`
+	MonthlyBudget money.Money `json:"monthlyBudget"`
+}
+
+type CreateCategoryOutputDTO struct {
+	Id string `json:"id"`
+}
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(
+		ctx context.Context,
+		categoryInput CategoryInputDTO) (*CreateCategoryOutputDTO, *internal_error.InternalError)
+	FindCategoryById(
+		ctx context.Context,
+		id string) (*CategoryOutputDTO, *internal_error.InternalError)
+	FindCategories(
+		ctx context.Context) ([]*CategoryOutputDTO, *internal_error.InternalError)
+	UpdateCategory(
+		ctx context.Context,
+		id string,
+		categoryInput UpdateCategoryInputDTO) *internal_error.InternalError
+	DeleteCategory(
+		ctx context.Context,
+		id string) *internal_error.InternalError
+	FindCategoryBudgets(
+		ctx context.Context,
+		period string) ([]*CategoryBudgetOutputDTO, *internal_error.InternalError)
+}
+
+type CategoryUseCase struct {
+	categoryRepository category_entity.CategoryRepositoryInterface
+	billRepository     bill_entity.BillRepositoryInterface
+	invoiceRepository  invoice_entity.InvoiceRepositoryInterface
+}
+
+func NewCategoryUseCase(
+	categoryRepository category_entity.CategoryRepositoryInterface,
+	billRepository bill_entity.BillRepositoryInterface,
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface) CategoryUseCaseInterface {
+	return &CategoryUseCase{
+		categoryRepository: categoryRepository,
+		billRepository:     billRepository,
+		invoiceRepository:  invoiceRepository,
+	}
+}
+
+func (u *CategoryUseCase) CreateCategory(
+	ctx context.Context,
+	categoryInput CategoryInputDTO) (*CreateCategoryOutputDTO, *internal_error.InternalError) {
+
+	if err := u.verifyParent(ctx, categoryInput.ParentId); err != nil {
+		return nil, err
+	}
+
+	category, err := category_entity.CreateCategory(categoryInput.Name, categoryInput.ParentId, categoryInput.MonthlyBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.categoryRepository.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return &CreateCategoryOutputDTO{Id: category.Id}, nil
+}
+
+// verifyParent fails when the parent category informed does not exist.
+func (u *CategoryUseCase) verifyParent(ctx context.Context, parentId string) *internal_error.InternalError {
+	if parentId == "" {
+		return nil
+	}
+
+	if _, err := u.categoryRepository.FindCategoryById(ctx, parentId); err != nil {
+		if err.Err == "not_found" {
+			return internal_error.NewBadRequestError("invalid category object. parent category not found",
+				internal_error.Cause{Field: "parentId", Message: "parent category not found"})
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package category_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// DeleteCategory removes a category without subcategories and bills.
+func (u *CategoryUseCase) DeleteCategory(
+	ctx context.Context,
+	id string) *internal_error.InternalError {
+
+	if _, err := u.categoryRepository.FindCategoryById(ctx, id); err != nil {
+		return err
+	}
+
+	categories, err := u.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if category.ParentId == id {
+			return internal_error.NewBadRequestError("The category has subcategories and cannot be deleted")
+		}
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{CategoryIds: []string{id}})
+	if err != nil {
+		return err
+	}
+	if len(bills) > 0 {
+		return internal_error.NewBadRequestError("The category has bills and cannot be deleted")
+	}
+
+	return u.categoryRepository.DeleteCategory(ctx, id)
+}
@@ -0,0 +1,67 @@
+package category_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+type CategoryBudgetOutputDTO struct {
+	CategoryId    string      `json:"categoryId"`
+	Name          string      `json:"name"`
+	Period        string      `json:"period"`
+	MonthlyBudget money.Money `json:"monthlyBudget"`
+	Spent         money.Money `json:"spent"`
+	Remaining     money.Money `json:"remaining"`
+	Exceeded      bool        `json:"exceeded"`
+	Alert         string      `json:"alert,omitempty"`
+}
+
+// FindCategoryBudgets compares the invoices of the competence period (YYYY-MM) with the monthly
+// budget of each category, reporting the exceeded ones.
+func (u *CategoryUseCase) FindCategoryBudgets(
+	ctx context.Context,
+	period string) ([]*CategoryBudgetOutputDTO, *internal_error.InternalError) {
+
+	if _, e := time.Parse("2006-01", period); e != nil {
+		return nil, internal_error.NewBadRequestError("invalid period. expected format YYYY-MM")
+	}
+
+	categories, err := u.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{FromPeriod: period, ToPeriod: period})
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := category_entity.CalculateBudgets(categories, period, bills, invoices)
+
+	budgetOutputs := make([]*CategoryBudgetOutputDTO, len(budgets))
+	for i, budget := range budgets {
+		budgetOutputs[i] = &CategoryBudgetOutputDTO{
+			CategoryId:    budget.Category.Id,
+			Name:          budget.Category.Name,
+			Period:        budget.Period,
+			MonthlyBudget: budget.Category.MonthlyBudget,
+			Spent:         budget.Spent,
+			Remaining:     budget.Remaining(),
+			Exceeded:      budget.Exceeded(),
+			Alert:         budget.Alert(),
+		}
+	}
+
+	return budgetOutputs, nil
+}
@@ -0,0 +1,57 @@
+package category_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+type CategoryOutputDTO struct {
+	Id            string      `json:"id"`
+	Name          string      `json:"name"`
+	ParentId      string      `json:"parentId,omitempty"`
+	MonthlyBudget money.Money `json:"monthlyBudget"`
+	CreatedAt     time.Time   `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt     time.Time   `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u *CategoryUseCase) FindCategoryById(
+	ctx context.Context, id string) (*CategoryOutputDTO, *internal_error.InternalError) {
+
+	categoryEntity, err := u.categoryRepository.FindCategoryById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCategoryOutputDTO(categoryEntity), nil
+}
+
+func (u *CategoryUseCase) FindCategories(
+	ctx context.Context) ([]*CategoryOutputDTO, *internal_error.InternalError) {
+
+	categoryEntities, err := u.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryOutputs := make([]*CategoryOutputDTO, len(categoryEntities))
+	for i, value := range categoryEntities {
+		categoryOutputs[i] = toCategoryOutputDTO(value)
+	}
+
+	return categoryOutputs, nil
+}
+
+func toCategoryOutputDTO(categoryEntity *category_entity.Category) *CategoryOutputDTO {
+	return &CategoryOutputDTO{
+		Id:            categoryEntity.Id,
+		Name:          categoryEntity.Name,
+		ParentId:      categoryEntity.ParentId,
+		MonthlyBudget: categoryEntity.MonthlyBudget,
+		CreatedAt:     categoryEntity.CreatedAt,
+		UpdatedAt:     categoryEntity.UpdatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package category_usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+// UpdateCategoryInputDTO changes the informed fields. An empty parentId moves the category to
+// the top level.
+type UpdateCategoryInputDTO struct {
+	Name          string       `json:"name"`
+	ParentId      *string      `json:"parentId"`
+	MonthlyBudget *money.Money `json:"monthlyBudget"`
+}
+
+func (u *CategoryUseCase) UpdateCategory(
+	ctx context.Context,
+	id string,
+	categoryInput UpdateCategoryInputDTO) *internal_error.InternalError {
+
+	category, err := u.categoryRepository.FindCategoryById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if categoryInput.ParentId != nil && *categoryInput.ParentId != category.ParentId {
+		if err := u.verifyParent(ctx, *categoryInput.ParentId); err != nil {
+			return err
+		}
+
+		categories, err := u.categoryRepository.FindCategories(ctx)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(category_entity.Subtree(categories, category.Id), *categoryInput.ParentId) {
+			return internal_error.NewBadRequestError("invalid category object. a category cannot be moved below its own subcategories",
+				internal_error.Cause{Field: "parentId", Message: "parentId must not be a subcategory of the category"})
+		}
+	}
+
+	if err := category.Update(categoryInput.Name, categoryInput.ParentId, categoryInput.MonthlyBudget); err != nil {
+		return err
+	}
+
+	return u.categoryRepository.UpdateCategory(ctx, category)
+}
@@ -3,14 +3,19 @@ package email_value_source_usecase
 import (
 	"context"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 )
 
 type EmailValueSourceInputDTO struct {
-	Address       string `json:"address" binding:"required,min=5"`
-	Subject       string `json:"subject" binding:"required,min=3"`
-	DataExtractor string `json:"dataExtractor" binding:"required"`
+	MailAccountId string   `json:"mailAccountId" binding:"required"`
+	Address       string   `json:"address" binding:"required,min=5"`
+	Subject       string   `json:"subject" binding:"required,min=3"`
+	Labels        []string `json:"labels"`
+	Exclude       []string `json:"exclude"`
+	DataExtractor string   `json:"dataExtractor" binding:"required"`
 }
 
 type EmailValueSourceUseCaseInterface interface {
@@ -32,12 +37,18 @@ type EmailValueSourceUseCaseInterface interface {
 
 type EmailValueSourceUseCase struct {
 	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface
+	mailAccountRepository      mail_account_entity.MailAccountRepositoryInterface
+	emailServiceResolver       email_service.EmailServiceResolverInterface
 }
 
 func NewEmailValueSourceUseCase(
-	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface) EmailValueSourceUseCaseInterface {
+	emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface,
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface,
+	emailServiceResolver email_service.EmailServiceResolverInterface) EmailValueSourceUseCaseInterface {
 	return &EmailValueSourceUseCase{
 		emailValueSourceRepository: emailValueSourceRepository,
+		mailAccountRepository:      mailAccountRepository,
+		emailServiceResolver:       emailServiceResolver,
 	}
 }
 
@@ -45,8 +56,13 @@ func (u *EmailValueSourceUseCase) CreateEmailValueSource(
 	ctx context.Context,
 	emailValueSourceInput EmailValueSourceInputDTO) *internal_error.InternalError {
 
+	if err := u.verifyMailAccount(ctx, emailValueSourceInput.MailAccountId); err != nil {
+		return err
+	}
+
 	emailValueSource, err := email_value_source_entity.
-		CreateEmailValueSource(emailValueSourceInput.Address, emailValueSourceInput.Subject, emailValueSourceInput.DataExtractor)
+		CreateEmailValueSource(emailValueSourceInput.MailAccountId, emailValueSourceInput.Address, emailValueSourceInput.Subject, emailValueSourceInput.Labels,
+			emailValueSourceInput.Exclude, emailValueSourceInput.DataExtractor)
 	if err != nil {
 		return err
 	}
@@ -57,3 +73,31 @@ func (u *EmailValueSourceUseCase) CreateEmailValueSource(
 
 	return nil
 }
+
+// verifyMailAccount checks that the mail account belongs to the household of the request, as the
+// mail account repository only finds the accounts of the household.
+func (u *EmailValueSourceUseCase) verifyMailAccount(ctx context.Context, mailAccountId string) *internal_error.InternalError {
+	if mailAccountId == "" {
+		return internal_error.NewBadRequestError("invalid emailValueSource object. mail account is required",
+			internal_error.Cause{Field: "mailAccountId", Message: "mailAccountId is required"})
+	}
+
+	if _, err := u.mailAccountRepository.FindMailAccountById(ctx, mailAccountId); err != nil {
+		if err.Err == "not_found" {
+			return internal_error.NewBadRequestError("invalid emailValueSource object. mail account not found",
+				internal_error.Cause{Field: "mailAccountId", Message: "mail account not found"})
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *EmailValueSourceUseCase) isAvailable(ctx context.Context, mailAccountId string) bool {
+	emailService, err := u.emailServiceResolver.ResolveEmailService(ctx, mailAccountId)
+	if err != nil {
+		return false
+	}
+
+	return emailService.IsConnected()
+}
@@ -4,21 +4,31 @@ import (
 	"context"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/email_value_source_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 )
 
-func FindEmailValueSourceUseCase(emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface) EmailValueSourceUseCaseInterface {
+func FindEmailValueSourceUseCase(emailValueSourceRepository email_value_source_entity.EmailValueSourceRepositoryInterface,
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface,
+	emailServiceResolver email_service.EmailServiceResolverInterface) EmailValueSourceUseCaseInterface {
 	return &EmailValueSourceUseCase{
 		emailValueSourceRepository,
+		mailAccountRepository,
+		emailServiceResolver,
 	}
 }
 
 type EmailValueSourceOutputDTO struct {
 	Id            string    `json:"id"`
+	MailAccountId string    `json:"mailAccountId"`
 	Address       string    `json:"address"`
 	Subject       string    `json:"subject"`
+	Labels        []string  `json:"labels"`
+	Exclude       []string  `json:"exclude,omitempty"`
 	DataExtractor string    `json:"dataExtractor"`
+	Available     bool      `json:"available"`
 	CreatedAt     time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
 	UpdatedAt     time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
 }
@@ -32,9 +42,13 @@ func (u *EmailValueSourceUseCase) FindEmailValueSourceById(
 
 	return &EmailValueSourceOutputDTO{
 		Id:            emailValueSourceEntity.Id,
+		MailAccountId: emailValueSourceEntity.MailAccountId,
 		Address:       emailValueSourceEntity.Address,
 		Subject:       emailValueSourceEntity.Subject,
+		Labels:        emailValueSourceEntity.Labels,
+		Exclude:       emailValueSourceEntity.Exclude,
 		DataExtractor: email_value_source_entity.EmailValueSourceDataExtractor(emailValueSourceEntity.DataExtractor).Name(),
+		Available:     u.isAvailable(ctx, emailValueSourceEntity.MailAccountId),
 		CreatedAt:     emailValueSourceEntity.CreatedAt,
 		UpdatedAt:     emailValueSourceEntity.UpdatedAt,
 	}, nil
@@ -53,9 +67,13 @@ func (u *EmailValueSourceUseCase) FindEmailValueSources(
 	for i, value := range emailValueSourceEntities {
 		emailValueSourceOutputs[i] = &EmailValueSourceOutputDTO{
 			Id:            value.Id,
+			MailAccountId: value.MailAccountId,
 			Address:       value.Address,
 			Subject:       value.Subject,
+			Labels:        value.Labels,
+			Exclude:       value.Exclude,
 			DataExtractor: email_value_source_entity.EmailValueSourceDataExtractor(value.DataExtractor).Name(),
+			Available:     u.isAvailable(ctx, value.MailAccountId),
 			CreatedAt:     value.CreatedAt,
 			UpdatedAt:     value.UpdatedAt,
 		}
@@ -7,9 +7,12 @@ import (
 )
 
 type UpdateEmailValueSourceInputDTO struct {
-	Address       string `json:"address" binding:"min=5"`
-	Subject       string `json:"subject" binding:"min=3"`
-	DataExtractor string `json:"dataExtractor"`
+	MailAccountId string   `json:"mailAccountId"`
+	Address       string   `json:"address" binding:"min=5"`
+	Subject       string   `json:"subject" binding:"min=3"`
+	Labels        []string `json:"labels"`
+	Exclude       []string `json:"exclude"`
+	DataExtractor string   `json:"dataExtractor"`
 }
 
 func (u *EmailValueSourceUseCase) UpdateEmailValueSource(
@@ -22,8 +25,22 @@ func (u *EmailValueSourceUseCase) UpdateEmailValueSource(
 		return err
 	}
 
+	// an empty mailAccountId keeps the current one. Only the value sources created before mail
+	// accounts existed have none, and keep reading the legacy mailbox
+	if emailValueSourceInput.MailAccountId != "" || emailValueSourceEntity.MailAccountId != "" {
+		mailAccountId := emailValueSourceInput.MailAccountId
+		if mailAccountId == "" {
+			mailAccountId = emailValueSourceEntity.MailAccountId
+		}
+
+		if err := u.verifyMailAccount(ctx, mailAccountId); err != nil {
+			return err
+		}
+	}
+
 	if err := emailValueSourceEntity.
-		Update(emailValueSourceInput.Address, emailValueSourceInput.Subject, emailValueSourceInput.DataExtractor); err != nil {
+		Update(emailValueSourceInput.MailAccountId, emailValueSourceInput.Address, emailValueSourceInput.Subject, emailValueSourceInput.Labels,
+			emailValueSourceInput.Exclude, emailValueSourceInput.DataExtractor); err != nil {
 		return err
 	}
 
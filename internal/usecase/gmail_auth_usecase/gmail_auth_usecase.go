@@ -0,0 +1,187 @@
+package gmail_auth_usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	STATE_EXPIRATION = 10 * time.Minute
+)
+
+type StartGmailAuthOutputDTO struct {
+	AuthURL string `json:"authUrl"`
+}
+
+type GmailAuthStatusOutputDTO struct {
+	MailAccountId string `json:"mailAccountId,omitempty"`
+	Connected     bool   `json:"connected"`
+}
+
+type GmailAuthenticatorInterface interface {
+	AuthCodeURL(state string) string
+	Connect(ctx context.Context, accountId string, code string) (string, *internal_error.InternalError)
+	IsConnected(ctx context.Context, accountId string) bool
+}
+
+type GmailAuthUseCaseInterface interface {
+	StartGmailAuth(
+		ctx context.Context,
+		mailAccountId string) (StartGmailAuthOutputDTO, *internal_error.InternalError)
+	CompleteGmailAuth(
+		ctx context.Context,
+		state string,
+		code string) (GmailAuthStatusOutputDTO, *internal_error.InternalError)
+	GetGmailAuthStatus(
+		ctx context.Context,
+		mailAccountId string) (GmailAuthStatusOutputDTO, *internal_error.InternalError)
+}
+
+type GmailAuthUseCase struct {
+	gmailAuthenticator    GmailAuthenticatorInterface
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface
+	mutex                 sync.Mutex
+	pendingStates         map[string]pendingState
+}
+
+type pendingState struct {
+	mailAccountId string
+	// household of the request that started the flow, as the callback comes without the access token
+	scope      household_entity.Scope
+	expiration time.Time
+}
+
+func NewGmailAuthUseCase(
+	gmailAuthenticator GmailAuthenticatorInterface,
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface) GmailAuthUseCaseInterface {
+	return &GmailAuthUseCase{
+		gmailAuthenticator:    gmailAuthenticator,
+		mailAccountRepository: mailAccountRepository,
+		pendingStates:         make(map[string]pendingState),
+	}
+}
+
+// StartGmailAuth returns the consent page url for a mail account of the household.
+func (u *GmailAuthUseCase) StartGmailAuth(
+	ctx context.Context,
+	mailAccountId string) (StartGmailAuthOutputDTO, *internal_error.InternalError) {
+
+	mailAccount, err := u.findMailAccount(ctx, mailAccountId)
+	if err != nil {
+		return StartGmailAuthOutputDTO{}, err
+	}
+	if mailAccount.Provider != mail_account_entity.Gmail {
+		return StartGmailAuthOutputDTO{}, internal_error.NewBadRequestError("Mail account is not a Gmail account")
+	}
+
+	scope, _ := household_entity.ScopeFromContext(ctx)
+
+	state, e := newState()
+	if e != nil {
+		return StartGmailAuthOutputDTO{}, internal_error.NewInternalServerError("Error trying to generate oauth state")
+	}
+
+	u.mutex.Lock()
+	u.removeExpiredStates()
+	u.pendingStates[state] = pendingState{
+		mailAccountId: mailAccountId,
+		scope:         scope,
+		expiration:    time.Now().Add(STATE_EXPIRATION),
+	}
+	u.mutex.Unlock()
+
+	return StartGmailAuthOutputDTO{
+		AuthURL: u.gmailAuthenticator.AuthCodeURL(state)}, nil
+}
+
+func (u *GmailAuthUseCase) CompleteGmailAuth(
+	ctx context.Context,
+	state string,
+	code string) (GmailAuthStatusOutputDTO, *internal_error.InternalError) {
+
+	u.mutex.Lock()
+	pending, found := u.pendingStates[state]
+	delete(u.pendingStates, state)
+	u.mutex.Unlock()
+
+	if !found || time.Now().After(pending.expiration) {
+		return GmailAuthStatusOutputDTO{}, internal_error.NewBadRequestError("Invalid or expired oauth state")
+	}
+
+	if code == "" {
+		return GmailAuthStatusOutputDTO{}, internal_error.NewBadRequestError("Missing authorization code")
+	}
+
+	ctx = household_entity.WithScope(ctx, pending.scope)
+
+	address, err := u.gmailAuthenticator.Connect(ctx, pending.mailAccountId, code)
+	if err != nil {
+		return GmailAuthStatusOutputDTO{}, err
+	}
+
+	mailAccount, err := u.mailAccountRepository.FindMailAccountById(ctx, pending.mailAccountId)
+	if err != nil {
+		return GmailAuthStatusOutputDTO{}, err
+	}
+
+	if err := mailAccount.Connect(address); err != nil {
+		return GmailAuthStatusOutputDTO{}, err
+	}
+
+	if err := u.mailAccountRepository.UpdateMailAccount(ctx, mailAccount); err != nil {
+		return GmailAuthStatusOutputDTO{}, err
+	}
+
+	return u.GetGmailAuthStatus(ctx, pending.mailAccountId)
+}
+
+func (u *GmailAuthUseCase) GetGmailAuthStatus(
+	ctx context.Context,
+	mailAccountId string) (GmailAuthStatusOutputDTO, *internal_error.InternalError) {
+
+	if _, err := u.findMailAccount(ctx, mailAccountId); err != nil {
+		return GmailAuthStatusOutputDTO{}, err
+	}
+
+	return GmailAuthStatusOutputDTO{
+		MailAccountId: mailAccountId,
+		Connected:     u.gmailAuthenticator.IsConnected(ctx, mailAccountId)}, nil
+}
+
+// findMailAccount returns the mail account of the household. The legacy mailbox, used by the
+// value sources without a mail account, can no longer be connected through the API.
+func (u *GmailAuthUseCase) findMailAccount(
+	ctx context.Context,
+	mailAccountId string) (*mail_account_entity.MailAccount, *internal_error.InternalError) {
+
+	if mailAccountId == "" {
+		return nil, internal_error.NewBadRequestError("Invalid fields",
+			internal_error.Cause{Field: "mailAccountId", Message: "mailAccountId is required"})
+	}
+
+	return u.mailAccountRepository.FindMailAccountById(ctx, mailAccountId)
+}
+
+func (u *GmailAuthUseCase) removeExpiredStates() {
+	now := time.Now()
+	for state, pending := range u.pendingStates {
+		if now.After(pending.expiration) {
+			delete(u.pendingStates, state)
+		}
+	}
+}
+
+func newState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
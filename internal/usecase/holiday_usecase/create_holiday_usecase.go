@@ -0,0 +1,77 @@
+package holiday_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/calendar"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/holiday_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type HolidayInputDTO struct {
+	Name   string `json:"name" binding:"required,min=3"`
+	Date   string `json:"date" binding:"required"`
+	Yearly bool   `json:"yearly"`
+	State  string `json:"state"`
+	City   string `json:"city"`
+}
+
+type CreateHolidayOutputDTO struct {
+	Id string `json:"id"`
+}
+
+type HolidayUseCaseInterface interface {
+	CreateHoliday(
+		ctx context.Context,
+		holidayInput HolidayInputDTO) (*CreateHolidayOutputDTO, *internal_error.InternalError)
+	FindHolidays(
+		ctx context.Context,
+		year int,
+		state string,
+		city string) ([]*HolidayOutputDTO, *internal_error.InternalError)
+	DeleteHoliday(
+		ctx context.Context,
+		id string) *internal_error.InternalError
+}
+
+type HolidayUseCase struct {
+	holidayRepository holiday_entity.HolidayRepositoryInterface
+	calendar          calendar.CalendarInterface
+}
+
+func NewHolidayUseCase(
+	holidayRepository holiday_entity.HolidayRepositoryInterface,
+	calendar calendar.CalendarInterface) HolidayUseCaseInterface {
+	return &HolidayUseCase{
+		holidayRepository: holidayRepository,
+		calendar:          calendar,
+	}
+}
+
+func (u *HolidayUseCase) CreateHoliday(
+	ctx context.Context,
+	holidayInput HolidayInputDTO) (*CreateHolidayOutputDTO, *internal_error.InternalError) {
+
+	holiday, err := holiday_entity.CreateHoliday(holidayInput.Name, holidayInput.Date, holidayInput.Yearly,
+		holidayInput.State, holidayInput.City)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.holidayRepository.CreateHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+
+	return &CreateHolidayOutputDTO{Id: holiday.Id}, nil
+}
+
+func (u *HolidayUseCase) DeleteHoliday(
+	ctx context.Context,
+	id string) *internal_error.InternalError {
+
+	if _, err := u.holidayRepository.FindHolidayById(ctx, id); err != nil {
+		return err
+	}
+
+	return u.holidayRepository.DeleteHoliday(ctx, id)
+}
@@ -0,0 +1,55 @@
+package holiday_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type HolidayOutputDTO struct {
+	Id    string `json:"id,omitempty"`
+	Date  string `json:"date"`
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+	State string `json:"state,omitempty"`
+	City  string `json:"city,omitempty"`
+}
+
+// FindHolidays returns the holidays of the year observed in the city of the state: the national
+// ones and the custom ones registered for the country, the state and the city.
+func (u *HolidayUseCase) FindHolidays(
+	ctx context.Context,
+	year int,
+	state string,
+	city string) ([]*HolidayOutputDTO, *internal_error.InternalError) {
+
+	if year < 1900 || year > 2999 {
+		return nil, internal_error.NewBadRequestError("invalid year")
+	}
+
+	holidays, err := u.calendar.Holidays(ctx, year, state, city)
+	if err != nil {
+		return nil, err
+	}
+
+	holidayOutputs := make([]*HolidayOutputDTO, len(holidays))
+	for i, holiday := range holidays {
+		scope := "national"
+		if holiday.City != "" {
+			scope = "city"
+		} else if holiday.State != "" {
+			scope = "state"
+		}
+
+		holidayOutputs[i] = &HolidayOutputDTO{
+			Id:    holiday.Id,
+			Date:  holiday.Date.Format("2006-01-02"),
+			Name:  holiday.Name,
+			Scope: scope,
+			State: holiday.State,
+			City:  holiday.City,
+		}
+	}
+
+	return holidayOutputs, nil
+}
@@ -0,0 +1,106 @@
+package household_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type HouseholdInputDTO struct {
+	Name string `json:"name" binding:"required,min=2"`
+}
+
+type CreateHouseholdOutputDTO struct {
+	Id string `json:"id"`
+}
+
+type HouseholdUseCaseInterface interface {
+	CreateHousehold(
+		ctx context.Context,
+		userId string,
+		householdInput HouseholdInputDTO) (*CreateHouseholdOutputDTO, *internal_error.InternalError)
+	FindHouseholdById(
+		ctx context.Context,
+		userId string,
+		id string) (*HouseholdOutputDTO, *internal_error.InternalError)
+	FindHouseholds(
+		ctx context.Context,
+		userId string) ([]*HouseholdOutputDTO, *internal_error.InternalError)
+	InviteMember(
+		ctx context.Context,
+		userId string,
+		id string,
+		memberInput MemberInputDTO) (*CreateMemberOutputDTO, *internal_error.InternalError)
+	ChangeMemberRole(
+		ctx context.Context,
+		userId string,
+		id string,
+		memberId string,
+		roleInput MemberRoleInputDTO) *internal_error.InternalError
+	RemoveMember(
+		ctx context.Context,
+		userId string,
+		id string,
+		memberId string) *internal_error.InternalError
+	AcceptInvitation(
+		ctx context.Context,
+		userId string,
+		id string) *internal_error.InternalError
+	ResolveScope(
+		ctx context.Context,
+		userId string,
+		householdId string) (household_entity.Scope, *internal_error.InternalError)
+	// HouseholdScopes returns the scope of every household, for the jobs that run outside of requests
+	HouseholdScopes(ctx context.Context) ([]household_entity.Scope, *internal_error.InternalError)
+}
+
+type HouseholdUseCase struct {
+	householdRepository household_entity.HouseholdRepositoryInterface
+	userRepository      user_entity.UserRepositoryInterface
+}
+
+func NewHouseholdUseCase(
+	householdRepository household_entity.HouseholdRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) HouseholdUseCaseInterface {
+	return &HouseholdUseCase{
+		householdRepository: householdRepository,
+		userRepository:      userRepository,
+	}
+}
+
+func (u *HouseholdUseCase) CreateHousehold(
+	ctx context.Context,
+	userId string,
+	householdInput HouseholdInputDTO) (*CreateHouseholdOutputDTO, *internal_error.InternalError) {
+
+	user, err := u.findCaller(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := household_entity.CreateHousehold(householdInput.Name, user.Id, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.householdRepository.CreateHousehold(ctx, household); err != nil {
+		return nil, err
+	}
+
+	return &CreateHouseholdOutputDTO{Id: household.Id}, nil
+}
+
+// findCaller returns the user making the request.
+func (u *HouseholdUseCase) findCaller(ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	user, err := u.userRepository.FindUserById(ctx, userId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewForbiddenError("user not found")
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
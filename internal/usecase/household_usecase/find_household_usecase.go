@@ -0,0 +1,116 @@
+package household_usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type HouseholdOutputDTO struct {
+	Id        string             `json:"id"`
+	Name      string             `json:"name"`
+	Members   []*MemberOutputDTO `json:"members"`
+	CreatedAt time.Time          `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt time.Time          `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+type MemberOutputDTO struct {
+	Id        string     `json:"id"`
+	UserId    string     `json:"userId,omitempty"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	InvitedAt time.Time  `json:"invitedAt" time_format:"2006-01-02 15:04:05"`
+	JoinedAt  *time.Time `json:"joinedAt,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+// FindHouseholdById returns the household when the user is a member of it or was invited to it.
+func (u *HouseholdUseCase) FindHouseholdById(
+	ctx context.Context,
+	userId string,
+	id string) (*HouseholdOutputDTO, *internal_error.InternalError) {
+
+	household, err := u.findHousehold(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toHouseholdOutputDTO(household), nil
+}
+
+// FindHouseholds returns the households the user is a member of or was invited to.
+func (u *HouseholdUseCase) FindHouseholds(
+	ctx context.Context,
+	userId string) ([]*HouseholdOutputDTO, *internal_error.InternalError) {
+
+	user, err := u.findCaller(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	households, err := u.householdRepository.FindHouseholdsByMember(ctx, user.Id, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	householdOutputs := make([]*HouseholdOutputDTO, len(households))
+	for i, household := range households {
+		householdOutputs[i] = toHouseholdOutputDTO(household)
+	}
+
+	return householdOutputs, nil
+}
+
+// findHousehold returns the household, failing when the user did not join it nor was invited to it.
+func (u *HouseholdUseCase) findHousehold(
+	ctx context.Context,
+	userId string,
+	id string) (*household_entity.Household, *internal_error.InternalError) {
+
+	user, err := u.findCaller(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := u.householdRepository.FindHouseholdById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range household.Members {
+		if member.UserId == user.Id || strings.EqualFold(member.Email, user.Email) {
+			return household, nil
+		}
+	}
+
+	return nil, internal_error.NewForbiddenError("user is not a member of the household")
+}
+
+func toHouseholdOutputDTO(household *household_entity.Household) *HouseholdOutputDTO {
+	output := &HouseholdOutputDTO{
+		Id:        household.Id,
+		Name:      household.Name,
+		Members:   make([]*MemberOutputDTO, len(household.Members)),
+		CreatedAt: household.CreatedAt,
+		UpdatedAt: household.UpdatedAt,
+	}
+
+	for i, member := range household.Members {
+		output.Members[i] = &MemberOutputDTO{
+			Id:        member.Id,
+			UserId:    member.UserId,
+			Email:     member.Email,
+			Role:      member.Role.Name(),
+			Status:    member.Status.Name(),
+			InvitedAt: member.InvitedAt,
+		}
+		if !member.JoinedAt.IsZero() {
+			output.Members[i].JoinedAt = &member.JoinedAt
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,78 @@
+package household_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// ResolveScope returns the household the user acts on. When householdId is empty, the only
+// household the user joined is used.
+func (u *HouseholdUseCase) ResolveScope(
+	ctx context.Context,
+	userId string,
+	householdId string) (household_entity.Scope, *internal_error.InternalError) {
+
+	if householdId != "" {
+		household, err := u.householdRepository.FindHouseholdById(ctx, householdId)
+		if err != nil {
+			if err.Err == "not_found" {
+				return household_entity.Scope{}, internal_error.NewForbiddenError("user is not a member of the household")
+			}
+			return household_entity.Scope{}, err
+		}
+
+		return toScope(household, userId)
+	}
+
+	user, err := u.findCaller(ctx, userId)
+	if err != nil {
+		return household_entity.Scope{}, err
+	}
+
+	households, err := u.householdRepository.FindHouseholdsByMember(ctx, user.Id, user.Email)
+	if err != nil {
+		return household_entity.Scope{}, err
+	}
+
+	joined := make([]*household_entity.Household, 0)
+	for _, household := range households {
+		if household.RoleOf(userId) != 0 {
+			joined = append(joined, household)
+		}
+	}
+
+	switch len(joined) {
+	case 0:
+		return household_entity.Scope{}, internal_error.NewForbiddenError("user is not a member of any household")
+	case 1:
+		return toScope(joined[0], userId)
+	default:
+		return household_entity.Scope{}, internal_error.NewBadRequestError(
+			"the user is a member of more than one household. inform the X-Household-Id header")
+	}
+}
+
+func (u *HouseholdUseCase) HouseholdScopes(ctx context.Context) ([]household_entity.Scope, *internal_error.InternalError) {
+	households, err := u.householdRepository.FindHouseholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]household_entity.Scope, len(households))
+	for i, household := range households {
+		scopes[i] = household_entity.Scope{HouseholdId: household.Id, MemberIds: household.MemberIds()}
+	}
+
+	return scopes, nil
+}
+
+func toScope(household *household_entity.Household, userId string) (household_entity.Scope, *internal_error.InternalError) {
+	role := household.RoleOf(userId)
+	if role == 0 {
+		return household_entity.Scope{}, internal_error.NewForbiddenError("user is not a member of the household")
+	}
+
+	return household_entity.Scope{HouseholdId: household.Id, UserId: userId, Role: role, MemberIds: household.MemberIds()}, nil
+}
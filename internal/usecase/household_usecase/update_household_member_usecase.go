@@ -0,0 +1,136 @@
+package household_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type MemberInputDTO struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type MemberRoleInputDTO struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type CreateMemberOutputDTO struct {
+	Id string `json:"id"`
+}
+
+// InviteMember invites the email to the household. Only owners manage the members.
+func (u *HouseholdUseCase) InviteMember(
+	ctx context.Context,
+	userId string,
+	id string,
+	memberInput MemberInputDTO) (*CreateMemberOutputDTO, *internal_error.InternalError) {
+
+	household, err := u.findOwnedHousehold(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := household.Invite(memberInput.Email, memberInput.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.householdRepository.UpdateHousehold(ctx, household); err != nil {
+		return nil, err
+	}
+
+	return &CreateMemberOutputDTO{Id: member.Id}, nil
+}
+
+func (u *HouseholdUseCase) ChangeMemberRole(
+	ctx context.Context,
+	userId string,
+	id string,
+	memberId string,
+	roleInput MemberRoleInputDTO) *internal_error.InternalError {
+
+	household, err := u.findOwnedHousehold(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+
+	if err := household.ChangeRole(memberId, roleInput.Role); err != nil {
+		return err
+	}
+
+	return u.householdRepository.UpdateHousehold(ctx, household)
+}
+
+// RemoveMember removes a member or cancels an invitation. Besides the owners, members may remove
+// themselves to leave the household.
+func (u *HouseholdUseCase) RemoveMember(
+	ctx context.Context,
+	userId string,
+	id string,
+	memberId string) *internal_error.InternalError {
+
+	household, err := u.findHousehold(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+
+	leaving := false
+	for _, member := range household.Members {
+		if member.Id == memberId && member.UserId == userId {
+			leaving = true
+		}
+	}
+
+	if !leaving && household.RoleOf(userId) != household_entity.Owner {
+		return internal_error.NewForbiddenError("only the owners can manage the members of the household")
+	}
+
+	if err := household.RemoveMember(memberId); err != nil {
+		return err
+	}
+
+	return u.householdRepository.UpdateHousehold(ctx, household)
+}
+
+// AcceptInvitation makes the user a member of the household it was invited to by email.
+func (u *HouseholdUseCase) AcceptInvitation(
+	ctx context.Context,
+	userId string,
+	id string) *internal_error.InternalError {
+
+	user, err := u.findCaller(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	household, err := u.householdRepository.FindHouseholdById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := household.Accept(user.Id, user.Email); err != nil {
+		return err
+	}
+
+	return u.householdRepository.UpdateHousehold(ctx, household)
+}
+
+// findOwnedHousehold returns the household, failing when the user is not one of its owners.
+func (u *HouseholdUseCase) findOwnedHousehold(
+	ctx context.Context,
+	userId string,
+	id string) (*household_entity.Household, *internal_error.InternalError) {
+
+	household, err := u.findHousehold(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if household.RoleOf(userId) != household_entity.Owner {
+		return nil, internal_error.NewForbiddenError("only the owners can manage the members of the household")
+	}
+
+	return household, nil
+}
@@ -4,25 +4,30 @@ import (
 	"context"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type InvoiceInputDTO struct {
-	Name    string  `json:"name" binding:"required,min=3"`
-	DueDate string  `json:"dueDate" binding:"required"`
-	Amount  float64 `json:"amount" binding:"required"`
-	Status  string  `json:"status"`
+	BillId  string      `json:"billId" binding:"required,uuid"`
+	Period  string      `json:"period"`
+	DueDate string      `json:"dueDate" binding:"required"`
+	Amount  money.Money `json:"amount" binding:"required"`
+	Status  string      `json:"status"`
+	Barcode string      `json:"barcode"`
 }
 
 type CreateInvoiceOutputDTO struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name"`
-	DueDate   time.Time `json:"dueDate"`
-	Amount    float64   `json:"amount"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
-	UpdatedAt time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+	Id        string      `json:"id"`
+	BillId    string      `json:"billId"`
+	DueDate   time.Time   `json:"dueDate"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt time.Time   `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
 }
 
 type InvoiceUseCaseInterface interface {
@@ -31,21 +36,44 @@ type InvoiceUseCaseInterface interface {
 		invoiceInput InvoiceInputDTO) *internal_error.InternalError
 	FindInvoiceById(
 		ctx context.Context,
-		id string) (*InvoiceOutputDTO, *internal_error.InternalError)
+		id string,
+		asOf string) (*InvoiceOutputDTO, *internal_error.InternalError)
 	FindInvoices(
 		ctx context.Context,
-		billId string,
-		status invoice_entity.InvoiceStatus) ([]*InvoiceOutputDTO, *internal_error.InternalError)
+		findInvoicesInput FindInvoicesInputDTO) ([]*InvoiceOutputDTO, *internal_error.InternalError)
+	PayInvoice(
+		ctx context.Context,
+		id string,
+		payInvoiceInput PayInvoiceInputDTO) (*InvoiceOutputDTO, *internal_error.InternalError)
+	DeleteInvoicePayment(
+		ctx context.Context,
+		id string,
+		paymentId string) (*InvoiceOutputDTO, *internal_error.InternalError)
+	PayInvoices(
+		ctx context.Context,
+		payInvoicesInput PayInvoicesInputDTO) (*PayInvoicesOutputDTO, *internal_error.InternalError)
+	FindPaymentDiscrepancies(
+		ctx context.Context,
+		billId string) (*PaymentDiscrepanciesOutputDTO, *internal_error.InternalError)
+	FindUnconfirmedPayments(
+		ctx context.Context,
+		billId string) ([]*InvoiceOutputDTO, *internal_error.InternalError)
 }
 
 type InvoiceUseCase struct {
-	invoiceRepository invoice_entity.InvoiceRepositoryInterface
+	invoiceRepository  invoice_entity.InvoiceRepositoryInterface
+	billRepository     bill_entity.BillRepositoryInterface
+	categoryRepository category_entity.CategoryRepositoryInterface
 }
 
 func NewInvoiceUseCase(
-	invoiceRepository invoice_entity.InvoiceRepositoryInterface) InvoiceUseCaseInterface {
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	billRepository bill_entity.BillRepositoryInterface,
+	categoryRepository category_entity.CategoryRepositoryInterface) InvoiceUseCaseInterface {
 	return &InvoiceUseCase{
-		invoiceRepository: invoiceRepository,
+		invoiceRepository:  invoiceRepository,
+		billRepository:     billRepository,
+		categoryRepository: categoryRepository,
 	}
 }
 
@@ -53,11 +81,29 @@ func (u *InvoiceUseCase) CreateInvoice(
 	ctx context.Context,
 	invoiceInput InvoiceInputDTO) *internal_error.InternalError {
 
-	invoice, err := invoice_entity.CreateInvoice(invoiceInput.Name, invoiceInput.DueDate, invoiceInput.Amount, invoiceInput.Status)
+	bill, err := u.billRepository.FindBillById(ctx, invoiceInput.BillId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return internal_error.NewBadRequestError("invalid invoice object. bill not found",
+				internal_error.Cause{Field: "billId", Message: "bill not found"})
+		}
+		return err
+	}
+
+	invoice, err := invoice_entity.CreateInvoice(invoiceInput.BillId, invoiceInput.Period, invoiceInput.DueDate, invoiceInput.Amount, invoiceInput.Status)
 	if err != nil {
 		return err
 	}
 
+	if invoiceInput.Barcode != "" {
+		if invoice.Barcode = invoice_entity.NormalizeBarcode(invoiceInput.Barcode); invoice.Barcode == "" {
+			return internal_error.NewBadRequestError("invalid invoice object. invalid barcode",
+				internal_error.Cause{Field: "barcode", Message: "barcode must have 44, 47 or 48 digits"})
+		}
+	}
+
+	invoice.Shares = bill.InvoiceShares(invoice.Amount)
+
 	if err := u.invoiceRepository.CreateInvoice(ctx, invoice); err != nil {
 		return err
 	}
@@ -2,68 +2,291 @@ package invoice_usecase
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/category_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
-func FindInvoiceUseCase(invoiceRepository invoice_entity.InvoiceRepositoryInterface) InvoiceUseCaseInterface {
+func FindInvoiceUseCase(
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	billRepository bill_entity.BillRepositoryInterface,
+	categoryRepository category_entity.CategoryRepositoryInterface) InvoiceUseCaseInterface {
 	return &InvoiceUseCase{
 		invoiceRepository,
+		billRepository,
+		categoryRepository,
 	}
 }
 
+// FindInvoicesInputDTO filters the invoices. Period selects a single competence period (YYYY-MM);
+// From and To select an inclusive range of periods. CategoryId selects the invoices of the bills
+// of the category and of its subcategories, and Tag the ones of the bills with the tag.
+type FindInvoicesInputDTO struct {
+	BillId     string
+	UserId     string
+	CategoryId string
+	Tag        string
+	Status     invoice_entity.InvoiceStatus
+	Period     string
+	From       string
+	To         string
+}
+
 type InvoiceOutputDTO struct {
-	Id        string    `json:"id"`
-	BillId    string    `json:"billId"`
-	DueDate   string    `json:"dueDate"`
-	Amount    float64   `json:"amount"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
-	UpdatedAt time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+	Id                 string                          `json:"id"`
+	BillId             string                          `json:"billId"`
+	Period             string                          `json:"period"`
+	DueDate            string                          `json:"dueDate"`
+	Amount             money.Money                     `json:"amount"`
+	Status             string                          `json:"status"`
+	Overdue            bool                            `json:"overdue"`
+	EmailSource        *InvoiceEmailSourceOutputDTO    `json:"emailSource,omitempty"`
+	Barcode            string                          `json:"barcode,omitempty"`
+	Shares             []*InvoiceShareOutputDTO        `json:"shares,omitempty"`
+	Payments           []*InvoicePaymentOutputDTO      `json:"payments"`
+	PaidAmount         money.Money                     `json:"paidAmount"`
+	OutstandingBalance money.Money                     `json:"outstandingBalance"`
+	OverpaidAmount     money.Money                     `json:"overpaidAmount,omitempty"`
+	AmountChanges      []*InvoiceAmountChangeOutputDTO `json:"amountChanges,omitempty"`
+	AmountDue          *InvoiceAmountDueOutputDTO      `json:"amountDue,omitempty"`
+	CreatedAt          time.Time                       `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt          time.Time                       `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+type InvoiceEmailSourceOutputDTO struct {
+	MessageId   string    `json:"messageId"`
+	ThreadId    string    `json:"threadId"`
+	Sender      string    `json:"sender"`
+	ReceivedAt  time.Time `json:"receivedAt" time_format:"2006-01-02 15:04:05"`
+	ContentHash string    `json:"contentHash"`
+	DuplicateOf string    `json:"duplicateOf,omitempty"`
+}
+
+type InvoiceShareOutputDTO struct {
+	UserId string      `json:"userId"`
+	Amount money.Money `json:"amount"`
+}
+
+type InvoiceAmountChangeOutputDTO struct {
+	PreviousAmount money.Money `json:"previousAmount"`
+	Amount         money.Money `json:"amount"`
+	ChangedAt      time.Time   `json:"changedAt" time_format:"2006-01-02 15:04:05"`
 }
 
+// InvoiceAmountDueOutputDTO is the amount needed to settle the invoice on AsOf, with the
+// multa and juros de mora configured on the bill.
+type InvoiceAmountDueOutputDTO struct {
+	AsOf        string      `json:"asOf"`
+	DaysOverdue int         `json:"daysOverdue"`
+	Principal   money.Money `json:"principal"`
+	Fine        money.Money `json:"fine"`
+	Interest    money.Money `json:"interest"`
+	Total       money.Money `json:"total"`
+}
+
+type InvoicePaymentOutputDTO struct {
+	Id          string                       `json:"id"`
+	PaymentDate string                       `json:"paymentDate"`
+	Amount      money.Money                  `json:"amount"`
+	Method      string                       `json:"method"`
+	Notes       string                       `json:"notes,omitempty"`
+	Reference   string                       `json:"reference,omitempty"`
+	EmailSource *InvoiceEmailSourceOutputDTO `json:"emailSource,omitempty"`
+	Automatic   bool                         `json:"automatic,omitempty"`
+	PaidBy      string                       `json:"paidBy,omitempty"`
+	Confirmed   bool                         `json:"confirmed"`
+	CreatedAt   time.Time                    `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+}
+
+// FindInvoiceById returns the invoice with the amount due on asOf (YYYY-MM-DD, today when empty).
 func (u *InvoiceUseCase) FindInvoiceById(
-	ctx context.Context, id string) (*InvoiceOutputDTO, *internal_error.InternalError) {
+	ctx context.Context, id string, asOf string) (*InvoiceOutputDTO, *internal_error.InternalError) {
+
+	asOfDate := time.Now()
+	if asOf != "" {
+		date, e := time.Parse("2006-01-02", asOf)
+		if e != nil {
+			return nil, internal_error.NewBadRequestError("invalid asOf date. expected format YYYY-MM-DD")
+		}
+		asOfDate = date
+	}
+
 	invoiceEntity, err := u.invoiceRepository.FindInvoiceById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &InvoiceOutputDTO{
-		Id:        invoiceEntity.Id,
-		BillId:    invoiceEntity.BillId,
-		DueDate:   invoiceEntity.DueDate,
-		Amount:    invoiceEntity.Amount,
-		Status:    invoice_entity.InvoiceStatus(invoiceEntity.Status).Name(),
-		CreatedAt: invoiceEntity.CreatedAt,
-		UpdatedAt: invoiceEntity.UpdatedAt,
-	}, nil
+	var latePaymentRule bill_entity.LatePaymentRule
+
+	billEntity, err := u.billRepository.FindBillById(ctx, invoiceEntity.BillId)
+	if err != nil && err.Err != "not_found" {
+		return nil, err
+	}
+	if billEntity != nil {
+		latePaymentRule = billEntity.LatePaymentRule
+	}
+
+	amountDue := invoiceEntity.CalculateAmountDue(asOfDate,
+		latePaymentRule.FinePercentage, latePaymentRule.MonthlyInterestPercentage)
+
+	output := toInvoiceOutputDTO(invoiceEntity)
+	output.Overdue = invoiceEntity.IsOverdue(asOfDate)
+	output.AmountDue = &InvoiceAmountDueOutputDTO{
+		AsOf:        amountDue.AsOf,
+		DaysOverdue: amountDue.DaysOverdue,
+		Principal:   amountDue.Principal,
+		Fine:        amountDue.Fine,
+		Interest:    amountDue.Interest,
+		Total:       amountDue.Total,
+	}
+
+	return output, nil
 }
 
 func (u *InvoiceUseCase) FindInvoices(
 	ctx context.Context,
-	billId string,
-	status invoice_entity.InvoiceStatus) ([]*InvoiceOutputDTO, *internal_error.InternalError) {
-	invoiceEntities, err := u.invoiceRepository.FindInvoices(
-		ctx, billId, status)
+	findInvoicesInput FindInvoicesInputDTO) ([]*InvoiceOutputDTO, *internal_error.InternalError) {
+
+	filter := invoice_entity.InvoiceFilter{
+		BillIds:    toBillIds(findInvoicesInput.BillId),
+		Status:     findInvoicesInput.Status,
+		FromPeriod: findInvoicesInput.From,
+		ToPeriod:   findInvoicesInput.To,
+	}
+
+	if findInvoicesInput.Period != "" {
+		if findInvoicesInput.From != "" || findInvoicesInput.To != "" {
+			return nil, internal_error.NewBadRequestError("period cannot be combined with from and to")
+		}
+		filter.FromPeriod = findInvoicesInput.Period
+		filter.ToPeriod = findInvoicesInput.Period
+	}
+
+	for _, period := range []string{filter.FromPeriod, filter.ToPeriod} {
+		if _, e := time.Parse("2006-01", period); period != "" && e != nil {
+			return nil, internal_error.NewBadRequestError("invalid period. expected format YYYY-MM")
+		}
+	}
+
+	if findInvoicesInput.UserId != "" || findInvoicesInput.CategoryId != "" || findInvoicesInput.Tag != "" {
+		billFilter := bill_entity.BillFilter{
+			UserId: findInvoicesInput.UserId,
+			Tag:    findInvoicesInput.Tag,
+		}
+
+		if findInvoicesInput.CategoryId != "" {
+			categories, err := u.categoryRepository.FindCategories(ctx)
+			if err != nil {
+				return nil, err
+			}
+			billFilter.CategoryIds = category_entity.Subtree(categories, findInvoicesInput.CategoryId)
+		}
+
+		bills, err := u.billRepository.FindBills(ctx, billFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		matchingBillIds := make([]string, 0)
+		for _, bill := range bills {
+			if filter.BillIds == nil || slices.Contains(filter.BillIds, bill.Id) {
+				matchingBillIds = append(matchingBillIds, bill.Id)
+			}
+		}
+		filter.BillIds = matchingBillIds
+	}
+
+	invoiceEntities, err := u.invoiceRepository.FindInvoices(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	invoiceOutputs := make([]*InvoiceOutputDTO, len(invoiceEntities))
 	for i, value := range invoiceEntities {
-		invoiceOutputs[i] = &InvoiceOutputDTO{
-			Id:        value.Id,
-			BillId:    value.BillId,
-			DueDate:   value.DueDate,
-			Amount:    value.Amount,
-			Status:    invoice_entity.InvoiceStatus(value.Status).Name(),
-			CreatedAt: value.CreatedAt,
-			UpdatedAt: value.UpdatedAt,
-		}
+		invoiceOutputs[i] = toInvoiceOutputDTO(value)
 	}
 
 	return invoiceOutputs, nil
 }
+
+// toBillIds returns the filter for a single bill, or nil (any bill) when billId is empty.
+func toBillIds(billId string) []string {
+	if billId == "" {
+		return nil
+	}
+	return []string{billId}
+}
+
+func toInvoiceOutputDTO(invoiceEntity *invoice_entity.Invoice) *InvoiceOutputDTO {
+	output := &InvoiceOutputDTO{
+		Id:          invoiceEntity.Id,
+		BillId:      invoiceEntity.BillId,
+		Period:      invoiceEntity.Period,
+		DueDate:     invoiceEntity.DueDate,
+		Amount:      invoiceEntity.Amount,
+		Status:      invoiceEntity.Status.Name(),
+		Overdue:     invoiceEntity.IsOverdue(time.Now()),
+		EmailSource: toInvoiceEmailSourceOutputDTO(invoiceEntity.EmailSource),
+		Barcode:     invoiceEntity.Barcode,
+		CreatedAt:   invoiceEntity.CreatedAt,
+		UpdatedAt:   invoiceEntity.UpdatedAt,
+	}
+
+	for _, share := range invoiceEntity.Shares {
+		output.Shares = append(output.Shares, &InvoiceShareOutputDTO{UserId: share.UserId, Amount: share.Amount})
+	}
+
+	output.Payments = make([]*InvoicePaymentOutputDTO, len(invoiceEntity.Payments))
+	for i, payment := range invoiceEntity.Payments {
+		output.Payments[i] = &InvoicePaymentOutputDTO{
+			Id:          payment.Id,
+			PaymentDate: payment.PaymentDate,
+			Amount:      payment.Amount,
+			Method:      payment.Method.Name(),
+			Notes:       payment.Notes,
+			Reference:   payment.Reference,
+			EmailSource: toInvoiceEmailSourceOutputDTO(payment.EmailSource),
+			Automatic:   payment.Automatic,
+			PaidBy:      payment.PaidBy,
+			Confirmed:   payment.Confirmed(),
+			CreatedAt:   payment.CreatedAt,
+		}
+	}
+
+	for _, change := range invoiceEntity.AmountChanges {
+		output.AmountChanges = append(output.AmountChanges, &InvoiceAmountChangeOutputDTO{
+			PreviousAmount: change.PreviousAmount,
+			Amount:         change.Amount,
+			ChangedAt:      change.ChangedAt,
+		})
+	}
+
+	output.PaidAmount = invoiceEntity.PaidAmount()
+	if balance := invoiceEntity.OutstandingBalance(); balance > 0 {
+		output.OutstandingBalance = balance
+	} else {
+		output.OverpaidAmount = -balance
+	}
+
+	return output
+}
+
+func toInvoiceEmailSourceOutputDTO(emailSource *invoice_entity.EmailSource) *InvoiceEmailSourceOutputDTO {
+	if emailSource == nil {
+		return nil
+	}
+
+	return &InvoiceEmailSourceOutputDTO{
+		MessageId:   emailSource.MessageId,
+		ThreadId:    emailSource.ThreadId,
+		Sender:      emailSource.Sender,
+		ReceivedAt:  emailSource.ReceivedAt,
+		ContentHash: emailSource.ContentHash,
+		DuplicateOf: emailSource.DuplicateOf,
+	}
+}
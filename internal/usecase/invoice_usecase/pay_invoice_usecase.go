@@ -0,0 +1,252 @@
+package invoice_usecase
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+type PayInvoiceInputDTO struct {
+	PaymentDate string      `json:"paymentDate"`
+	Amount      money.Money `json:"amount"`
+	Method      string      `json:"method" binding:"required"`
+	Notes       string      `json:"notes"`
+	Reference   string      `json:"reference"`
+	PaidBy      string      `json:"paidBy"`
+}
+
+// PayInvoicesInputDTO pays the outstanding balance of every open invoice of a period (the
+// reference month of the bill processing; invoices are due on the following month).
+type PayInvoicesInputDTO struct {
+	Period      string `json:"period" binding:"required"`
+	BillId      string `json:"billId"`
+	PaymentDate string `json:"paymentDate"`
+	Method      string `json:"method" binding:"required"`
+	Notes       string `json:"notes"`
+	PaidBy      string `json:"paidBy"`
+}
+
+type PayInvoicesOutputDTO struct {
+	InvoiceIds []string `json:"invoiceIds"`
+}
+
+type PaymentDiscrepanciesOutputDTO struct {
+	Underpaid []*InvoiceOutputDTO `json:"underpaid"`
+	Overpaid  []*InvoiceOutputDTO `json:"overpaid"`
+}
+
+func (u *InvoiceUseCase) PayInvoice(
+	ctx context.Context,
+	id string,
+	payInvoiceInput PayInvoiceInputDTO) (*InvoiceOutputDTO, *internal_error.InternalError) {
+
+	invoiceEntity, err := u.invoiceRepository.FindInvoiceById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payInvoiceInput.Reference != "" && invoiceEntity.HasPaymentReference(payInvoiceInput.Reference) {
+		return nil, internal_error.NewBadRequestError("payment already registered with this reference")
+	}
+
+	if err := u.verifyPayer(ctx, invoiceEntity, payInvoiceInput.PaidBy); err != nil {
+		return nil, err
+	}
+
+	var payment *invoice_entity.Payment
+	if invoiceEntity.UnconfirmedPayment() != nil && payInvoiceInput.Reference != "" {
+		// the charge of an invoice paid automatically, found in a statement
+		if err := verifyAutomaticPaymentMethod(invoiceEntity.UnconfirmedPayment(), payInvoiceInput.Method); err != nil {
+			return nil, err
+		}
+		payment, err = invoiceEntity.ConfirmAutomaticPayment(payInvoiceInput.PaymentDate, payInvoiceInput.Amount,
+			payInvoiceInput.Reference)
+	} else {
+		payment, err = invoiceEntity.AddPayment(payInvoiceInput.PaymentDate, payInvoiceInput.Amount,
+			payInvoiceInput.Method, payInvoiceInput.Notes, payInvoiceInput.Reference)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if payInvoiceInput.PaidBy != "" {
+		payment.PaidBy = payInvoiceInput.PaidBy
+	}
+
+	if err := u.invoiceRepository.UpdateInvoice(ctx, invoiceEntity); err != nil {
+		return nil, err
+	}
+
+	return toInvoiceOutputDTO(invoiceEntity), nil
+}
+
+// verifyAutomaticPaymentMethod fails when the charge was not made with the method of the
+// automatic payment it would confirm.
+func verifyAutomaticPaymentMethod(payment *invoice_entity.Payment, method string) *internal_error.InternalError {
+	paymentMethod, err := invoice_entity.GetPaymentMethodByName(method)
+	if err != nil {
+		return err
+	}
+
+	if paymentMethod != payment.Method {
+		return internal_error.NewBadRequestError("the payment method differs from the automatic payment",
+			internal_error.Cause{Field: "method", Message: "method must be " + payment.Method.Name()})
+	}
+
+	return nil
+}
+
+// verifyPayer fails when the user who paid is not the owner of the bill of the invoice nor one of
+// the users it is shared with.
+func (u *InvoiceUseCase) verifyPayer(
+	ctx context.Context,
+	invoiceEntity *invoice_entity.Invoice,
+	paidBy string) *internal_error.InternalError {
+
+	if paidBy == "" {
+		return nil
+	}
+
+	bill, err := u.billRepository.FindBillById(ctx, invoiceEntity.BillId)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(bill.Users(), paidBy) {
+		return internal_error.NewBadRequestError("invalid payment. the payer does not share the bill",
+			internal_error.Cause{Field: "paidBy", Message: "user is not the owner of the bill nor shares it"})
+	}
+
+	return nil
+}
+
+func (u *InvoiceUseCase) DeleteInvoicePayment(
+	ctx context.Context,
+	id string,
+	paymentId string) (*InvoiceOutputDTO, *internal_error.InternalError) {
+
+	invoiceEntity, err := u.invoiceRepository.FindInvoiceById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invoiceEntity.RemovePayment(paymentId); err != nil {
+		return nil, err
+	}
+
+	if err := u.invoiceRepository.UpdateInvoice(ctx, invoiceEntity); err != nil {
+		return nil, err
+	}
+
+	return toInvoiceOutputDTO(invoiceEntity), nil
+}
+
+func (u *InvoiceUseCase) PayInvoices(
+	ctx context.Context,
+	payInvoicesInput PayInvoicesInputDTO) (*PayInvoicesOutputDTO, *internal_error.InternalError) {
+
+	if _, e := time.Parse("2006-01", payInvoicesInput.Period); e != nil {
+		return nil, internal_error.NewBadRequestError("invalid period. expected format YYYY-MM")
+	}
+
+	invoiceEntities, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{
+		BillIds:    toBillIds(payInvoicesInput.BillId),
+		FromPeriod: payInvoicesInput.Period,
+		ToPeriod:   payInvoicesInput.Period,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &PayInvoicesOutputDTO{InvoiceIds: make([]string, 0)}
+
+	for _, invoiceEntity := range invoiceEntities {
+		if invoiceEntity.Status == invoice_entity.Paid {
+			continue
+		}
+
+		if err := u.verifyPayer(ctx, invoiceEntity, payInvoicesInput.PaidBy); err != nil {
+			return output, err
+		}
+
+		payment, err := invoiceEntity.AddPayment(payInvoicesInput.PaymentDate, 0,
+			payInvoicesInput.Method, payInvoicesInput.Notes, "")
+		if err != nil {
+			return output, err
+		}
+		payment.PaidBy = payInvoicesInput.PaidBy
+
+		if err := u.invoiceRepository.UpdateInvoice(ctx, invoiceEntity); err != nil {
+			return output, err
+		}
+
+		output.InvoiceIds = append(output.InvoiceIds, invoiceEntity.Id)
+	}
+
+	log.Printf("Invoices paid for period %s: %d", payInvoicesInput.Period, len(output.InvoiceIds))
+
+	return output, nil
+}
+
+// FindPaymentDiscrepancies lists the invoices paid with a different amount than billed: overdue
+// invoices paid only in part, and invoices paid above their amount (e.g. with late interest).
+func (u *InvoiceUseCase) FindPaymentDiscrepancies(
+	ctx context.Context,
+	billId string) (*PaymentDiscrepanciesOutputDTO, *internal_error.InternalError) {
+
+	invoiceEntities, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{
+		BillIds: toBillIds(billId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	output := &PaymentDiscrepanciesOutputDTO{
+		Underpaid: make([]*InvoiceOutputDTO, 0),
+		Overpaid:  make([]*InvoiceOutputDTO, 0),
+	}
+
+	for _, invoiceEntity := range invoiceEntities {
+		balance := invoiceEntity.OutstandingBalance()
+
+		switch {
+		case invoiceEntity.Status == invoice_entity.PartiallyPaid && invoiceEntity.DueDate < today:
+			output.Underpaid = append(output.Underpaid, toInvoiceOutputDTO(invoiceEntity))
+		case balance < 0:
+			output.Overpaid = append(output.Overpaid, toInvoiceOutputDTO(invoiceEntity))
+		}
+	}
+
+	return output, nil
+}
+
+// FindUnconfirmedPayments lists the invoices of bills charged automatically that were taken as
+// paid on the due date and still wait for a statement to confirm the debit or card charge.
+func (u *InvoiceUseCase) FindUnconfirmedPayments(
+	ctx context.Context,
+	billId string) ([]*InvoiceOutputDTO, *internal_error.InternalError) {
+
+	invoiceEntities, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{
+		BillIds: toBillIds(billId),
+		Status:  invoice_entity.Paid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*InvoiceOutputDTO, 0)
+	for _, invoiceEntity := range invoiceEntities {
+		if invoiceEntity.UnconfirmedPayment() != nil {
+			output = append(output, toInvoiceOutputDTO(invoiceEntity))
+		}
+	}
+
+	return output, nil
+}
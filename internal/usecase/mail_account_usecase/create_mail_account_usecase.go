@@ -0,0 +1,80 @@
+package mail_account_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type MailAccountInputDTO struct {
+	UserId   string `json:"userId" binding:"required"`
+	Provider string `json:"provider" binding:"required"`
+	Address  string `json:"address" binding:"required,min=5"`
+}
+
+type CreateMailAccountOutputDTO struct {
+	Id      string `json:"id"`
+	AuthURL string `json:"authUrl"`
+}
+
+type MailAccountUseCaseInterface interface {
+	CreateMailAccount(
+		ctx context.Context,
+		mailAccountInput MailAccountInputDTO) (*CreateMailAccountOutputDTO, *internal_error.InternalError)
+	FindMailAccountById(
+		ctx context.Context,
+		id string) (*MailAccountOutputDTO, *internal_error.InternalError)
+	FindMailAccounts(
+		ctx context.Context,
+		status mail_account_entity.MailAccountStatus,
+		userId string) ([]*MailAccountOutputDTO, *internal_error.InternalError)
+}
+
+type MailAccountUseCase struct {
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface
+	userRepository        user_entity.UserRepositoryInterface
+}
+
+func NewMailAccountUseCase(
+	mailAccountRepository mail_account_entity.MailAccountRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) MailAccountUseCaseInterface {
+	return &MailAccountUseCase{
+		mailAccountRepository: mailAccountRepository,
+		userRepository:        userRepository,
+	}
+}
+
+func (u *MailAccountUseCase) CreateMailAccount(
+	ctx context.Context,
+	mailAccountInput MailAccountInputDTO) (*CreateMailAccountOutputDTO, *internal_error.InternalError) {
+
+	if _, err := u.userRepository.FindUserById(ctx, mailAccountInput.UserId); err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("invalid mailAccount object. user not found",
+				internal_error.Cause{Field: "userId", Message: "user not found"})
+		}
+		return nil, err
+	}
+
+	if scope, found := household_entity.ScopeFromContext(ctx); !found || !scope.HasMember(mailAccountInput.UserId) {
+		return nil, internal_error.NewBadRequestError("invalid mailAccount object. user is not a member of the household",
+			internal_error.Cause{Field: "userId", Message: "user is not a member of the household"})
+	}
+
+	mailAccount, err := mail_account_entity.CreateMailAccount(mailAccountInput.UserId, mailAccountInput.Provider, mailAccountInput.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.mailAccountRepository.CreateMailAccount(ctx, mailAccount); err != nil {
+		return nil, err
+	}
+
+	return &CreateMailAccountOutputDTO{
+		Id:      mailAccount.Id,
+		AuthURL: "/auth/" + mailAccount.Provider.Name() + "/start?mailAccountId=" + mailAccount.Id,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package mail_account_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/mail_account_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type MailAccountOutputDTO struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"userId"`
+	Provider  string    `json:"provider"`
+	Address   string    `json:"address"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u *MailAccountUseCase) FindMailAccountById(
+	ctx context.Context, id string) (*MailAccountOutputDTO, *internal_error.InternalError) {
+	mailAccountEntity, err := u.mailAccountRepository.FindMailAccountById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MailAccountOutputDTO{
+		Id:        mailAccountEntity.Id,
+		UserId:    mailAccountEntity.UserId,
+		Provider:  mailAccountEntity.Provider.Name(),
+		Address:   mailAccountEntity.Address,
+		Status:    mailAccountEntity.Status.Name(),
+		CreatedAt: mailAccountEntity.CreatedAt,
+		UpdatedAt: mailAccountEntity.UpdatedAt,
+	}, nil
+}
+
+func (u *MailAccountUseCase) FindMailAccounts(
+	ctx context.Context,
+	status mail_account_entity.MailAccountStatus,
+	userId string) ([]*MailAccountOutputDTO, *internal_error.InternalError) {
+	mailAccountEntities, err := u.mailAccountRepository.FindMailAccounts(ctx, status, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	mailAccountOutputs := make([]*MailAccountOutputDTO, len(mailAccountEntities))
+	for i, value := range mailAccountEntities {
+		mailAccountOutputs[i] = &MailAccountOutputDTO{
+			Id:        value.Id,
+			UserId:    value.UserId,
+			Provider:  value.Provider.Name(),
+			Address:   value.Address,
+			Status:    value.Status.Name(),
+			CreatedAt: value.CreatedAt,
+			UpdatedAt: value.UpdatedAt,
+		}
+	}
+
+	return mailAccountOutputs, nil
+}
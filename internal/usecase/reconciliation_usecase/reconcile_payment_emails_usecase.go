@@ -0,0 +1,135 @@
+package reconciliation_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/data_extractor/email_data_extractor"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+const (
+	// period searched when the request has no start date
+	PAYMENT_EMAILS_DEFAULT_DAYS = 30
+)
+
+type ReconcilePaymentEmailsInputDTO struct {
+	MailAccountId string `json:"mailAccountId" binding:"required"`
+	// sender of the confirmations, e.g. the bank address. Any sender when empty.
+	Address   string `json:"address"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// ReconcilePaymentEmails reads the payment confirmations ("Pix enviado", "Pagamento de boleto
+// realizado") received in the period and registers each one on the open invoice it matches, the
+// same way the statement debits are. The payment keeps the message it came from.
+func (u *ReconciliationUseCase) ReconcilePaymentEmails(
+	ctx context.Context,
+	input ReconcilePaymentEmailsInputDTO) (*ReconciliationOutputDTO, *internal_error.InternalError) {
+
+	endDate := time.Now()
+	if input.EndDate != "" {
+		date, e := time.ParseInLocation("2006-01-02", input.EndDate, time.Local)
+		if e != nil {
+			return nil, internal_error.NewBadRequestError("invalid endDate. expected format YYYY-MM-DD")
+		}
+		endDate = date
+	}
+
+	startDate := endDate.AddDate(0, 0, -PAYMENT_EMAILS_DEFAULT_DAYS)
+	if input.StartDate != "" {
+		date, e := time.ParseInLocation("2006-01-02", input.StartDate, time.Local)
+		if e != nil {
+			return nil, internal_error.NewBadRequestError("invalid startDate. expected format YYYY-MM-DD")
+		}
+		startDate = date
+	}
+
+	if startDate.After(endDate) {
+		return nil, internal_error.NewBadRequestError("startDate must not be after endDate")
+	}
+
+	emailService, err := u.emailServiceResolver.ResolveEmailService(ctx, input.MailAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, billsById, err := u.findOpenInvoices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmations := make([]*email_data_extractor.PaymentConfirmation, 0)
+	alreadyReconciled := 0
+
+	for _, extractor := range email_data_extractor.NewPaymentConfirmationExtractors(emailService) {
+		extracted, err := extractor.ExtractPayments(email_data_extractor.EmailDataExtractorRequest{
+			Address: input.Address,
+			// the Gmail "before" operator is exclusive
+			StartDate: startDate,
+			EndDate:   endDate.AddDate(0, 0, 1),
+			SkipMessage: func(messageId string) bool {
+				if isAlreadyReconciled(invoices, messageId) {
+					alreadyReconciled++
+					return true
+				}
+				return false
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		confirmations = append(confirmations, extracted...)
+	}
+
+	output := newReconciliationOutputDTO(len(confirmations) + alreadyReconciled)
+	output.AlreadyReconciled = alreadyReconciled
+
+	for _, confirmation := range confirmations {
+		message := confirmation.Message
+
+		// a forwarded copy of a confirmation already registered
+		if isAlreadyReconciledFromEmail(invoices, message.Id, message.ContentHash) {
+			output.AlreadyReconciled++
+			continue
+		}
+
+		err := u.reconcile(ctx, invoices, billsById, output, &payment{
+			transaction: TransactionOutputDTO{
+				Id:          message.Id,
+				Date:        confirmation.Date.Format("2006-01-02"),
+				Amount:      -confirmation.Amount,
+				Description: confirmation.Beneficiary,
+				Barcode:     confirmation.Barcode,
+			},
+			date:   confirmation.Date,
+			amount: confirmation.Amount,
+			method: confirmation.Method,
+			notes:  "Conciliado pelo comprovante enviado por e-mail: " + confirmation.Beneficiary,
+			emailSource: &invoice_entity.EmailSource{
+				MessageId:   message.Id,
+				ThreadId:    message.ThreadId,
+				Sender:      message.Sender,
+				ReceivedAt:  message.ReceivedAt,
+				ContentHash: message.ContentHash,
+			},
+		})
+		if err != nil {
+			return output, err
+		}
+	}
+
+	return output, nil
+}
+
+func isAlreadyReconciledFromEmail(invoices []*invoice_entity.Invoice, messageId string, contentHash string) bool {
+	for _, invoice := range invoices {
+		if invoice.HasPaymentFromEmail(messageId, contentHash) {
+			return true
+		}
+	}
+	return false
+}
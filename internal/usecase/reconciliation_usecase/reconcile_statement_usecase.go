@@ -0,0 +1,388 @@
+package reconciliation_usecase
+
+import (
+	"context"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/email_service"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+	"github.com/regismartiny/lembrador-contas-go/internal/statement_parser"
+)
+
+const (
+	// a debit matches invoices due up to DAYS_AFTER_DEBIT after it or DAYS_BEFORE_DEBIT before it
+	DAYS_AFTER_DEBIT  = 15
+	DAYS_BEFORE_DEBIT = 10
+
+	// share of the bill name/company words found in the transaction description
+	MIN_NAME_SIMILARITY = 0.5
+	// how much the best candidate must score above the second one to be chosen automatically
+	MIN_SCORE_MARGIN = 0.25
+	// score of the invoice with the paid barcode, above any name similarity
+	BARCODE_MATCH_SCORE = 2
+)
+
+var ignoredNameWords = []string{"de", "da", "do", "das", "dos", "e", "cia", "ltda", "sa", "s", "a", "me", "eireli"}
+
+// TransactionOutputDTO is a statement debit or a payment confirmation email. For emails, the id is
+// the message id, the description is the beneficiary and the barcode is the one of the paid boleto.
+type TransactionOutputDTO struct {
+	Id          string      `json:"id"`
+	Date        string      `json:"date"`
+	Amount      money.Money `json:"amount"`
+	Description string      `json:"description"`
+	Barcode     string      `json:"barcode,omitempty"`
+}
+
+type ReconciliationCandidateOutputDTO struct {
+	InvoiceId          string      `json:"invoiceId"`
+	BillId             string      `json:"billId"`
+	BillName           string      `json:"billName"`
+	DueDate            string      `json:"dueDate"`
+	OutstandingBalance money.Money `json:"outstandingBalance"`
+	Score              float64     `json:"score"`
+}
+
+type ReconciledTransactionOutputDTO struct {
+	Transaction TransactionOutputDTO              `json:"transaction"`
+	Invoice     *ReconciliationCandidateOutputDTO `json:"invoice"`
+}
+
+// AmbiguousTransactionOutputDTO is a payment that could settle more than one invoice, or that
+// matches an invoice without enough confidence. It must be confirmed with POST /invoice/:id/pay,
+// passing the transaction id as reference.
+type AmbiguousTransactionOutputDTO struct {
+	Transaction TransactionOutputDTO                `json:"transaction"`
+	Candidates  []*ReconciliationCandidateOutputDTO `json:"candidates"`
+}
+
+type ReconciliationOutputDTO struct {
+	Transactions      int                               `json:"transactions"`
+	AlreadyReconciled int                               `json:"alreadyReconciled"`
+	Reconciled        []*ReconciledTransactionOutputDTO `json:"reconciled"`
+	Ambiguous         []*AmbiguousTransactionOutputDTO  `json:"ambiguous"`
+	Unmatched         []*TransactionOutputDTO           `json:"unmatched"`
+}
+
+type ReconciliationUseCaseInterface interface {
+	ReconcileStatement(
+		ctx context.Context,
+		format statement_parser.StatementFormat,
+		statement io.Reader) (*ReconciliationOutputDTO, *internal_error.InternalError)
+	ReconcilePaymentEmails(
+		ctx context.Context,
+		input ReconcilePaymentEmailsInputDTO) (*ReconciliationOutputDTO, *internal_error.InternalError)
+}
+
+type ReconciliationUseCase struct {
+	invoiceRepository    invoice_entity.InvoiceRepositoryInterface
+	billRepository       bill_entity.BillRepositoryInterface
+	emailServiceResolver email_service.EmailServiceResolverInterface
+}
+
+func NewReconciliationUseCase(
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	billRepository bill_entity.BillRepositoryInterface,
+	emailServiceResolver email_service.EmailServiceResolverInterface) ReconciliationUseCaseInterface {
+	return &ReconciliationUseCase{
+		invoiceRepository:    invoiceRepository,
+		billRepository:       billRepository,
+		emailServiceResolver: emailServiceResolver,
+	}
+}
+
+// ReconcileStatement matches the debits of a bank statement to the open invoices by amount,
+// date and payee name, registering a payment on the confident matches. Matches of invoices paid
+// automatically confirm the automatic payment instead.
+func (u *ReconciliationUseCase) ReconcileStatement(
+	ctx context.Context,
+	format statement_parser.StatementFormat,
+	statement io.Reader) (*ReconciliationOutputDTO, *internal_error.InternalError) {
+
+	parser := statement_parser.NewStatementParser(format)
+	if parser == nil {
+		return nil, internal_error.NewBadRequestError("Unsupported statement format")
+	}
+
+	content, err := statement_parser.ReadStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := parser.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, billsById, err := u.findOpenInvoices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := newReconciliationOutputDTO(len(transactions))
+
+	for _, transaction := range transactions {
+		if transaction.Amount >= 0 {
+			continue // only debits pay invoices
+		}
+
+		if isAlreadyReconciled(invoices, transaction.Id) {
+			output.AlreadyReconciled++
+			continue
+		}
+
+		err := u.reconcile(ctx, invoices, billsById, output, &payment{
+			transaction: TransactionOutputDTO{
+				Id:          transaction.Id,
+				Date:        transaction.Date.Format("2006-01-02"),
+				Amount:      transaction.Amount,
+				Description: transaction.Description,
+			},
+			date:   transaction.Date,
+			amount: -transaction.Amount,
+			method: guessPaymentMethod(transaction),
+			notes:  "Conciliado pelo extrato: " + transaction.Description,
+		})
+		if err != nil {
+			return output, err
+		}
+	}
+
+	return output, nil
+}
+
+// findOpenInvoices returns the invoices that can still receive payments and the bills by id.
+func (u *ReconciliationUseCase) findOpenInvoices(
+	ctx context.Context) ([]*invoice_entity.Invoice, map[string]*bill_entity.Bill, *internal_error.InternalError) {
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	billsById := make(map[string]*bill_entity.Bill)
+	for _, bill := range bills {
+		billsById[bill.Id] = bill
+	}
+
+	return invoices, billsById, nil
+}
+
+func newReconciliationOutputDTO(transactions int) *ReconciliationOutputDTO {
+	return &ReconciliationOutputDTO{
+		Transactions: transactions,
+		Reconciled:   make([]*ReconciledTransactionOutputDTO, 0),
+		Ambiguous:    make([]*AmbiguousTransactionOutputDTO, 0),
+		Unmatched:    make([]*TransactionOutputDTO, 0),
+	}
+}
+
+// payment is a debit to be matched to an invoice, read from a statement or from an email.
+type payment struct {
+	transaction TransactionOutputDTO
+	date        time.Time
+	amount      money.Money
+	method      invoice_entity.PaymentMethod
+	notes       string
+	emailSource *invoice_entity.EmailSource
+}
+
+// reconcile registers the payment on the invoice it confidently matches, or adds it to the
+// ambiguous or unmatched ones in output.
+func (u *ReconciliationUseCase) reconcile(
+	ctx context.Context,
+	invoices []*invoice_entity.Invoice,
+	billsById map[string]*bill_entity.Bill,
+	output *ReconciliationOutputDTO,
+	p *payment) *internal_error.InternalError {
+
+	candidates := findCandidates(invoices, billsById, p)
+
+	switch {
+	case len(candidates) == 0:
+		output.Unmatched = append(output.Unmatched, &p.transaction)
+	case isConfident(candidates):
+		invoice := candidates[0].invoice
+
+		var registered *invoice_entity.Payment
+		var err *internal_error.InternalError
+		if invoice.UnconfirmedPayment() != nil {
+			// the automatic debit or card charge assumed on the due date happened
+			registered, err = invoice.ConfirmAutomaticPayment(p.date.Format("2006-01-02"), p.amount, p.transaction.Id)
+		} else {
+			registered, err = invoice.AddPayment(p.date.Format("2006-01-02"), p.amount, p.method.Name(), p.notes,
+				p.transaction.Id)
+		}
+		if err != nil {
+			return err
+		}
+		registered.EmailSource = p.emailSource
+
+		if err := u.invoiceRepository.UpdateInvoice(ctx, invoice); err != nil {
+			return err
+		}
+
+		log.Printf("Transaction %s reconciled with invoice %s", p.transaction.Id, invoice.Id)
+
+		output.Reconciled = append(output.Reconciled, &ReconciledTransactionOutputDTO{
+			Transaction: p.transaction,
+			Invoice:     candidates[0].output,
+		})
+	default:
+		ambiguous := &AmbiguousTransactionOutputDTO{
+			Transaction: p.transaction,
+			Candidates:  make([]*ReconciliationCandidateOutputDTO, len(candidates)),
+		}
+		for i, c := range candidates {
+			ambiguous.Candidates[i] = c.output
+		}
+		output.Ambiguous = append(output.Ambiguous, ambiguous)
+	}
+
+	return nil
+}
+
+func isAlreadyReconciled(invoices []*invoice_entity.Invoice, reference string) bool {
+	for _, invoice := range invoices {
+		if invoice.HasPaymentReference(reference) {
+			return true
+		}
+	}
+	return false
+}
+
+type candidate struct {
+	invoice *invoice_entity.Invoice
+	output  *ReconciliationCandidateOutputDTO
+}
+
+// findCandidates returns the open invoices with the paid amount due near the payment date, and
+// the invoices paid automatically whose charge was not confirmed yet, best name match first. The
+// invoice with the paid barcode is the best match whatever its due date.
+func findCandidates(
+	invoices []*invoice_entity.Invoice,
+	billsById map[string]*bill_entity.Bill,
+	p *payment) []*candidate {
+
+	candidates := make([]*candidate, 0)
+
+	for _, invoice := range invoices {
+		balance := invoice.OutstandingBalance()
+		if unconfirmed := invoice.UnconfirmedPayment(); unconfirmed != nil {
+			balance = unconfirmed.Amount
+		} else if invoice.Status == invoice_entity.Paid {
+			continue
+		}
+
+		if balance != p.amount {
+			continue
+		}
+
+		barcodeMatch := p.transaction.Barcode != "" && invoice.Barcode == p.transaction.Barcode
+
+		dueDate, err := time.Parse("2006-01-02", invoice.DueDate)
+		if err != nil {
+			continue
+		}
+		if !barcodeMatch && (dueDate.Before(p.date.AddDate(0, 0, -DAYS_BEFORE_DEBIT)) ||
+			dueDate.After(p.date.AddDate(0, 0, DAYS_AFTER_DEBIT))) {
+			continue
+		}
+
+		output := &ReconciliationCandidateOutputDTO{
+			InvoiceId:          invoice.Id,
+			BillId:             invoice.BillId,
+			DueDate:            invoice.DueDate,
+			OutstandingBalance: balance,
+		}
+
+		if bill, found := billsById[invoice.BillId]; found {
+			output.BillName = bill.Name
+			output.Score = nameSimilarity(p.transaction.Description, bill.Name+" "+bill.Company)
+		}
+		if barcodeMatch {
+			output.Score = BARCODE_MATCH_SCORE
+		}
+
+		candidates = append(candidates, &candidate{invoice: invoice, output: output})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b *candidate) int {
+		switch {
+		case a.output.Score > b.output.Score:
+			return -1
+		case a.output.Score < b.output.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return candidates
+}
+
+func isConfident(candidates []*candidate) bool {
+	if candidates[0].output.Score < MIN_NAME_SIMILARITY {
+		return false
+	}
+	return len(candidates) == 1 || candidates[0].output.Score-candidates[1].output.Score >= MIN_SCORE_MARGIN
+}
+
+// nameSimilarity is the share of the payee words found in the description. Words match when one
+// is a prefix of the other, since banks truncate names ("SANEAMENTO" -> "SANEAM").
+func nameSimilarity(description string, payee string) float64 {
+	descriptionWords := strings.Fields(statement_parser.NormalizeText(description))
+
+	payeeWords := make([]string, 0)
+	for _, word := range strings.Fields(statement_parser.NormalizeText(payee)) {
+		if len(word) >= 3 && !slices.Contains(ignoredNameWords, word) && !slices.Contains(payeeWords, word) {
+			payeeWords = append(payeeWords, word)
+		}
+	}
+
+	if len(payeeWords) == 0 {
+		return 0
+	}
+
+	found := 0
+	for _, payeeWord := range payeeWords {
+		for _, descriptionWord := range descriptionWords {
+			if len(descriptionWord) >= 3 &&
+				(strings.HasPrefix(payeeWord, descriptionWord) || strings.HasPrefix(descriptionWord, payeeWord)) {
+				found++
+				break
+			}
+		}
+	}
+
+	return math.Round(float64(found)/float64(len(payeeWords))*100) / 100
+}
+
+func guessPaymentMethod(transaction *statement_parser.StatementTransaction) invoice_entity.PaymentMethod {
+	description := statement_parser.NormalizeText(transaction.Description)
+
+	switch {
+	case strings.Contains(description, "pix"):
+		return invoice_entity.Pix
+	case strings.Contains(description, "deb aut") || strings.Contains(description, "debito automatico") ||
+		strings.EqualFold(transaction.Type, "DIRECTDEBIT"):
+		return invoice_entity.DebitoAutomatico
+	case strings.Contains(description, "cartao"):
+		return invoice_entity.Cartao
+	default:
+		return invoice_entity.Boleto
+	}
+}
@@ -0,0 +1,29 @@
+package reconciliation_usecase
+
+import "testing"
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		payee       string
+		want        float64
+	}{
+		{name: "same name", description: "CORSAN", payee: "Corsan", want: 1},
+		{name: "accents and punctuation", description: "DEB AUT CPFL ENERGIA", payee: "CPFL Energia S.A.", want: 1},
+		{name: "truncated by the bank", description: "PAGTO CIA RIOGRANDENSE SANEAM", payee: "Companhia Riograndense de Saneamento", want: 0.67},
+		{name: "truncated payee", description: "PAGAMENTO ENERGIA ELETRICA", payee: "Energ", want: 1},
+		{name: "repeated payee words count once", description: "AGUA", payee: "Água Água Limpa", want: 0.5},
+		{name: "short description words are ignored", description: "SA PAGTO", payee: "Saneamento", want: 0},
+		{name: "only ignored payee words", description: "CIA LTDA", payee: "Cia Ltda ME", want: 0},
+		{name: "no common words", description: "PIX ENVIADO JOAO", payee: "Condomínio Edifício Aurora", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nameSimilarity(tt.description, tt.payee); got != tt.want {
+				t.Errorf("nameSimilarity(%q, %q) = %v, want %v", tt.description, tt.payee, got, tt.want)
+			}
+		})
+	}
+}
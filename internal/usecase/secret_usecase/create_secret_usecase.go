@@ -0,0 +1,77 @@
+package secret_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/secret_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type SecretInputDTO struct {
+	OwnerId string `json:"ownerId" binding:"required"`
+	Name    string `json:"name" binding:"required,min=3"`
+	Value   string `json:"value" binding:"required"`
+}
+
+type CreateSecretOutputDTO struct {
+	Id string `json:"id"`
+}
+
+type SecretUseCaseInterface interface {
+	CreateSecret(
+		ctx context.Context,
+		secretInput SecretInputDTO) (*CreateSecretOutputDTO, *internal_error.InternalError)
+	UpdateSecret(
+		ctx context.Context,
+		id string,
+		secretInput UpdateSecretInputDTO) *internal_error.InternalError
+	DeleteSecret(
+		ctx context.Context,
+		id string) *internal_error.InternalError
+	FindSecretById(
+		ctx context.Context,
+		id string) (*SecretOutputDTO, *internal_error.InternalError)
+	FindSecrets(
+		ctx context.Context,
+		ownerId string) ([]*SecretOutputDTO, *internal_error.InternalError)
+	RotateSecrets(
+		ctx context.Context) (*RotateSecretsOutputDTO, *internal_error.InternalError)
+}
+
+type SecretUseCase struct {
+	secretRepository          secret_entity.SecretRepositoryInterface
+	reencryptableRepositories []secret_entity.ReencryptableRepositoryInterface
+}
+
+func NewSecretUseCase(
+	secretRepository secret_entity.SecretRepositoryInterface,
+	reencryptableRepositories ...secret_entity.ReencryptableRepositoryInterface) SecretUseCaseInterface {
+	return &SecretUseCase{
+		secretRepository:          secretRepository,
+		reencryptableRepositories: reencryptableRepositories,
+	}
+}
+
+func (u *SecretUseCase) CreateSecret(
+	ctx context.Context,
+	secretInput SecretInputDTO) (*CreateSecretOutputDTO, *internal_error.InternalError) {
+
+	if scope, found := household_entity.ScopeFromContext(ctx); !found || !scope.HasMember(secretInput.OwnerId) {
+		return nil, internal_error.NewBadRequestError("invalid secret object. owner is not a member of the household",
+			internal_error.Cause{Field: "ownerId", Message: "user is not a member of the household"})
+	}
+
+	secret, err := secret_entity.CreateSecret(secretInput.OwnerId, secretInput.Name, secretInput.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.secretRepository.CreateSecret(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	return &CreateSecretOutputDTO{
+		Id: secret.Id,
+	}, nil
+}
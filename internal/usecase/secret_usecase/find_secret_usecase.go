@@ -0,0 +1,55 @@
+package secret_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// SecretOutputDTO intentionally has no value field: secrets never leave the server.
+type SecretOutputDTO struct {
+	Id        string    `json:"id"`
+	OwnerId   string    `json:"ownerId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt" time_format:"2006-01-02 15:04:05"`
+	UpdatedAt time.Time `json:"updatedAt" time_format:"2006-01-02 15:04:05"`
+}
+
+func (u *SecretUseCase) FindSecretById(
+	ctx context.Context, id string) (*SecretOutputDTO, *internal_error.InternalError) {
+	secretEntity, err := u.secretRepository.FindSecretById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretOutputDTO{
+		Id:        secretEntity.Id,
+		OwnerId:   secretEntity.OwnerId,
+		Name:      secretEntity.Name,
+		CreatedAt: secretEntity.CreatedAt,
+		UpdatedAt: secretEntity.UpdatedAt,
+	}, nil
+}
+
+func (u *SecretUseCase) FindSecrets(
+	ctx context.Context,
+	ownerId string) ([]*SecretOutputDTO, *internal_error.InternalError) {
+	secretEntities, err := u.secretRepository.FindSecrets(ctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	secretOutputs := make([]*SecretOutputDTO, len(secretEntities))
+	for i, value := range secretEntities {
+		secretOutputs[i] = &SecretOutputDTO{
+			Id:        value.Id,
+			OwnerId:   value.OwnerId,
+			Name:      value.Name,
+			CreatedAt: value.CreatedAt,
+			UpdatedAt: value.UpdatedAt,
+		}
+	}
+
+	return secretOutputs, nil
+}
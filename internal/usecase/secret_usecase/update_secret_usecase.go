@@ -0,0 +1,74 @@
+package secret_usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+type UpdateSecretInputDTO struct {
+	Value string `json:"value" binding:"required"`
+}
+
+type RotateSecretsOutputDTO struct {
+	Reencrypted uint `json:"reencrypted"`
+}
+
+func (u *SecretUseCase) UpdateSecret(
+	ctx context.Context,
+	id string,
+	secretInput UpdateSecretInputDTO) *internal_error.InternalError {
+
+	secretEntity, err := u.secretRepository.FindSecretById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := secretEntity.Update(secretInput.Value); err != nil {
+		return err
+	}
+
+	return u.secretRepository.UpdateSecret(ctx, secretEntity)
+}
+
+func (u *SecretUseCase) DeleteSecret(
+	ctx context.Context,
+	id string) *internal_error.InternalError {
+
+	if _, err := u.secretRepository.FindSecretById(ctx, id); err != nil {
+		return err
+	}
+
+	return u.secretRepository.DeleteSecret(ctx, id)
+}
+
+// RotateSecrets encrypts again, under the current master key, every value still encrypted
+// with a previous key (or stored before encryption was enabled). Requests only rotate the values
+// of their household, and only its owners can do it. Unscoped contexts rotate every value.
+func (u *SecretUseCase) RotateSecrets(
+	ctx context.Context) (*RotateSecretsOutputDTO, *internal_error.InternalError) {
+
+	if !household_entity.IsUnscoped(ctx) {
+		if scope, found := household_entity.ScopeFromContext(ctx); !found || scope.Role != household_entity.Owner {
+			return nil, internal_error.NewForbiddenError("only the household owners can rotate the secrets")
+		}
+	}
+
+	var total uint
+
+	for _, repository := range u.reencryptableRepositories {
+		count, err := repository.ReencryptSecrets(ctx)
+		total += count
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Secrets reencrypted: %d", total)
+
+	return &RotateSecretsOutputDTO{
+		Reencrypted: total,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package settlement_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/bill_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/invoice_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/settlement_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
+)
+
+type SettlementOutputDTO struct {
+	Period    string               `json:"period"`
+	Balances  []*BalanceOutputDTO  `json:"balances"`
+	Transfers []*TransferOutputDTO `json:"transfers"`
+}
+
+// BalanceOutputDTO is what the user paid and owed in the period. A positive net is to be
+// received and a negative one to be paid.
+type BalanceOutputDTO struct {
+	UserId   string      `json:"userId"`
+	UserName string      `json:"userName,omitempty"`
+	Paid     money.Money `json:"paid"`
+	Owed     money.Money `json:"owed"`
+	Net      money.Money `json:"net"`
+}
+
+type TransferOutputDTO struct {
+	FromUserId   string      `json:"fromUserId"`
+	FromUserName string      `json:"fromUserName,omitempty"`
+	ToUserId     string      `json:"toUserId"`
+	ToUserName   string      `json:"toUserName,omitempty"`
+	Amount       money.Money `json:"amount"`
+}
+
+type SettlementUseCaseInterface interface {
+	FindSettlement(
+		ctx context.Context,
+		period string) (*SettlementOutputDTO, *internal_error.InternalError)
+}
+
+type SettlementUseCase struct {
+	billRepository    bill_entity.BillRepositoryInterface
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface
+	userRepository    user_entity.UserRepositoryInterface
+}
+
+func NewSettlementUseCase(
+	billRepository bill_entity.BillRepositoryInterface,
+	invoiceRepository invoice_entity.InvoiceRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) SettlementUseCaseInterface {
+	return &SettlementUseCase{
+		billRepository:    billRepository,
+		invoiceRepository: invoiceRepository,
+		userRepository:    userRepository,
+	}
+}
+
+// FindSettlement computes who owes whom for the invoices paid in the competence period (YYYY-MM).
+func (u *SettlementUseCase) FindSettlement(
+	ctx context.Context,
+	period string) (*SettlementOutputDTO, *internal_error.InternalError) {
+
+	if _, e := time.Parse("2006-01", period); e != nil {
+		return nil, internal_error.NewBadRequestError("invalid period. expected format YYYY-MM",
+			internal_error.Cause{Field: "period", Message: "period must be in the format YYYY-MM"})
+	}
+
+	bills, err := u.billRepository.FindBills(ctx, bill_entity.BillFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := u.invoiceRepository.FindInvoices(ctx, invoice_entity.InvoiceFilter{FromPeriod: period, ToPeriod: period})
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := settlement_entity.CalculateSettlement(period, bills, invoices)
+
+	// only the users in the settlement are named
+	userIds := make([]string, len(settlement.Balances))
+	for i, balance := range settlement.Balances {
+		userIds[i] = balance.UserId
+	}
+
+	users, err := u.userRepository.FindUsersByIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	userNames := make(map[string]string)
+	for _, user := range users {
+		userNames[user.Id] = user.Name
+	}
+
+	output := &SettlementOutputDTO{
+		Period:    settlement.Period,
+		Balances:  make([]*BalanceOutputDTO, len(settlement.Balances)),
+		Transfers: make([]*TransferOutputDTO, len(settlement.Transfers)),
+	}
+
+	for i, balance := range settlement.Balances {
+		output.Balances[i] = &BalanceOutputDTO{
+			UserId:   balance.UserId,
+			UserName: userNames[balance.UserId],
+			Paid:     balance.Paid,
+			Owed:     balance.Owed,
+			Net:      balance.Net(),
+		}
+	}
+
+	for i, transfer := range settlement.Transfers {
+		output.Transfers[i] = &TransferOutputDTO{
+			FromUserId:   transfer.FromUserId,
+			FromUserName: userNames[transfer.FromUserId],
+			ToUserId:     transfer.ToUserId,
+			ToUserName:   userNames[transfer.ToUserId],
+			Amount:       transfer.Amount,
+		}
+	}
+
+	return output, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/table_value_source_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+	"github.com/regismartiny/lembrador-contas-go/internal/money"
 )
 
 type TableValueSourceInputDTO struct {
@@ -15,7 +16,7 @@ type TableValueSourceInputDTO struct {
 
 type TableValueSourceDataDTO struct {
 	Period TableValueSourceDataPeriodDTO `json:"period"`
-	Amount float64                       `json:"amount"`
+	Amount money.Money                   `json:"amount"`
 }
 
 type TableValueSourceDataPeriodDTO struct {
@@ -9,8 +9,9 @@ import (
 )
 
 type UserInputDTO struct {
-	Name  string `json:"name" binding:"required,min=1"`
-	Email string `json:"email" binding:"required,min=5"`
+	Name     string `json:"name" binding:"required,min=1"`
+	Email    string `json:"email" binding:"required,min=5"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
 }
 
 type CreateUserOutputDTO struct {
@@ -33,6 +34,11 @@ type UserUseCaseInterface interface {
 		ctx context.Context,
 		status user_entity.UserStatus,
 		name, email string) ([]*UserOutputDTO, *internal_error.InternalError)
+	UpdateUserPassword(
+		ctx context.Context,
+		callerId string,
+		id string,
+		passwordInput UserPasswordInputDTO) *internal_error.InternalError
 }
 
 type UserUseCase struct {
@@ -50,7 +56,7 @@ func (u *UserUseCase) CreateUser(
 	ctx context.Context,
 	userInput UserInputDTO) *internal_error.InternalError {
 
-	user, err := user_entity.CreateUser(userInput.Name, userInput.Email)
+	user, err := user_entity.CreateUser(userInput.Name, userInput.Email, userInput.Password)
 	if err != nil {
 		return err
 	}
@@ -2,8 +2,10 @@ package user_usecase
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/regismartiny/lembrador-contas-go/internal/entity/household_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/entity/user_entity"
 	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
 )
@@ -23,8 +25,13 @@ type UserOutputDTO struct {
 	UpdatedAt time.Time `json:"updated_at" time_format:"2006-01-02 15:04:05"`
 }
 
+// FindUserById returns the user when it is a member of the household of the request.
 func (u *UserUseCase) FindUserById(
 	ctx context.Context, id string) (*UserOutputDTO, *internal_error.InternalError) {
+	if scope, found := household_entity.ScopeFromContext(ctx); !found || !scope.HasMember(id) {
+		return nil, internal_error.NewNotFoundError(fmt.Sprintf("User not found with this id = %s", id))
+	}
+
 	userEntity, err := u.userRepository.FindUserById(ctx, id)
 	if err != nil {
 		return nil, err
@@ -40,6 +47,7 @@ func (u *UserUseCase) FindUserById(
 	}, nil
 }
 
+// FindUsers returns the members of the household of the request.
 func (u *UserUseCase) FindUsers(
 	ctx context.Context,
 	status user_entity.UserStatus,
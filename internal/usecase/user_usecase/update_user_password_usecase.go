@@ -0,0 +1,40 @@
+package user_usecase
+
+import (
+	"context"
+
+	"github.com/regismartiny/lembrador-contas-go/internal/internal_error"
+)
+
+// UserPasswordInputDTO changes the password of the user. The users created before authentication
+// existed define the first password with a password setup token instead (POST /auth/password-setup).
+type UserPasswordInputDTO struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	Password        string `json:"password" binding:"required,min=8,max=72"`
+}
+
+func (u *UserUseCase) UpdateUserPassword(
+	ctx context.Context,
+	callerId string,
+	id string,
+	passwordInput UserPasswordInputDTO) *internal_error.InternalError {
+
+	if callerId != id {
+		return internal_error.NewForbiddenError("users can only change their own password")
+	}
+
+	user, err := u.userRepository.FindUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.CheckPassword(passwordInput.CurrentPassword) {
+		return internal_error.NewUnauthorizedError("invalid current password")
+	}
+
+	if err := user.SetPassword(passwordInput.Password); err != nil {
+		return err
+	}
+
+	return u.userRepository.UpdateUser(ctx, user)
+}